@@ -9,25 +9,57 @@ export type { OpenAPIConfig } from './core/OpenAPI';
 
 export type { AIAvatarVideo } from './models/AIAvatarVideo';
 export type { AIAvatarVideosResponse } from './models/AIAvatarVideosResponse';
+export type { BrandKit } from './models/BrandKit';
+export type { BrandKitBackground } from './models/BrandKitBackground';
+export type { BrandKitFont } from './models/BrandKitFont';
+export type { BrandKitLogo } from './models/BrandKitLogo';
+export type { BrandKitRequest } from './models/BrandKitRequest';
+export type { BrandKitsResponse } from './models/BrandKitsResponse';
+export { CaptionOptions } from './models/CaptionOptions';
+export type { ClipOptions } from './models/ClipOptions';
 export type { CheckoutSessionResponse } from './models/CheckoutSessionResponse';
 export type { CreateCheckoutSessionRequest } from './models/CreateCheckoutSessionRequest';
 export type { CreateCustomerPortalRequest } from './models/CreateCustomerPortalRequest';
+export type { CreateRenderBatchRequest } from './models/CreateRenderBatchRequest';
 export type { CreateUserGeneratedVideoRequest } from './models/CreateUserGeneratedVideoRequest';
 export type { CustomerPortalResponse } from './models/CustomerPortalResponse';
+export type { EndCard } from './models/EndCard';
 export type { ErrorResponse } from './models/ErrorResponse';
+export type { GeneratedHookEvent } from './models/GeneratedHookEvent';
 export type { GenerateHooksRequest } from './models/GenerateHooksRequest';
 export type { GenerateHooksResponse } from './models/GenerateHooksResponse';
 export type { GetHooksResponse } from './models/GetHooksResponse';
 export type { HealthResponse } from './models/HealthResponse';
 export type { Hook } from './models/Hook';
+export type { HookGenerationError } from './models/HookGenerationError';
+export type { HookGenerationSummary } from './models/HookGenerationSummary';
+export { OutputProfile } from './models/OutputProfile';
+export type { OverlayBackground } from './models/OverlayBackground';
+export { OverlayStyle } from './models/OverlayStyle';
+export type { PromptTemplate } from './models/PromptTemplate';
+export type { PromptTemplateRequest } from './models/PromptTemplateRequest';
+export type { PromptTemplatesResponse } from './models/PromptTemplatesResponse';
+export { PromptTemplateStyle } from './models/PromptTemplateStyle';
+export type { PromptTemplateVersion } from './models/PromptTemplateVersion';
+export type { PromptTemplateVersionsResponse } from './models/PromptTemplateVersionsResponse';
+export type { RefineHookRequest } from './models/RefineHookRequest';
+export { RenderBatch } from './models/RenderBatch';
+export type { RenderBatchesResponse } from './models/RenderBatchesResponse';
+export type { RenderBatchResponse } from './models/RenderBatchResponse';
+export { SubtitleMode } from './models/SubtitleMode';
 export type { UserAccount } from './models/UserAccount';
 export { UserGeneratedVideo } from './models/UserGeneratedVideo';
 export type { UserGeneratedVideoResponse } from './models/UserGeneratedVideoResponse';
+export type { UserGeneratedVideoVariant } from './models/UserGeneratedVideoVariant';
 export type { UserGeneratedVideosResponse } from './models/UserGeneratedVideosResponse';
 
+export { AdminService } from './services/AdminService';
 export { AiAvatarService } from './services/AiAvatarService';
+export { BrandKitsService } from './services/BrandKitsService';
 export { HealthService } from './services/HealthService';
 export { HooksService } from './services/HooksService';
+export { PromptTemplatesService } from './services/PromptTemplatesService';
+export { RenderBatchesService } from './services/RenderBatchesService';
 export { SubscriptionsService } from './services/SubscriptionsService';
 export { UserGeneratedVideosService } from './services/UserGeneratedVideosService';
 export { UsersService } from './services/UsersService';
@@ -0,0 +1,48 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { BrandKitBackground } from './BrandKitBackground';
+import type { BrandKitFont } from './BrandKitFont';
+import type { BrandKitLogo } from './BrandKitLogo';
+/**
+ * Saved font, colours, logo and background image. A render that names the kit in brand_kit_id uses its font and colours wherever its style and captions leave them unset, and its end card can use the logo and background image.
+ */
+export type BrandKit = {
+    /**
+     * Unique identifier for the brand kit
+     */
+    id: string;
+    /**
+     * Name of the brand kit
+     */
+    name: string;
+    /**
+     * Overlay text colour (omitted for the default)
+     */
+    font_color?: string;
+    /**
+     * Overlay text outline colour (omitted for the default)
+     */
+    stroke_color?: string;
+    /**
+     * Colour of the box behind the overlay text (omitted for no box)
+     */
+    background_color?: string;
+    /**
+     * Colour of the current word in animated captions (omitted for the default)
+     */
+    highlight_color?: string;
+    font?: BrandKitFont;
+    logo?: BrandKitLogo;
+    background?: BrandKitBackground;
+    /**
+     * When the brand kit was created
+     */
+    created_at: string;
+    /**
+     * When the brand kit was last updated
+     */
+    updated_at: string;
+};
+
@@ -0,0 +1,18 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * The background image uploaded to a brand kit, used by end cards
+ */
+export type BrandKitBackground = {
+    /**
+     * Size of the image in bytes
+     */
+    size_bytes: number;
+    /**
+     * Signed CloudFront URL for the image (expires after 24 hours)
+     */
+    url: string;
+};
+
@@ -0,0 +1,26 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * The font uploaded to a brand kit
+ */
+export type BrandKitFont = {
+    /**
+     * Family name read from the font file
+     */
+    family: string;
+    /**
+     * Whether the uploader confirmed they hold a licence to use the font in videos
+     */
+    licensed: boolean;
+    /**
+     * Size of the font file in bytes
+     */
+    size_bytes: number;
+    /**
+     * Signed CloudFront URL for the font file (expires after 24 hours)
+     */
+    url: string;
+};
+
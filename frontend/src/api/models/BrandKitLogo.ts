@@ -0,0 +1,18 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * The logo uploaded to a brand kit
+ */
+export type BrandKitLogo = {
+    /**
+     * Size of the PNG in bytes
+     */
+    size_bytes: number;
+    /**
+     * Signed CloudFront URL for the logo (expires after 24 hours)
+     */
+    url: string;
+};
+
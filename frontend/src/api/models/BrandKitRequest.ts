@@ -0,0 +1,27 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+export type BrandKitRequest = {
+    /**
+     * Name of the brand kit, usually the brand or client it is for
+     */
+    name: string;
+    /**
+     * Overlay text colour as #RRGGBB or #RRGGBBAA; omit for the default
+     */
+    font_color?: string;
+    /**
+     * Overlay text outline colour as #RRGGBB or #RRGGBBAA; omit for the default
+     */
+    stroke_color?: string;
+    /**
+     * Colour of a box behind the overlay text as #RRGGBB or #RRGGBBAA; omit for no box
+     */
+    background_color?: string;
+    /**
+     * Colour of the current word in animated captions as #RRGGBB or #RRGGBBAA; omit for the default
+     */
+    highlight_color?: string;
+};
+
@@ -0,0 +1,57 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * How the overlay text is animated. Omit for a single static block of text.
+ */
+export type CaptionOptions = {
+    /**
+     * static draws the whole text for the length of the video; animated steps through it word by word or phrase by phrase
+     */
+    mode?: CaptionOptions.mode;
+    /**
+     * What each animation step covers. Phrases end at punctuation (, . ! ? ; :) and line breaks.
+     */
+    unit?: CaptionOptions.unit;
+    /**
+     * highlight shows the full text and colours the current step; reveal shows the text up to and including the current step
+     */
+    effect?: CaptionOptions.effect;
+    /**
+     * Colour of the current step in highlight mode as #RRGGBB or #RRGGBBAA
+     */
+    highlight_color?: string;
+    /**
+     * Pace of the animation when timings are not given
+     */
+    words_per_second?: number;
+    /**
+     * Explicit start time in seconds of each step, in order. Must have one entry per word or phrase; overrides words_per_second.
+     */
+    timings?: Array<number>;
+};
+export namespace CaptionOptions {
+    /**
+     * static draws the whole text for the length of the video; animated steps through it word by word or phrase by phrase
+     */
+    export enum mode {
+        STATIC = 'static',
+        ANIMATED = 'animated',
+    }
+    /**
+     * What each animation step covers. Phrases end at punctuation (, . ! ? ; :) and line breaks.
+     */
+    export enum unit {
+        WORD = 'word',
+        PHRASE = 'phrase',
+    }
+    /**
+     * highlight shows the full text and colours the current step; reveal shows the text up to and including the current step
+     */
+    export enum effect {
+        HIGHLIGHT = 'highlight',
+        REVEAL = 'reveal',
+    }
+}
+
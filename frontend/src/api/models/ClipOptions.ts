@@ -0,0 +1,23 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Which part of the AI avatar video to use and how long the render is. Omit to use the whole video. A duration shorter than the clip trims it; a longer one loops the clip, crossfading at each join.
+ *
+ */
+export type ClipOptions = {
+    /**
+     * Offset into the AI avatar video the clip starts at
+     */
+    start_seconds?: number;
+    /**
+     * Offset into the AI avatar video the clip ends at; defaults to the end of the video. The clip must be at least 1 second long.
+     */
+    end_seconds?: number;
+    /**
+     * Length of the rendered video; defaults to the length of the clip. May be at most 20 times the clip's length.
+     */
+    duration_seconds?: number;
+};
+
@@ -0,0 +1,38 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { CaptionOptions } from './CaptionOptions';
+import type { ClipOptions } from './ClipOptions';
+import type { EndCard } from './EndCard';
+import type { OutputProfile } from './OutputProfile';
+import type { OverlayStyle } from './OverlayStyle';
+import type { SubtitleMode } from './SubtitleMode';
+export type CreateRenderBatchRequest = {
+    /**
+     * AI avatar videos to render every overlay text over
+     */
+    ai_avatar_video_ids: Array<string>;
+    /**
+     * Saved hooks whose text is used as overlay text
+     */
+    hook_ids?: Array<string>;
+    /**
+     * Raw overlay texts, rendered after the hooks. At least one hook or text is required.
+     */
+    texts?: Array<string>;
+    style?: OverlayStyle;
+    captions?: CaptionOptions;
+    /**
+     * Extra platform variants to render alongside each source-resolution video
+     */
+    output_profiles?: Array<OutputProfile>;
+    subtitle_mode?: SubtitleMode;
+    clip?: ClipOptions;
+    end_card?: EndCard;
+    /**
+     * Brand kit whose font and colours are used wherever style and captions leave them unset. The kit is read when the render is queued, so later changes to it do not affect the render.
+     */
+    brand_kit_id?: string;
+};
+
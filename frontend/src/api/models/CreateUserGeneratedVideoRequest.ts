@@ -2,6 +2,12 @@
 /* istanbul ignore file */
 /* tslint:disable */
 /* eslint-disable */
+import type { CaptionOptions } from './CaptionOptions';
+import type { ClipOptions } from './ClipOptions';
+import type { EndCard } from './EndCard';
+import type { OutputProfile } from './OutputProfile';
+import type { OverlayStyle } from './OverlayStyle';
+import type { SubtitleMode } from './SubtitleMode';
 export type CreateUserGeneratedVideoRequest = {
     /**
      * ID of the AI avatar video to use as base
@@ -11,5 +17,18 @@ export type CreateUserGeneratedVideoRequest = {
      * Text to overlay on the video
      */
     overlay_text: string;
+    style?: OverlayStyle;
+    captions?: CaptionOptions;
+    /**
+     * Extra platform variants to render alongside the source-resolution video
+     */
+    output_profiles?: Array<OutputProfile>;
+    subtitle_mode?: SubtitleMode;
+    clip?: ClipOptions;
+    end_card?: EndCard;
+    /**
+     * Brand kit whose font and colours are used wherever style and captions leave them unset. The kit is read when the render is queued, so later changes to it do not affect the render.
+     */
+    brand_kit_id?: string;
 };
 
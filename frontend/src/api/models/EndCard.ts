@@ -0,0 +1,35 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * A closing call-to-action card added after the avatar clip, crossfading in over its last half second. The card matches the video's resolution, and the video's audio fades out under it. Its text uses the overlay font and colours. It needs at least a headline, subtitle, logo or background image.
+ *
+ */
+export type EndCard = {
+    /**
+     * How long the card is shown for after the clip
+     */
+    duration_seconds?: number;
+    /**
+     * Colour filling the card as #RRGGBB
+     */
+    background_color?: string;
+    /**
+     * Fill the card with the brand kit's background image instead of background_color. Needs brand_kit_id.
+     */
+    show_background_image?: boolean;
+    /**
+     * Large text in the middle of the card
+     */
+    headline?: string;
+    /**
+     * Smaller text under the headline
+     */
+    subtitle?: string;
+    /**
+     * Show the brand kit's logo above the text. Needs brand_kit_id.
+     */
+    show_logo?: boolean;
+};
+
@@ -11,5 +11,9 @@ export type GenerateHooksRequest = {
      * Number of hooks to generate
      */
     num_hooks: number;
+    /**
+     * Active prompt template to write the hooks with, at its latest version; omit for the built-in prompt
+     */
+    prompt_template_id?: string;
 };
 
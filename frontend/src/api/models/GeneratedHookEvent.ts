@@ -0,0 +1,19 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+export type GeneratedHookEvent = {
+    /**
+     * ID of the saved hook
+     */
+    id: string;
+    /**
+     * The hook text content
+     */
+    text: string;
+    /**
+     * Position of the hook in the generation, starting at 0
+     */
+    index: number;
+};
+
@@ -11,5 +11,13 @@ export type Hook = {
      * The hook text content
      */
     text: string;
+    /**
+     * The hook this hook was refined from (omitted for generated hooks, or once the parent is deleted)
+     */
+    parent_hook_id?: string;
+    /**
+     * The instruction the parent hook was refined with (omitted for generated hooks)
+     */
+    refinement_instruction?: string;
 };
 
@@ -0,0 +1,19 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+export type HookGenerationError = {
+    /**
+     * Error type
+     */
+    error: string;
+    /**
+     * Human-readable error message
+     */
+    message: string;
+    /**
+     * IDs of the hooks sent earlier in the stream that have been deleted
+     */
+    deleted_hook_ids: Array<string>;
+};
+
@@ -0,0 +1,24 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { Hook } from './Hook';
+export type HookGenerationSummary = {
+    /**
+     * ID shared by the hooks of this generation
+     */
+    generation_id: string;
+    /**
+     * The generated hooks, in order
+     */
+    hooks: Array<Hook>;
+    /**
+     * Credits charged for the generation
+     */
+    credits_used: number;
+    /**
+     * The user's credits after the generation
+     */
+    remaining_credits: number;
+};
+
@@ -0,0 +1,14 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * A named output format. tiktok and reels are 1080x1920 cropped to fill the frame, shorts is 1080x1920 letterboxed to keep the whole picture and square is 1080x1080 letterboxed.
+ *
+ */
+export enum OutputProfile {
+    TIKTOK = 'tiktok',
+    REELS = 'reels',
+    SHORTS = 'shorts',
+    SQUARE = 'square',
+}
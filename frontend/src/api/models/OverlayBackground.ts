@@ -0,0 +1,18 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * A filled box drawn behind the overlay text
+ */
+export type OverlayBackground = {
+    /**
+     * Box colour as #RRGGBB or #RRGGBBAA
+     */
+    color: string;
+    /**
+     * Space between the text and the edge of the box in pixels
+     */
+    padding?: number;
+};
+
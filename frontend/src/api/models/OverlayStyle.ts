@@ -0,0 +1,66 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { OverlayBackground } from './OverlayBackground';
+/**
+ * How the overlay text is drawn. Omitted fields use the defaults shown.
+ */
+export type OverlayStyle = {
+    /**
+     * Font from the server's font library (tiktok-display, dejavu-sans, liberation-sans or liberation-serif)
+     */
+    font_family?: string;
+    /**
+     * Font size in pixels; the text is shrunk automatically if it would wrap past max_lines
+     */
+    font_size?: number;
+    /**
+     * Most lines the text may wrap to at the frame width before the font is shrunk
+     */
+    max_lines?: number;
+    /**
+     * Text fill colour as #RRGGBB or #RRGGBBAA
+     */
+    font_color?: string;
+    /**
+     * Text outline colour as #RRGGBB or #RRGGBBAA
+     */
+    stroke_color?: string;
+    /**
+     * Text outline width in pixels (0 for no outline)
+     */
+    stroke_width?: number;
+    background?: OverlayBackground;
+    /**
+     * Where the text block sits vertically (ignored when y is set)
+     */
+    vertical_anchor?: OverlayStyle.vertical_anchor;
+    /**
+     * Custom offset of the text block from the top of the frame in pixels, overriding vertical_anchor
+     */
+    y?: number;
+    /**
+     * Horizontal alignment of the text block and its lines
+     */
+    alignment?: OverlayStyle.alignment;
+};
+export namespace OverlayStyle {
+    /**
+     * Where the text block sits vertically (ignored when y is set)
+     */
+    export enum vertical_anchor {
+        TOP = 'top',
+        CENTER = 'center',
+        BOTTOM = 'bottom',
+    }
+    /**
+     * Horizontal alignment of the text block and its lines
+     */
+    export enum alignment {
+        LEFT = 'left',
+        CENTER = 'center',
+        RIGHT = 'right',
+    }
+}
+
@@ -0,0 +1,37 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { PromptTemplateStyle } from './PromptTemplateStyle';
+export type PromptTemplate = {
+    /**
+     * Unique identifier for the prompt template
+     */
+    id: string;
+    /**
+     * Name of the template
+     */
+    name: string;
+    style: PromptTemplateStyle;
+    /**
+     * The latest version, which hook generation uses
+     */
+    version: number;
+    /**
+     * Instructions of the latest version
+     */
+    instructions: string;
+    /**
+     * Whether users can generate hooks with the template
+     */
+    active: boolean;
+    /**
+     * When the template was created
+     */
+    created_at: string;
+    /**
+     * When the template was last updated
+     */
+    updated_at: string;
+};
+
@@ -0,0 +1,22 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { PromptTemplateStyle } from './PromptTemplateStyle';
+export type PromptTemplateRequest = {
+    /**
+     * Unique name of the template shown to users
+     */
+    name: string;
+    style: PromptTemplateStyle;
+    /**
+     * Instructions and examples for the model. The output format, the user's topic and the number of hooks are added after them, so they should only describe the style.
+     *
+     */
+    instructions: string;
+    /**
+     * Whether users can generate hooks with the template
+     */
+    active?: boolean;
+};
+
@@ -0,0 +1,14 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * Style of hook a prompt template writes
+ */
+export enum PromptTemplateStyle {
+    LISTICLE = 'listicle',
+    POV = 'pov',
+    QUESTION = 'question',
+    CONTROVERSIAL = 'controversial',
+    STORYTIME = 'storytime',
+}
@@ -0,0 +1,27 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+export type PromptTemplateVersion = {
+    /**
+     * Unique identifier for the version, recorded on the hooks generated with it
+     */
+    id: string;
+    /**
+     * Version number, starting at 1
+     */
+    version: number;
+    /**
+     * The instructions of this version
+     */
+    instructions: string;
+    /**
+     * The admin who wrote this version (omitted for the starting templates)
+     */
+    created_by?: string;
+    /**
+     * When this version was written
+     */
+    created_at: string;
+};
+
@@ -0,0 +1,12 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { PromptTemplateVersion } from './PromptTemplateVersion';
+export type PromptTemplateVersionsResponse = {
+    /**
+     * Every version of the template, newest first
+     */
+    versions: Array<PromptTemplateVersion>;
+};
+
@@ -0,0 +1,9 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { PromptTemplate } from './PromptTemplate';
+export type PromptTemplatesResponse = {
+    prompt_templates: Array<PromptTemplate>;
+};
+
@@ -0,0 +1,15 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+export type RefineHookRequest = {
+    /**
+     * How the variants should differ from the hook
+     */
+    instruction: string;
+    /**
+     * Number of variants to generate
+     */
+    num_variants?: number;
+};
+
@@ -0,0 +1,56 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+export type RenderBatch = {
+    /**
+     * Unique identifier for the batch
+     */
+    id: string;
+    /**
+     * processing while any render is still running; once they have all finished, completed if every render succeeded, failed if none did and partially_completed otherwise
+     *
+     */
+    status: RenderBatch.status;
+    /**
+     * Number of renders the batch fanned out into
+     */
+    render_count: number;
+    /**
+     * Credits held for the whole batch when it was created
+     */
+    credits_charged: number;
+    /**
+     * Renders still queued or running
+     */
+    processing_count: number;
+    /**
+     * Renders that finished successfully
+     */
+    completed_count: number;
+    /**
+     * Renders that failed (their credits are refunded)
+     */
+    failed_count: number;
+    /**
+     * Renders that were cancelled (their credits are refunded)
+     */
+    cancelled_count: number;
+    /**
+     * When the batch was requested
+     */
+    created_at: string;
+};
+export namespace RenderBatch {
+    /**
+     * processing while any render is still running; once they have all finished, completed if every render succeeded, failed if none did and partially_completed otherwise
+     *
+     */
+    export enum status {
+        PROCESSING = 'processing',
+        COMPLETED = 'completed',
+        PARTIALLY_COMPLETED = 'partially_completed',
+        FAILED = 'failed',
+    }
+}
+
@@ -0,0 +1,14 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { RenderBatch } from './RenderBatch';
+import type { UserGeneratedVideo } from './UserGeneratedVideo';
+export type RenderBatchResponse = {
+    batch: RenderBatch;
+    /**
+     * Every video in the batch
+     */
+    videos: Array<UserGeneratedVideo>;
+};
+
@@ -0,0 +1,12 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { RenderBatch } from './RenderBatch';
+export type RenderBatchesResponse = {
+    /**
+     * The user's render batches, newest first
+     */
+    batches: Array<RenderBatch>;
+};
+
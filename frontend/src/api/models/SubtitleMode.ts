@@ -0,0 +1,13 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+/**
+ * How the overlay text is delivered. burned_in draws it onto the video; soft leaves the picture clean and carries the text as a subtitle track in the MP4 instead; burned_in_and_soft does both. soft and burned_in_and_soft also publish SRT and WebVTT sidecar files for platforms with native captions.
+ *
+ */
+export enum SubtitleMode {
+    BURNED_IN = 'burned_in',
+    SOFT = 'soft',
+    BURNED_IN_AND_SOFT = 'burned_in_and_soft',
+}
@@ -2,6 +2,7 @@
 /* istanbul ignore file */
 /* tslint:disable */
 /* eslint-disable */
+import type { UserGeneratedVideoVariant } from './UserGeneratedVideoVariant';
 export type UserGeneratedVideo = {
     /**
      * Unique identifier for the user-generated video
@@ -20,17 +21,49 @@ export type UserGeneratedVideo = {
      */
     overlay_text: string;
     /**
-     * CloudFront URL for the generated video
+     * Signed CloudFront URL for the generated video (only set once the video is completed)
      */
-    video_url: string;
+    video_url?: string;
     /**
-     * CloudFront URL for the video thumbnail
+     * Signed CloudFront URL for the video thumbnail (only set once the video is completed)
      */
-    thumbnail_url: string;
+    thumbnail_url?: string;
     /**
-     * Current processing status
+     * Current processing status (stays processing while a render that hit a temporary problem waits to be retried)
      */
     status: UserGeneratedVideo.status;
+    /**
+     * Why rendering failed, written for the user (only set when status is failed). Temporary problems such as storage or network errors are retried automatically and only end in failed once retries run out; either way any credits the render used are refunded.
+     */
+    error_message?: string | null;
+    /**
+     * How much of the render has been encoded (only set on single-video responses)
+     */
+    progress_percent?: number;
+    /**
+     * Estimated seconds until the render finishes (only set while processing)
+     */
+    eta_seconds?: number | null;
+    /**
+     * Platform variants rendered from the requested output profiles
+     */
+    variants?: Array<UserGeneratedVideoVariant>;
+    /**
+     * Signed CloudFront URL for the SRT sidecar (only set once completed, when the subtitle mode includes soft subtitles)
+     */
+    subtitle_srt_url?: string;
+    /**
+     * Signed CloudFront URL for the WebVTT sidecar (only set once completed, when the subtitle mode includes soft subtitles)
+     */
+    subtitle_vtt_url?: string;
+    /**
+     * The render batch the video was created in (null for single renders)
+     */
+    render_batch_id?: string | null;
+    /**
+     * Whether the video carries the free plan watermark; re-render it after upgrading for a clean copy
+     */
+    watermarked: boolean;
     /**
      * When the video was created
      */
@@ -44,6 +77,7 @@ export namespace UserGeneratedVideo {
         PROCESSING = 'processing',
         COMPLETED = 'completed',
         FAILED = 'failed',
+        CANCELLED = 'cancelled',
     }
 }
 
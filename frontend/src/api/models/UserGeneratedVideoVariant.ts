@@ -0,0 +1,21 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { OutputProfile } from './OutputProfile';
+export type UserGeneratedVideoVariant = {
+    profile: OutputProfile;
+    /**
+     * Frame width of the variant in pixels
+     */
+    width: number;
+    /**
+     * Frame height of the variant in pixels
+     */
+    height: number;
+    /**
+     * Signed CloudFront URL for the variant (only set once the video is completed)
+     */
+    video_url?: string;
+};
+
@@ -0,0 +1,133 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { PromptTemplate } from '../models/PromptTemplate';
+import type { PromptTemplateRequest } from '../models/PromptTemplateRequest';
+import type { PromptTemplatesResponse } from '../models/PromptTemplatesResponse';
+import type { PromptTemplateVersionsResponse } from '../models/PromptTemplateVersionsResponse';
+import type { CancelablePromise } from '../core/CancelablePromise';
+import { OpenAPI } from '../core/OpenAPI';
+import { request as __request } from '../core/request';
+export class AdminService {
+    /**
+     * Get all prompt templates
+     * Retrieves every hook prompt template, including inactive ones. Admin only.
+     * @returns PromptTemplatesResponse Prompt templates retrieved successfully
+     * @throws ApiError
+     */
+    public static getAdminPromptTemplates(): CancelablePromise<PromptTemplatesResponse> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: '/admin/prompt-templates',
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                403: `Forbidden - the user is not an admin`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Create a prompt template
+     * Creates a hook prompt template at version 1. Admin only.
+     * @param requestBody
+     * @returns PromptTemplate Prompt template created successfully
+     * @throws ApiError
+     */
+    public static createPromptTemplate(
+        requestBody: PromptTemplateRequest,
+    ): CancelablePromise<PromptTemplate> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: '/admin/prompt-templates',
+            body: requestBody,
+            mediaType: 'application/json',
+            errors: {
+                400: `Bad request - invalid name, style or instructions`,
+                401: `Unauthorized - invalid or missing token`,
+                403: `Forbidden - the user is not an admin`,
+                409: `A prompt template with this name already exists`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Get a prompt template
+     * Retrieves a hook prompt template at its latest version. Admin only.
+     * @param templateId The ID of the prompt template
+     * @returns PromptTemplate Prompt template retrieved successfully
+     * @throws ApiError
+     */
+    public static getPromptTemplate(
+        templateId: string,
+    ): CancelablePromise<PromptTemplate> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: '/admin/prompt-templates/{templateId}',
+            path: {
+                'templateId': templateId,
+            },
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                403: `Forbidden - the user is not an admin`,
+                404: `Prompt template not found`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Update a prompt template
+     * Replaces a hook prompt template's name, style, instructions and whether it is active. Changing the instructions adds a new version; hooks keep a reference to the version they were generated with, so earlier versions are never changed. Admin only.
+     *
+     * @param templateId The ID of the prompt template
+     * @param requestBody
+     * @returns PromptTemplate Prompt template updated successfully
+     * @throws ApiError
+     */
+    public static updatePromptTemplate(
+        templateId: string,
+        requestBody: PromptTemplateRequest,
+    ): CancelablePromise<PromptTemplate> {
+        return __request(OpenAPI, {
+            method: 'PUT',
+            url: '/admin/prompt-templates/{templateId}',
+            path: {
+                'templateId': templateId,
+            },
+            body: requestBody,
+            mediaType: 'application/json',
+            errors: {
+                400: `Bad request - invalid name, style or instructions`,
+                401: `Unauthorized - invalid or missing token`,
+                403: `Forbidden - the user is not an admin`,
+                404: `Prompt template not found`,
+                409: `A prompt template with this name already exists`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Get a prompt template's versions
+     * Retrieves every version of a hook prompt template's instructions, newest first. Admin only.
+     * @param templateId The ID of the prompt template
+     * @returns PromptTemplateVersionsResponse Prompt template versions retrieved successfully
+     * @throws ApiError
+     */
+    public static getPromptTemplateVersions(
+        templateId: string,
+    ): CancelablePromise<PromptTemplateVersionsResponse> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: '/admin/prompt-templates/{templateId}/versions',
+            path: {
+                'templateId': templateId,
+            },
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                403: `Forbidden - the user is not an admin`,
+                404: `Prompt template not found`,
+                500: `Internal server error`,
+            },
+        });
+    }
+}
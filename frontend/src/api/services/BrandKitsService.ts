@@ -0,0 +1,298 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { BrandKit } from '../models/BrandKit';
+import type { BrandKitRequest } from '../models/BrandKitRequest';
+import type { BrandKitsResponse } from '../models/BrandKitsResponse';
+import type { CancelablePromise } from '../core/CancelablePromise';
+import { OpenAPI } from '../core/OpenAPI';
+import { request as __request } from '../core/request';
+export class BrandKitsService {
+    /**
+     * Get brand kits
+     * Retrieves the authenticated user's brand kits, newest first
+     * @returns BrandKitsResponse Brand kits retrieved successfully
+     * @throws ApiError
+     */
+    public static getBrandKits(): CancelablePromise<BrandKitsResponse> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: '/brand-kits',
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Create a brand kit
+     * Creates a brand kit with a name and colour palette; upload its font and logo separately
+     * @param requestBody
+     * @returns BrandKit Brand kit created successfully
+     * @throws ApiError
+     */
+    public static createBrandKit(
+        requestBody: BrandKitRequest,
+    ): CancelablePromise<BrandKit> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: '/brand-kits',
+            body: requestBody,
+            mediaType: 'application/json',
+            errors: {
+                400: `Bad request - invalid name or colour`,
+                401: `Unauthorized - invalid or missing token`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Get a brand kit
+     * Retrieves one of the authenticated user's brand kits
+     * @param brandKitId The ID of the brand kit
+     * @returns BrandKit Brand kit retrieved successfully
+     * @throws ApiError
+     */
+    public static getBrandKit(
+        brandKitId: string,
+    ): CancelablePromise<BrandKit> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: '/brand-kits/{brandKitId}',
+            path: {
+                'brandKitId': brandKitId,
+            },
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                404: `Brand kit not found or doesn't belong to user`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Update a brand kit
+     * Replaces a brand kit's name and colour palette. Its font and logo are kept.
+     * @param brandKitId The ID of the brand kit
+     * @param requestBody
+     * @returns BrandKit Brand kit updated successfully
+     * @throws ApiError
+     */
+    public static updateBrandKit(
+        brandKitId: string,
+        requestBody: BrandKitRequest,
+    ): CancelablePromise<BrandKit> {
+        return __request(OpenAPI, {
+            method: 'PUT',
+            url: '/brand-kits/{brandKitId}',
+            path: {
+                'brandKitId': brandKitId,
+            },
+            body: requestBody,
+            mediaType: 'application/json',
+            errors: {
+                400: `Bad request - invalid name or colour`,
+                401: `Unauthorized - invalid or missing token`,
+                404: `Brand kit not found or doesn't belong to user`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Delete a brand kit
+     * Deletes a brand kit with its font and logo. Renders already queued with it are unaffected.
+     * @param brandKitId The ID of the brand kit
+     * @returns void
+     * @throws ApiError
+     */
+    public static deleteBrandKit(
+        brandKitId: string,
+    ): CancelablePromise<void> {
+        return __request(OpenAPI, {
+            method: 'DELETE',
+            url: '/brand-kits/{brandKitId}',
+            path: {
+                'brandKitId': brandKitId,
+            },
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                404: `Brand kit not found or doesn't belong to user`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Upload a brand kit font
+     * Uploads the TrueType or OpenType font overlay text is drawn in when rendering with the kit, replacing any font it already has. The font must parse, must not be restricted from embedding by its licence, and the uploader must confirm they are licensed to use it.
+     *
+     * @param brandKitId The ID of the brand kit
+     * @param formData
+     * @returns BrandKit Font uploaded successfully
+     * @throws ApiError
+     */
+    public static uploadBrandKitFont(
+        brandKitId: string,
+        formData: {
+            /**
+             * TTF or OTF font file, at most 10 MB
+             */
+            file: Blob;
+            /**
+             * Confirms the uploader holds a licence to use the font in videos; must be true
+             */
+            licensed: boolean;
+        },
+    ): CancelablePromise<BrandKit> {
+        return __request(OpenAPI, {
+            method: 'PUT',
+            url: '/brand-kits/{brandKitId}/font',
+            path: {
+                'brandKitId': brandKitId,
+            },
+            formData: formData,
+            mediaType: 'multipart/form-data',
+            errors: {
+                400: `Bad request - missing file, too large or not a TrueType or OpenType font`,
+                401: `Unauthorized - invalid or missing token`,
+                404: `Brand kit not found or doesn't belong to user`,
+                422: `The licence was not confirmed, or the font's embedding permissions forbid using it`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Remove a brand kit font
+     * Removes a brand kit's font, so renders with the kit use the default font again
+     * @param brandKitId The ID of the brand kit
+     * @returns BrandKit Font removed successfully
+     * @throws ApiError
+     */
+    public static deleteBrandKitFont(
+        brandKitId: string,
+    ): CancelablePromise<BrandKit> {
+        return __request(OpenAPI, {
+            method: 'DELETE',
+            url: '/brand-kits/{brandKitId}/font',
+            path: {
+                'brandKitId': brandKitId,
+            },
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                404: `Brand kit not found, doesn't belong to user or has no font`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Upload a brand kit logo
+     * Uploads the brand kit's logo as a PNG, replacing any logo it already has
+     * @param brandKitId The ID of the brand kit
+     * @param formData
+     * @returns BrandKit Logo uploaded successfully
+     * @throws ApiError
+     */
+    public static uploadBrandKitLogo(
+        brandKitId: string,
+        formData: {
+            /**
+             * PNG logo, at most 5 MB and 4096x4096 pixels
+             */
+            file: Blob;
+        },
+    ): CancelablePromise<BrandKit> {
+        return __request(OpenAPI, {
+            method: 'PUT',
+            url: '/brand-kits/{brandKitId}/logo',
+            path: {
+                'brandKitId': brandKitId,
+            },
+            formData: formData,
+            mediaType: 'multipart/form-data',
+            errors: {
+                400: `Bad request - missing file, too large or not a PNG`,
+                401: `Unauthorized - invalid or missing token`,
+                404: `Brand kit not found or doesn't belong to user`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Remove a brand kit logo
+     * Removes a brand kit's logo
+     * @param brandKitId The ID of the brand kit
+     * @returns BrandKit Logo removed successfully
+     * @throws ApiError
+     */
+    public static deleteBrandKitLogo(
+        brandKitId: string,
+    ): CancelablePromise<BrandKit> {
+        return __request(OpenAPI, {
+            method: 'DELETE',
+            url: '/brand-kits/{brandKitId}/logo',
+            path: {
+                'brandKitId': brandKitId,
+            },
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                404: `Brand kit not found, doesn't belong to user or has no logo`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Upload a brand kit background image
+     * Uploads the brand kit's background image as a PNG or JPEG, replacing any it already has. End cards can use it in place of a solid colour.
+     * @param brandKitId The ID of the brand kit
+     * @param formData
+     * @returns BrandKit Background image uploaded successfully
+     * @throws ApiError
+     */
+    public static uploadBrandKitBackground(
+        brandKitId: string,
+        formData: {
+            /**
+             * PNG or JPEG image, at most 10 MB and 4096x4096 pixels
+             */
+            file: Blob;
+        },
+    ): CancelablePromise<BrandKit> {
+        return __request(OpenAPI, {
+            method: 'PUT',
+            url: '/brand-kits/{brandKitId}/background',
+            path: {
+                'brandKitId': brandKitId,
+            },
+            formData: formData,
+            mediaType: 'multipart/form-data',
+            errors: {
+                400: `Bad request - missing file, too large or not a PNG or JPEG`,
+                401: `Unauthorized - invalid or missing token`,
+                404: `Brand kit not found or doesn't belong to user`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Remove a brand kit background image
+     * Removes a brand kit's background image
+     * @param brandKitId The ID of the brand kit
+     * @returns BrandKit Background image removed successfully
+     * @throws ApiError
+     */
+    public static deleteBrandKitBackground(
+        brandKitId: string,
+    ): CancelablePromise<BrandKit> {
+        return __request(OpenAPI, {
+            method: 'DELETE',
+            url: '/brand-kits/{brandKitId}/background',
+            path: {
+                'brandKitId': brandKitId,
+            },
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                404: `Brand kit not found, doesn't belong to user or has no background image`,
+                500: `Internal server error`,
+            },
+        });
+    }
+}
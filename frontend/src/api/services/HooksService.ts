@@ -5,6 +5,7 @@
 import type { GenerateHooksRequest } from '../models/GenerateHooksRequest';
 import type { GenerateHooksResponse } from '../models/GenerateHooksResponse';
 import type { GetHooksResponse } from '../models/GetHooksResponse';
+import type { RefineHookRequest } from '../models/RefineHookRequest';
 import type { CancelablePromise } from '../core/CancelablePromise';
 import { OpenAPI } from '../core/OpenAPI';
 import { request as __request } from '../core/request';
@@ -27,6 +28,36 @@ export class HooksService {
             errors: {
                 400: `Bad request - invalid request data or insufficient credits`,
                 401: `Unauthorized - invalid or missing token`,
+                404: `Prompt template not found or inactive`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Generate hooks for TikTok slideshow as a stream
+     * Generates hooks like generateHooks, but streams them as Server-Sent Events as soon as the
+     * model writes them. Each `hook` event holds a GeneratedHookEvent for a hook that has already
+     * been saved. A final `done` event holds a HookGenerationSummary, or an `error` event holds a
+     * HookGenerationError if generation fails after the stream has started; the credits are then
+     * refunded and the hooks already sent are deleted, with their IDs listed in the error so the
+     * client can drop them.
+     *
+     * @param requestBody
+     * @returns string Stream of hook generation events
+     * @throws ApiError
+     */
+    public static generateHooksStream(
+        requestBody: GenerateHooksRequest,
+    ): CancelablePromise<string> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: '/hooks/generate/stream',
+            body: requestBody,
+            mediaType: 'application/json',
+            errors: {
+                400: `Bad request - invalid request data or insufficient credits`,
+                401: `Unauthorized - invalid or missing token`,
+                404: `Prompt template not found or inactive`,
                 500: `Internal server error`,
             },
         });
@@ -93,6 +124,35 @@ export class HooksService {
             },
         });
     }
+    /**
+     * Refine a hook
+     * Generates variants of one of the authenticated user's hooks following an instruction such as "shorter" or "more urgent", written for the original hook's prompt in the style of the prompt template version it was generated with. The variants are saved as hooks whose parent_hook_id is the refined hook, so refining them in turn builds a tree.
+     *
+     * @param hookId The ID of the hook to refine
+     * @param requestBody
+     * @returns GenerateHooksResponse Hook variants generated successfully
+     * @throws ApiError
+     */
+    public static refineHook(
+        hookId: string,
+        requestBody: RefineHookRequest,
+    ): CancelablePromise<GenerateHooksResponse> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: '/hooks/{hookId}/refine',
+            path: {
+                'hookId': hookId,
+            },
+            body: requestBody,
+            mediaType: 'application/json',
+            errors: {
+                400: `Bad request - invalid request data or insufficient credits`,
+                401: `Unauthorized - invalid or missing token`,
+                404: `Hook not found or doesn't belong to user`,
+                500: `Internal server error`,
+            },
+        });
+    }
     /**
      * Delete a hook
      * Deletes a specific hook by ID (only if it belongs to the authenticated user)
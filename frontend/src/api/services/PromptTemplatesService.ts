@@ -0,0 +1,26 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { PromptTemplatesResponse } from '../models/PromptTemplatesResponse';
+import type { CancelablePromise } from '../core/CancelablePromise';
+import { OpenAPI } from '../core/OpenAPI';
+import { request as __request } from '../core/request';
+export class PromptTemplatesService {
+    /**
+     * Get prompt templates
+     * Retrieves the active hook prompt templates, which can be passed to hook generation as prompt_template_id
+     * @returns PromptTemplatesResponse Prompt templates retrieved successfully
+     * @throws ApiError
+     */
+    public static getPromptTemplates(): CancelablePromise<PromptTemplatesResponse> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: '/prompt-templates',
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                500: `Internal server error`,
+            },
+        });
+    }
+}
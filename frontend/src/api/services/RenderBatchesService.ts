@@ -0,0 +1,85 @@
+/* generated using openapi-typescript-codegen -- do not edit */
+/* istanbul ignore file */
+/* tslint:disable */
+/* eslint-disable */
+import type { CreateRenderBatchRequest } from '../models/CreateRenderBatchRequest';
+import type { RenderBatchesResponse } from '../models/RenderBatchesResponse';
+import type { RenderBatchResponse } from '../models/RenderBatchResponse';
+import type { CancelablePromise } from '../core/CancelablePromise';
+import { OpenAPI } from '../core/OpenAPI';
+import { request as __request } from '../core/request';
+export class RenderBatchesService {
+    /**
+     * Get render batches
+     * Retrieves the authenticated user's render batches, newest first, with the status of each
+     * @param limit Number of batches to return
+     * @param offset Number of batches to skip
+     * @returns RenderBatchesResponse Render batches retrieved successfully
+     * @throws ApiError
+     */
+    public static getRenderBatches(
+        limit: number = 20,
+        offset?: number,
+    ): CancelablePromise<RenderBatchesResponse> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: '/render-batches',
+            query: {
+                'limit': limit,
+                'offset': offset,
+            },
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Render every hook over every avatar video
+     * Queues one user-generated video for each combination of overlay text (from hook_ids and texts) and AI avatar video, all with the same render options. Each render costs 10 credits, as for a single render. Credits for the whole batch are held up front, so the batch is either queued in full or not at all; each render's credits are refunded if it fails or is cancelled.
+     *
+     * @param requestBody
+     * @returns RenderBatchResponse Batch accepted for rendering
+     * @throws ApiError
+     */
+    public static createRenderBatch(
+        requestBody: CreateRenderBatchRequest,
+    ): CancelablePromise<RenderBatchResponse> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: '/render-batches',
+            body: requestBody,
+            mediaType: 'application/json',
+            errors: {
+                400: `Bad request - invalid input or insufficient credits for the whole batch`,
+                401: `Unauthorized - invalid or missing token`,
+                404: `A hook, AI avatar video or brand kit was not found`,
+                422: `An AI avatar video cannot be rendered (unsupported codec, frame size or duration)`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Get a render batch
+     * Retrieves a render batch with its status and every video it fanned out into
+     * @param batchId The ID of the render batch
+     * @returns RenderBatchResponse Render batch retrieved successfully
+     * @throws ApiError
+     */
+    public static getRenderBatch(
+        batchId: string,
+    ): CancelablePromise<RenderBatchResponse> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: '/render-batches/{batchId}',
+            path: {
+                'batchId': batchId,
+            },
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                404: `Batch not found or doesn't belong to user`,
+                500: `Internal server error`,
+            },
+        });
+    }
+}
@@ -27,9 +27,9 @@ export class UserGeneratedVideosService {
     }
     /**
      * Generate a video with text overlay
-     * Creates a new user-generated video by adding text overlay to an existing AI avatar video
+     * Queues a new user-generated video that adds a text overlay to an existing AI avatar video. A render identical to one the user has already completed reuses that output without re-encoding. The video is returned immediately with status `processing` and rendered in the background.
      * @param requestBody
-     * @returns UserGeneratedVideoResponse Video generated successfully
+     * @returns UserGeneratedVideoResponse Video accepted for rendering
      * @throws ApiError
      */
     public static createUserGeneratedVideo(
@@ -43,7 +43,79 @@ export class UserGeneratedVideosService {
             errors: {
                 400: `Bad request - invalid input`,
                 401: `Unauthorized - invalid or missing token`,
-                404: `AI avatar video not found`,
+                404: `AI avatar video or brand kit not found`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Get a user-generated video
+     * Retrieves a single user-generated video, including render progress while it is processing
+     * @param videoId The ID of the user-generated video
+     * @returns UserGeneratedVideoResponse User-generated video retrieved successfully
+     * @throws ApiError
+     */
+    public static getUserGeneratedVideo(
+        videoId: string,
+    ): CancelablePromise<UserGeneratedVideoResponse> {
+        return __request(OpenAPI, {
+            method: 'GET',
+            url: '/user-generated-videos/{videoId}',
+            path: {
+                'videoId': videoId,
+            },
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                404: `Video not found or doesn't belong to user`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Cancel a user-generated video render
+     * Stops a queued or running render, marks the video `cancelled` and refunds any credits held for it
+     * @param videoId The ID of the user-generated video to cancel
+     * @returns UserGeneratedVideoResponse Render cancelled
+     * @throws ApiError
+     */
+    public static cancelUserGeneratedVideo(
+        videoId: string,
+    ): CancelablePromise<UserGeneratedVideoResponse> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: '/user-generated-videos/{videoId}/cancel',
+            path: {
+                'videoId': videoId,
+            },
+            errors: {
+                401: `Unauthorized - invalid or missing token`,
+                404: `Video not found or doesn't belong to user`,
+                409: `Video has already finished rendering`,
+                500: `Internal server error`,
+            },
+        });
+    }
+    /**
+     * Re-render a user-generated video
+     * Queues a new user-generated video with the same AI avatar video, overlay text and render options as an existing one. Free plan renders are watermarked, so re-rendering after upgrading produces a clean copy.
+     * @param videoId The ID of the user-generated video to re-render
+     * @returns UserGeneratedVideoResponse New video accepted for rendering
+     * @throws ApiError
+     */
+    public static rerenderUserGeneratedVideo(
+        videoId: string,
+    ): CancelablePromise<UserGeneratedVideoResponse> {
+        return __request(OpenAPI, {
+            method: 'POST',
+            url: '/user-generated-videos/{videoId}/rerender',
+            path: {
+                'videoId': videoId,
+            },
+            errors: {
+                400: `Bad request - invalid render options`,
+                401: `Unauthorized - invalid or missing token`,
+                404: `Video or its brand kit not found or doesn't belong to user`,
+                422: `The AI avatar video can no longer be rendered (unsupported codec, frame size or duration)`,
                 500: `Internal server error`,
             },
         });
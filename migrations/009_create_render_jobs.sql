@@ -0,0 +1,36 @@
+-- Migration: Create render jobs table
+-- Description: Postgres-backed queue for rendering user-generated videos in the background
+
+-- Create the render jobs table
+CREATE TABLE public.render_jobs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_generated_video_id UUID NOT NULL REFERENCES public.user_generated_videos(id) ON DELETE CASCADE,
+  status TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued','running','completed','failed')),
+  attempts INTEGER NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+  run_after TIMESTAMPTZ NOT NULL DEFAULT now(), -- Earliest time a worker may pick the job up
+  locked_at TIMESTAMPTZ, -- Set while a worker is running the job
+  last_error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Add updated_at trigger
+CREATE TRIGGER set_updated_at_render_jobs
+BEFORE UPDATE ON public.render_jobs
+FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+-- Add indexes for performance
+CREATE INDEX idx_render_jobs_status_run_after ON public.render_jobs(status, run_after);
+CREATE INDEX idx_render_jobs_user_generated_video_id ON public.render_jobs(user_generated_video_id);
+
+-- Add comments for documentation
+COMMENT ON TABLE public.render_jobs IS 'Queue of background render jobs for user-generated videos';
+COMMENT ON COLUMN public.render_jobs.id IS 'Unique job identifier';
+COMMENT ON COLUMN public.render_jobs.user_generated_video_id IS 'The user-generated video this job renders';
+COMMENT ON COLUMN public.render_jobs.status IS 'Job status: queued, running, completed or failed';
+COMMENT ON COLUMN public.render_jobs.attempts IS 'Number of times a worker has picked up this job';
+COMMENT ON COLUMN public.render_jobs.run_after IS 'Earliest time a worker may pick up this job';
+COMMENT ON COLUMN public.render_jobs.locked_at IS 'When a worker claimed this job (null while queued)';
+COMMENT ON COLUMN public.render_jobs.last_error IS 'Error from the most recent failed attempt';
+COMMENT ON COLUMN public.render_jobs.created_at IS 'When the job was enqueued';
+COMMENT ON COLUMN public.render_jobs.updated_at IS 'When the job was last updated';
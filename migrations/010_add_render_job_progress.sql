@@ -0,0 +1,10 @@
+-- Migration: Add render job progress columns
+-- Description: Tracks percent complete and estimated time remaining for running render jobs
+
+ALTER TABLE public.render_jobs
+ADD COLUMN progress_percent DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (progress_percent >= 0 AND progress_percent <= 100),
+ADD COLUMN eta_seconds INTEGER;
+
+-- Add comments to document the columns
+COMMENT ON COLUMN public.render_jobs.progress_percent IS 'Percent of the source video encoded so far (0-100), parsed from ffmpeg -progress output';
+COMMENT ON COLUMN public.render_jobs.eta_seconds IS 'Estimated seconds until the encode finishes (null until known)';
@@ -0,0 +1,10 @@
+-- Migration: Allow render jobs to be cancelled
+-- Description: Adds the cancelled status to render_jobs so users can stop queued or running renders
+
+ALTER TABLE public.render_jobs
+DROP CONSTRAINT render_jobs_status_check;
+
+ALTER TABLE public.render_jobs
+ADD CONSTRAINT render_jobs_status_check CHECK (status IN ('queued', 'running', 'completed', 'failed', 'cancelled'));
+
+COMMENT ON COLUMN public.render_jobs.status IS 'Job status: queued, running, completed, failed or cancelled';
@@ -0,0 +1,8 @@
+-- Migration: Add render options to user-generated videos
+-- Description: Stores the per-request render settings (such as overlay style) so background workers can apply them
+
+ALTER TABLE public.user_generated_videos
+ADD COLUMN render_options JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- Add comment to document the column
+COMMENT ON COLUMN public.user_generated_videos.render_options IS 'Per-request render settings such as overlay style, as JSON';
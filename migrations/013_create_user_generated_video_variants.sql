@@ -0,0 +1,31 @@
+-- Migration: Create user-generated video variants table
+-- Description: Stores the extra renders of a user-generated video made for platform output profiles (TikTok, Reels, Shorts, square)
+
+-- Create the variants table
+CREATE TABLE public.user_generated_video_variants (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_generated_video_id UUID NOT NULL REFERENCES public.user_generated_videos(id) ON DELETE CASCADE,
+  profile TEXT NOT NULL, -- Output profile name, e.g. tiktok
+  video_filename VARCHAR(255) NOT NULL, -- S3 filename in user-generated-videos/videos/ folder
+  width INTEGER NOT NULL CHECK (width > 0),
+  height INTEGER NOT NULL CHECK (height > 0),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT user_generated_video_variants_video_profile_key UNIQUE (user_generated_video_id, profile)
+);
+
+-- Add updated_at trigger
+CREATE TRIGGER set_updated_at_user_generated_video_variants
+BEFORE UPDATE ON public.user_generated_video_variants
+FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+-- Add comments for documentation
+COMMENT ON TABLE public.user_generated_video_variants IS 'Per-platform renders of a user-generated video';
+COMMENT ON COLUMN public.user_generated_video_variants.id IS 'Unique variant identifier';
+COMMENT ON COLUMN public.user_generated_video_variants.user_generated_video_id IS 'The user-generated video this is a variant of';
+COMMENT ON COLUMN public.user_generated_video_variants.profile IS 'Name of the output profile the variant was rendered with';
+COMMENT ON COLUMN public.user_generated_video_variants.video_filename IS 'S3 filename of the rendered variant';
+COMMENT ON COLUMN public.user_generated_video_variants.width IS 'Output width in pixels';
+COMMENT ON COLUMN public.user_generated_video_variants.height IS 'Output height in pixels';
+COMMENT ON COLUMN public.user_generated_video_variants.created_at IS 'When the variant was first rendered';
+COMMENT ON COLUMN public.user_generated_video_variants.updated_at IS 'When the variant was last rendered';
@@ -0,0 +1,13 @@
+-- Migration: Add render spec hash to user-generated videos
+-- Description: Fingerprints everything that determines a render's output so identical renders can reuse an earlier result
+
+ALTER TABLE public.user_generated_videos
+ADD COLUMN render_spec_hash TEXT;
+
+-- Look up a user's completed renders by spec hash
+CREATE INDEX idx_user_generated_videos_user_render_spec_hash
+ON public.user_generated_videos (user_id, render_spec_hash)
+WHERE status = 'completed';
+
+-- Add comment to document the column
+COMMENT ON COLUMN public.user_generated_videos.render_spec_hash IS 'SHA-256 of the canonical render spec (source video, text, style, profiles and renderer version)';
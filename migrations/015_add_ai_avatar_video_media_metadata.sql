@@ -0,0 +1,26 @@
+-- Migration: Add media metadata to AI avatar videos
+-- Description: Records what ffprobe reports about each source video so renders can adapt to it and unusable sources can be rejected
+
+ALTER TABLE public.ai_avatar_videos
+ADD COLUMN width INTEGER,
+ADD COLUMN height INTEGER,
+ADD COLUMN rotation INTEGER,
+ADD COLUMN frame_rate DOUBLE PRECISION,
+ADD COLUMN video_codec TEXT,
+ADD COLUMN audio_codec TEXT,
+ADD COLUMN has_audio BOOLEAN,
+ADD COLUMN duration_ms BIGINT,
+ADD COLUMN probed_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN unsupported_reason TEXT;
+
+-- Add comments to document the columns
+COMMENT ON COLUMN public.ai_avatar_videos.width IS 'Coded frame width in pixels, before rotation';
+COMMENT ON COLUMN public.ai_avatar_videos.height IS 'Coded frame height in pixels, before rotation';
+COMMENT ON COLUMN public.ai_avatar_videos.rotation IS 'Display rotation in degrees (0, 90, 180 or 270)';
+COMMENT ON COLUMN public.ai_avatar_videos.frame_rate IS 'Average frames per second of the video stream';
+COMMENT ON COLUMN public.ai_avatar_videos.video_codec IS 'Codec of the first video stream, e.g. h264';
+COMMENT ON COLUMN public.ai_avatar_videos.audio_codec IS 'Codec of the first audio stream (null if there is none)';
+COMMENT ON COLUMN public.ai_avatar_videos.has_audio IS 'Whether the video has an audio stream';
+COMMENT ON COLUMN public.ai_avatar_videos.duration_ms IS 'Exact duration in milliseconds';
+COMMENT ON COLUMN public.ai_avatar_videos.probed_at IS 'When the metadata was last read with ffprobe (null until probed)';
+COMMENT ON COLUMN public.ai_avatar_videos.unsupported_reason IS 'Why the render pipeline cannot use this video (null if it can)';
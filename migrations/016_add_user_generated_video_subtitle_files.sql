@@ -0,0 +1,10 @@
+-- Migration: Add subtitle sidecar files to user-generated videos
+-- Description: Records the SRT and WebVTT files published next to renders that carry a soft subtitle track
+
+ALTER TABLE public.user_generated_videos
+ADD COLUMN subtitle_srt_filename TEXT,
+ADD COLUMN subtitle_vtt_filename TEXT;
+
+-- Add comments to document the columns
+COMMENT ON COLUMN public.user_generated_videos.subtitle_srt_filename IS 'SRT sidecar of the overlay text in S3 (null unless soft subtitles were requested)';
+COMMENT ON COLUMN public.user_generated_videos.subtitle_vtt_filename IS 'WebVTT sidecar of the overlay text in S3 (null unless soft subtitles were requested)';
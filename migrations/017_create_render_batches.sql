@@ -0,0 +1,37 @@
+-- Migration: Create render batches table
+-- Description: Groups the renders fanned out from one hooks x avatar videos request so they can be tracked together
+
+-- Create the render batches table
+CREATE TABLE public.render_batches (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES public.user_accounts(id) ON DELETE CASCADE,
+  render_count INTEGER NOT NULL CHECK (render_count > 0),
+  credits_charged INTEGER NOT NULL CHECK (credits_charged >= 0),
+  render_options JSONB NOT NULL DEFAULT '{}'::jsonb,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Add updated_at trigger
+CREATE TRIGGER set_updated_at_render_batches
+BEFORE UPDATE ON public.render_batches
+FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+-- Link each render to the batch it came from
+ALTER TABLE public.user_generated_videos
+ADD COLUMN render_batch_id UUID REFERENCES public.render_batches(id) ON DELETE SET NULL;
+
+-- Add indexes for performance
+CREATE INDEX idx_render_batches_user_id_created_at ON public.render_batches(user_id, created_at);
+CREATE INDEX idx_user_generated_videos_render_batch_id ON public.user_generated_videos(render_batch_id);
+
+-- Add comments for documentation
+COMMENT ON TABLE public.render_batches IS 'Batches of renders requested together, one per hook text and avatar video pair';
+COMMENT ON COLUMN public.render_batches.id IS 'Unique batch identifier';
+COMMENT ON COLUMN public.render_batches.user_id IS 'The user who requested the batch';
+COMMENT ON COLUMN public.render_batches.render_count IS 'Number of renders the batch fanned out into';
+COMMENT ON COLUMN public.render_batches.credits_charged IS 'Credits held up front for the whole batch';
+COMMENT ON COLUMN public.render_batches.render_options IS 'Render settings shared by every render in the batch, as JSON';
+COMMENT ON COLUMN public.render_batches.created_at IS 'When the batch was requested';
+COMMENT ON COLUMN public.render_batches.updated_at IS 'When the batch was last updated';
+COMMENT ON COLUMN public.user_generated_videos.render_batch_id IS 'The render batch this video was rendered in (null for single renders)';
@@ -0,0 +1,8 @@
+-- Migration: Add watermark flag to user-generated videos
+-- Description: Records whether a render was watermarked because the user was on the free plan when it was queued
+
+ALTER TABLE public.user_generated_videos
+ADD COLUMN watermarked BOOLEAN NOT NULL DEFAULT false;
+
+-- Add comments to document the column
+COMMENT ON COLUMN public.user_generated_videos.watermarked IS 'Whether the render carries the free plan watermark, decided from the user''s plan when it was queued';
@@ -0,0 +1,65 @@
+-- Migration: Create brand kits tables
+-- Description: Saved typefaces, colours and logos a user can render with, one kit per brand or client
+
+-- Create the brand kits table
+CREATE TABLE public.brand_kits (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES public.user_accounts(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  font_color TEXT,
+  stroke_color TEXT,
+  background_color TEXT,
+  highlight_color TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Create the brand kit assets table, holding each kit's uploaded font and logo
+CREATE TABLE public.brand_kit_assets (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  brand_kit_id UUID NOT NULL REFERENCES public.brand_kits(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('font', 'logo')),
+  filename TEXT NOT NULL,
+  sha256 TEXT NOT NULL,
+  size_bytes INTEGER NOT NULL CHECK (size_bytes > 0),
+  font_family TEXT,
+  licensed BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (brand_kit_id, kind)
+);
+
+-- Add updated_at triggers
+CREATE TRIGGER set_updated_at_brand_kits
+BEFORE UPDATE ON public.brand_kits
+FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+CREATE TRIGGER set_updated_at_brand_kit_assets
+BEFORE UPDATE ON public.brand_kit_assets
+FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+-- Add indexes for performance
+CREATE INDEX idx_brand_kits_user_id_created_at ON public.brand_kits(user_id, created_at);
+
+-- Add comments for documentation
+COMMENT ON TABLE public.brand_kits IS 'Saved fonts, colours and logos a user can apply to renders';
+COMMENT ON COLUMN public.brand_kits.id IS 'Unique brand kit identifier';
+COMMENT ON COLUMN public.brand_kits.user_id IS 'The user who owns the brand kit';
+COMMENT ON COLUMN public.brand_kits.name IS 'Name of the brand kit, usually the brand or client it is for';
+COMMENT ON COLUMN public.brand_kits.font_color IS 'Overlay text colour as #RRGGBB or #RRGGBBAA (null for the default)';
+COMMENT ON COLUMN public.brand_kits.stroke_color IS 'Overlay text outline colour as #RRGGBB or #RRGGBBAA (null for the default)';
+COMMENT ON COLUMN public.brand_kits.background_color IS 'Colour of the box behind the overlay text as #RRGGBB or #RRGGBBAA (null for no box)';
+COMMENT ON COLUMN public.brand_kits.highlight_color IS 'Colour of the current word in animated captions as #RRGGBB or #RRGGBBAA (null for the default)';
+COMMENT ON COLUMN public.brand_kits.created_at IS 'When the brand kit was created';
+COMMENT ON COLUMN public.brand_kits.updated_at IS 'When the brand kit was last updated';
+COMMENT ON TABLE public.brand_kit_assets IS 'Font and logo files uploaded to brand kits, at most one of each per kit';
+COMMENT ON COLUMN public.brand_kit_assets.id IS 'Unique asset identifier';
+COMMENT ON COLUMN public.brand_kit_assets.brand_kit_id IS 'The brand kit the asset belongs to';
+COMMENT ON COLUMN public.brand_kit_assets.kind IS 'What the asset is: font (TTF/OTF) or logo (PNG)';
+COMMENT ON COLUMN public.brand_kit_assets.filename IS 'Filename of the asset in S3 under brand-kits/fonts/ or brand-kits/logos/, named by its content hash';
+COMMENT ON COLUMN public.brand_kit_assets.sha256 IS 'Hex SHA-256 of the file contents';
+COMMENT ON COLUMN public.brand_kit_assets.size_bytes IS 'Size of the file in bytes';
+COMMENT ON COLUMN public.brand_kit_assets.font_family IS 'Family name read from the font file (null for logos)';
+COMMENT ON COLUMN public.brand_kit_assets.licensed IS 'Whether the uploader confirmed they hold a licence to use the font in videos (false for logos)';
+COMMENT ON COLUMN public.brand_kit_assets.created_at IS 'When the asset was first uploaded';
+COMMENT ON COLUMN public.brand_kit_assets.updated_at IS 'When the asset was last replaced';
@@ -0,0 +1,14 @@
+-- Migration: Allow brand kits to hold a background image
+-- Description: Adds the background asset kind, an image end cards can use in place of a solid colour
+
+ALTER TABLE public.brand_kit_assets
+DROP CONSTRAINT brand_kit_assets_kind_check;
+
+ALTER TABLE public.brand_kit_assets
+ADD CONSTRAINT brand_kit_assets_kind_check CHECK (kind IN ('font', 'logo', 'background'));
+
+COMMENT ON TABLE public.brand_kit_assets IS 'Font, logo and background image files uploaded to brand kits, at most one of each per kit';
+COMMENT ON COLUMN public.brand_kit_assets.kind IS 'What the asset is: font (TTF/OTF), logo (PNG) or background (PNG/JPEG)';
+COMMENT ON COLUMN public.brand_kit_assets.filename IS 'Filename of the asset in S3 under brand-kits/fonts/, brand-kits/logos/ or brand-kits/backgrounds/, named by its content hash';
+COMMENT ON COLUMN public.brand_kit_assets.font_family IS 'Family name read from the font file (null for logos and backgrounds)';
+COMMENT ON COLUMN public.brand_kit_assets.licensed IS 'Whether the uploader confirmed they hold a licence to use the font in videos (false for logos and backgrounds)';
@@ -0,0 +1,121 @@
+-- Migration: Create hook prompt templates tables
+-- Description: Versioned prompt templates for hook generation, one per writing style, and the template version each hook was generated with
+
+-- Create the prompt templates table
+CREATE TABLE public.hook_prompt_templates (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL UNIQUE,
+  style TEXT NOT NULL CHECK (style IN ('listicle', 'pov', 'question', 'controversial', 'storytime')),
+  active BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Create the prompt template versions table; versions are never changed, editing a template's instructions adds one
+CREATE TABLE public.hook_prompt_template_versions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  template_id UUID NOT NULL REFERENCES public.hook_prompt_templates(id) ON DELETE CASCADE,
+  version INTEGER NOT NULL CHECK (version > 0),
+  instructions TEXT NOT NULL,
+  created_by UUID REFERENCES public.user_accounts(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (template_id, version)
+);
+
+-- Record the template version each hook was generated with
+ALTER TABLE public.hooks
+ADD COLUMN prompt_template_version_id UUID REFERENCES public.hook_prompt_template_versions(id);
+
+-- Add updated_at trigger
+CREATE TRIGGER set_updated_at_hook_prompt_templates
+BEFORE UPDATE ON public.hook_prompt_templates
+FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+-- Add indexes for performance
+CREATE INDEX idx_hooks_prompt_template_version_id ON public.hooks(prompt_template_version_id);
+
+-- Add a starting template for each style
+WITH templates (name, style, instructions) AS (
+  VALUES
+    ('Listicle', 'listicle', $$You are a helpful assistant that generates short hooks for a tiktok slideshow.
+You will be given a prompt and you will need to generate a list of hooks for the prompt.
+Write every hook as the title of a numbered list, promising a specific number of tips, mistakes or facts.
+
+For example:
+Prompt: "Plants dying in my house"
+Hooks:
+- "5 things I wish I knew before killing my plants"
+- "3 watering mistakes that are quietly killing your houseplants"
+- "7 houseplants that survive literally anything"
+$$),
+    ('POV', 'pov', $$You are a helpful assistant that generates short hooks for a tiktok slideshow.
+You will be given a prompt and you will need to generate a list of hooks for the prompt.
+Write every hook as a "POV:" line that puts the viewer in a relatable moment.
+
+For example:
+Prompt: "Plants dying in my house"
+Hooks:
+- "POV: you finally figured out why every plant you own dies"
+- "POV: your plant expert friend sees how often you water"
+- "POV: it's week two and your new monstera is already yellow"
+$$),
+    ('Question', 'question', $$You are a helpful assistant that generates short hooks for a tiktok slideshow.
+You will be given a prompt and you will need to generate a list of hooks for the prompt.
+Write every hook as a question the viewer will want answered, so they keep watching for the answer.
+
+For example:
+Prompt: "Plants dying in my house"
+Hooks:
+- "why do your plants keep dying even though you water them every day?"
+- "is your pot the reason your plants won't grow?"
+- "did you know most houseplants die from too much care, not too little?"
+$$),
+    ('Controversial', 'controversial', $$You are a helpful assistant that generates short hooks for a tiktok slideshow.
+You will be given a prompt and you will need to generate a list of hooks for the prompt.
+Write every hook as a bold, contrarian opinion that viewers will want to argue with, without being offensive.
+
+For example:
+Prompt: "Plants dying in my house"
+Hooks:
+- "unpopular opinion: watering schedules are why your plants are dead"
+- "plant shops don't want you to know this about self-watering pots"
+- "hot take: you don't have a black thumb, you have bad soil"
+$$),
+    ('Storytime', 'storytime', $$You are a helpful assistant that generates short hooks for a tiktok slideshow.
+You will be given a prompt and you will need to generate a list of hooks for the prompt.
+Write every hook as the opening line of a personal story, casual and in the first person.
+
+For example:
+Prompt: "Plants dying in my house"
+Hooks:
+- "um so why did it take a plant expert explaining to me that traditional planters are so expensive just to constantly water plants..."
+- "I killed 12 plants in one year before a stranger at the garden centre told me this"
+- "the day my grandma looked at my plants and just started laughing"
+$$)
+),
+inserted AS (
+  INSERT INTO public.hook_prompt_templates (name, style)
+  SELECT name, style FROM templates
+  RETURNING id, name
+)
+INSERT INTO public.hook_prompt_template_versions (template_id, version, instructions)
+SELECT inserted.id, 1, templates.instructions
+FROM inserted
+JOIN templates ON templates.name = inserted.name;
+
+-- Add comments for documentation
+COMMENT ON TABLE public.hook_prompt_templates IS 'Prompt templates admins write for hook generation, one writing style each';
+COMMENT ON COLUMN public.hook_prompt_templates.id IS 'Unique prompt template identifier';
+COMMENT ON COLUMN public.hook_prompt_templates.name IS 'Unique name of the template shown to users';
+COMMENT ON COLUMN public.hook_prompt_templates.style IS 'Style of hook the template writes: listicle, pov, question, controversial or storytime';
+COMMENT ON COLUMN public.hook_prompt_templates.active IS 'Whether users can generate hooks with the template; templates are deactivated rather than deleted';
+COMMENT ON COLUMN public.hook_prompt_templates.created_at IS 'When the template was created';
+COMMENT ON COLUMN public.hook_prompt_templates.updated_at IS 'When the template was last updated';
+COMMENT ON TABLE public.hook_prompt_template_versions IS 'Every version of each prompt template''s instructions; the highest version is the one in use';
+COMMENT ON COLUMN public.hook_prompt_template_versions.id IS 'Unique template version identifier';
+COMMENT ON COLUMN public.hook_prompt_template_versions.template_id IS 'The template this is a version of';
+COMMENT ON COLUMN public.hook_prompt_template_versions.version IS 'Version number, starting at 1 and counting up with each edit';
+COMMENT ON COLUMN public.hook_prompt_template_versions.instructions IS 'Instructions and examples given to the model before the fixed output format, topic and hook count';
+COMMENT ON COLUMN public.hook_prompt_template_versions.created_by IS 'The admin who wrote this version (null for the starting templates)';
+COMMENT ON COLUMN public.hook_prompt_template_versions.created_at IS 'When this version was written';
+COMMENT ON COLUMN public.hooks.prompt_template_version_id IS 'The prompt template version the hook was generated with (null for the built-in prompt)';
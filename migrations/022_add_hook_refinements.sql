@@ -0,0 +1,14 @@
+-- Migration: Add hook refinements
+-- Description: Records the hook a refined hook was generated from and the instruction it was refined with, forming a refinement tree
+
+-- Add the refinement columns; deleting a hook keeps the hooks refined from it
+ALTER TABLE public.hooks
+ADD COLUMN parent_hook_id UUID REFERENCES public.hooks(id) ON DELETE SET NULL,
+ADD COLUMN refinement_instruction TEXT;
+
+-- Add indexes for performance
+CREATE INDEX idx_hooks_parent_hook_id ON public.hooks(parent_hook_id);
+
+-- Add comments for documentation
+COMMENT ON COLUMN public.hooks.parent_hook_id IS 'The hook this hook was refined from (null for generated hooks, or once the parent is deleted)';
+COMMENT ON COLUMN public.hooks.refinement_instruction IS 'The instruction the parent hook was refined with, such as "shorter" (null for generated hooks)';
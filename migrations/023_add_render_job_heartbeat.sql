@@ -0,0 +1,5 @@
+-- Migration: Heartbeat running render jobs
+-- Description: Workers now refresh locked_at while a job runs, so a long render is not mistaken for one whose worker went away
+
+-- Add comments to document the column
+COMMENT ON COLUMN public.render_jobs.locked_at IS 'When the worker running this job last checked in (null while queued); jobs that stop checking in are requeued';
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ethanhosier/reel-farm/internal/repository"
+	"github.com/ethanhosier/reel-farm/internal/service"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+)
+
+// backfill-video-metadata probes every AI avatar video uploaded before media metadata was recorded,
+// storing its metadata and marking any the render pipeline cannot use as unsupported
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Printf("Warning: .env file not found: %v", err)
+	}
+
+	// Hardcoded bucket name from Terraform output
+	bucketName := "reel-farm-bucket-an04kbe3"
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL environment variable is required")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	aiAvatarService, err := service.NewAIAvatarService(repository.NewAIAvatarRepository(pool), bucketName)
+	if err != nil {
+		log.Fatalf("Failed to create AIAvatarService: %v", err)
+	}
+
+	videos, err := aiAvatarService.GetVideosMissingMediaMetadata(ctx)
+	if err != nil {
+		log.Fatalf("Failed to get videos missing metadata: %v", err)
+	}
+
+	if len(videos) == 0 {
+		fmt.Println("🎉 Every video already has media metadata")
+		return
+	}
+
+	fmt.Printf("🎬 Found %d video(s) missing media metadata\n", len(videos))
+	fmt.Println()
+
+	// Probe each video
+	var successCount, unsupportedCount, errorCount int
+	for i, video := range videos {
+		fmt.Printf("📹 Probing video %d/%d: %s\n", i+1, len(videos), video.Title)
+
+		updated, err := aiAvatarService.ProbeAndStoreSourceMetadata(ctx, video)
+		if err != nil {
+			log.Printf("❌ Failed to probe %s: %v", video.Title, err)
+			errorCount++
+			continue
+		}
+
+		if updated.UnsupportedReason != nil {
+			fmt.Printf("🚫 Marked unsupported: %s\n", *updated.UnsupportedReason)
+			unsupportedCount++
+			continue
+		}
+
+		fmt.Printf("✅ %dx%d, %dms\n", *updated.Width, *updated.Height, *updated.DurationMs)
+		successCount++
+	}
+
+	// Summary
+	fmt.Println()
+	fmt.Printf("📊 Backfill Summary:\n")
+	fmt.Printf("   ✅ Supported: %d\n", successCount)
+	fmt.Printf("   🚫 Unsupported: %d\n", unsupportedCount)
+	fmt.Printf("   ❌ Failed: %d\n", errorCount)
+	fmt.Printf("   📁 Total: %d\n", len(videos))
+
+	if errorCount > 0 {
+		fmt.Printf("\n⚠️  Some videos could not be probed. Check the errors above.\n")
+		os.Exit(1)
+	}
+}
@@ -1,11 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/ethanhosier/reel-farm/internal/ffmpeg"
+	"github.com/ethanhosier/reel-farm/internal/textlayout"
+)
+
+const (
+	fontPath    = "TikTokDisplay-Medium.ttf"
+	fontSize    = 36
+	strokeWidth = 3
+	maxLines    = 6
 )
 
 func main() {
@@ -59,10 +70,38 @@ func main() {
 	baseName := strings.TrimSuffix(filepath.Base(firstVideo), filepath.Ext(firstVideo))
 	outputFile := fmt.Sprintf("%s_with_text2.mp4", baseName)
 
-	// Wrap text if it's too long (approximately 35 characters per line for 36px font)
-	wrappedLines := wrapTextToLines(text, 35)
+	// Wrap text to the frame width (minus an 8% margin each side and the outline) using the font's metrics
+	probe, err := ffmpeg.Probe(context.Background(), firstVideo)
+	if err != nil {
+		fmt.Printf("❌ Failed to probe video: %v\n", err)
+		os.Exit(1)
+	}
+	stream := probe.VideoStream()
+	if stream == nil {
+		fmt.Printf("❌ No video stream found in %s\n", firstVideo)
+		os.Exit(1)
+	}
+	frameWidth, _ := stream.DisplaySize()
+
+	font, err := textlayout.LoadFont(fontPath)
+	if err != nil {
+		fmt.Printf("❌ Failed to load font: %v\n", err)
+		os.Exit(1)
+	}
+
+	layout, err := font.Layout(text, textlayout.Options{
+		FontSize:    fontSize,
+		MinFontSize: fontSize / 2,
+		MaxWidth:    float64(frameWidth)*0.84 - 2*strokeWidth,
+		MaxLines:    maxLines,
+	})
+	if err != nil {
+		fmt.Printf("❌ Failed to lay out text: %v\n", err)
+		os.Exit(1)
+	}
+	wrappedLines := layout.Lines
 
-	fmt.Printf("📝 Adding text (%d lines):\n", len(wrappedLines))
+	fmt.Printf("📝 Adding text (%d lines at %dpx):\n", len(wrappedLines), layout.FontSize)
 	for i, line := range wrappedLines {
 		fmt.Printf("  Line %d: '%s'\n", i+1, line)
 	}
@@ -79,60 +118,35 @@ func main() {
 	defer os.Remove(tempTextFile) // Clean up the temp file
 
 	// Use textfile parameter instead of inline text
-	// videoFilter := fmt.Sprintf("drawtext=textfile=%s:fontfile=TikTokDisplay-Medium.ttf:fontsize=36:fontcolor=white:x=(w-text_w)/2:y=(h-text_h)/2:borderw=3:bordercolor=black:text_align=center:line_spacing=16", tempTextFile)
-
-	videoFilter := fmt.Sprintf("drawtext=textfile=%s:fontfile=TikTokDisplay-Medium.ttf:fontsize=36:fontcolor=white:x=(w-text_w)/2:y=(h-text_h)/2:borderw=3:bordercolor=black:text_align=center:line_spacing=16", tempTextFile)
-
-	cmd := exec.Command("ffmpeg",
-		"-i", firstVideo,
-		"-vf", videoFilter,
-		"-c:a", "copy", // Copy audio without re-encoding
-		"-y", // Overwrite output file if it exists
-		outputFile,
-	)
+	drawtext := ffmpeg.DrawtextFile(tempTextFile, fontPath).
+		With("fontsize", layout.FontSize).
+		With("fontcolor", "white").
+		With("x", "(w-text_w)/2").
+		With("y", "(h-text_h)/2").
+		With("borderw", strokeWidth).
+		With("bordercolor", "black").
+		With("text_align", "center").
+		With("line_spacing", layout.FontSize*16/fontSize)
+
+	cmd := ffmpeg.New().
+		Input(firstVideo).
+		VideoFilter(ffmpeg.Chain{drawtext}).
+		AudioCodec("copy"). // Copy audio without re-encoding
+		Output(outputFile)
 
 	fmt.Printf("🚀 Running FFmpeg command...\n")
-	fmt.Printf("Command: %s\n", strings.Join(cmd.Args, " "))
+	fmt.Printf("Command: %s\n", cmd.String())
 
 	// Run the command
-	output, err := cmd.CombinedOutput()
-	if err != nil {
+	if err := cmd.Run(context.Background(), nil); err != nil {
 		fmt.Printf("❌ FFmpeg error: %v\n", err)
-		fmt.Printf("Output: %s\n", string(output))
+		var ffmpegErr *ffmpeg.Error
+		if errors.As(err, &ffmpegErr) {
+			fmt.Printf("Output: %s\n", ffmpegErr.Stderr)
+		}
 		os.Exit(1)
 	}
 
 	fmt.Printf("✅ Successfully created %s\n", outputFile)
 	fmt.Printf("📁 Output location: %s\n", filepath.Join(".", outputFile))
 }
-
-// wrapTextToLines wraps text to fit within a specified number of characters per line
-func wrapTextToLines(text string, maxCharsPerLine int) []string {
-	words := strings.Fields(text)
-	var lines []string
-	var currentLine string
-
-	for _, word := range words {
-		if len(currentLine)+len(word)+1 <= maxCharsPerLine {
-			if currentLine == "" {
-				currentLine = word
-			} else {
-				currentLine += " " + word // Single space between words
-			}
-		} else {
-			if currentLine != "" {
-				lines = append(lines, currentLine)
-				currentLine = word
-			} else {
-				// Word is longer than maxCharsPerLine, add it anyway
-				lines = append(lines, word)
-			}
-		}
-	}
-
-	if currentLine != "" {
-		lines = append(lines, currentLine)
-	}
-
-	return lines
-}
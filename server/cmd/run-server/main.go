@@ -10,12 +10,15 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/ethanhosier/reel-farm/internal/api"
 	"github.com/ethanhosier/reel-farm/internal/handler"
 	"github.com/ethanhosier/reel-farm/internal/middleware"
 	"github.com/ethanhosier/reel-farm/internal/repository"
 	"github.com/ethanhosier/reel-farm/internal/service"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 )
@@ -66,9 +69,15 @@ func main() {
 	userService := service.NewUserService(userRepo)
 	subscriptionService := service.NewSubscriptionService(userRepo)
 
-	// Create LLM and Hook services
-	llmService := service.NewLLMService()
-	hookService := service.NewHookService(userRepo, hookRepo, llmService)
+	// Create the LLM provider chosen in config and the Hook service
+	llmProvider, err := service.NewLLMProvider(service.LLMConfigFromEnv())
+	if err != nil {
+		log.Fatal("Failed to create LLM provider:", err)
+	}
+	log.Printf("🤖 Generating hooks with %s", llmProvider.Name())
+	promptTemplateRepo := repository.NewPromptTemplateRepository(pool)
+	promptTemplateService := service.NewPromptTemplateService(promptTemplateRepo)
+	hookService := service.NewHookService(userRepo, hookRepo, promptTemplateService, llmProvider)
 
 	// Create AI avatar service
 	aiAvatarRepo := repository.NewAIAvatarRepository(pool)
@@ -81,12 +90,44 @@ func main() {
 		log.Fatal("Failed to create AI avatar service:", err)
 	}
 
-	apiServer := handler.NewAPIServer(userService, subscriptionService, hookService, aiAvatarService)
+	// Create brand kit service
+	brandKitRepo := repository.NewBrandKitRepository(pool)
+	brandKitService := service.NewBrandKitService(brandKitRepo, aiAvatarService)
+
+	// Create render job service and start the background render workers
+	renderWorkerConcurrency := 1
+	if concurrencyStr := os.Getenv("RENDER_WORKER_CONCURRENCY"); concurrencyStr != "" {
+		concurrency, err := strconv.Atoi(concurrencyStr)
+		if err != nil {
+			log.Fatal("Invalid RENDER_WORKER_CONCURRENCY:", err)
+		}
+		renderWorkerConcurrency = concurrency
+	}
+	renderJobRepo := repository.NewRenderJobRepository(pool)
+	renderJobService := service.NewRenderJobService(renderJobRepo, hookRepo, userRepo, aiAvatarService, brandKitService, renderWorkerConcurrency)
+	renderJobService.Start(context.Background())
+
+	apiServer := handler.NewAPIServer(userService, subscriptionService, hookService, aiAvatarService, renderJobService, brandKitService, promptTemplateService)
+
+	// Users allowed to call the admin endpoints, as a comma-separated list of user IDs
+	var adminUserIDs []uuid.UUID
+	for _, idStr := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if idStr = strings.TrimSpace(idStr); idStr == "" {
+			continue
+		}
+		adminUserID, err := uuid.Parse(idStr)
+		if err != nil {
+			log.Fatal("Invalid ADMIN_USER_IDS:", err)
+		}
+		adminUserIDs = append(adminUserIDs, adminUserID)
+	}
 
 	// Create HTTP handler using generated code with auth middleware
 	apiHandler := api.HandlerWithOptions(apiServer, api.StdHTTPServerOptions{
 		BaseRouter: http.NewServeMux(),
+		// Middlewares wrap the handler in order, so the last runs first: auth before the admin check
 		Middlewares: []api.MiddlewareFunc{
+			api.MiddlewareFunc(middleware.AdminMiddleware(*noAuth, adminUserIDs)),
 			api.MiddlewareFunc(middleware.AuthMiddleware(*noAuth)),
 		},
 		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
@@ -119,6 +160,10 @@ func main() {
 	fmt.Printf("📡 Health check available at: http://localhost:%s/health\n", port)
 	fmt.Printf("👤 User endpoint available at: http://localhost:%s/user\n", port)
 	fmt.Printf("🎣 Hook generation available at: http://localhost:%s/hooks/generate\n", port)
+	fmt.Printf("📶 Streaming hook generation available at: http://localhost:%s/hooks/generate/stream\n", port)
+	fmt.Printf("🎬 Video rendering available at: http://localhost:%s/user-generated-videos\n", port)
+	fmt.Printf("🎨 Brand kits available at: http://localhost:%s/brand-kits\n", port)
+	fmt.Printf("📝 Prompt templates available at: http://localhost:%s/prompt-templates (admin: /admin/prompt-templates)\n", port)
 	fmt.Printf("🔗 Stripe webhook available at: http://localhost:%s/webhooks/stripe\n", port)
 
 	log.Fatal(http.ListenAndServe(":"+port, mux))
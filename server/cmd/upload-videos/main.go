@@ -7,16 +7,15 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/ethanhosier/reel-farm/db"
+	"github.com/ethanhosier/reel-farm/internal/ffmpeg"
 	"github.com/ethanhosier/reel-farm/internal/repository"
 	"github.com/ethanhosier/reel-farm/internal/service"
 	"github.com/google/uuid"
@@ -201,8 +200,19 @@ func (u *VideoUploader) UploadVideo(videoPath string) error {
 		return fmt.Errorf("failed to get file info: %w", err)
 	}
 
+	// Probe the video and reject anything the render pipeline cannot use before uploading it
+	metadata, err := service.ProbeSourceMetadata(context.Background(), videoPath)
+	if err != nil {
+		return fmt.Errorf("failed to probe video: %w", err)
+	}
+	if err := metadata.Validate(); err != nil {
+		return err
+	}
+	width, height := metadata.DisplaySize()
+	fmt.Printf("   🔎 %dx%d %s, %.2f fps, %s\n", width, height, metadata.VideoCodec, metadata.FrameRate, metadata.Duration.Round(time.Millisecond))
+
 	// Extract thumbnail using ffmpeg
-	thumbnailPath, duration, err := u.extractThumbnail(videoPath)
+	thumbnailPath, err := u.extractThumbnail(videoPath)
 	if err != nil {
 		return fmt.Errorf("failed to extract thumbnail: %w", err)
 	}
@@ -225,17 +235,8 @@ func (u *VideoUploader) UploadVideo(videoPath string) error {
 		return fmt.Errorf("failed to upload thumbnail: %w", err)
 	}
 
-	// Save to database
-	fileSize := fileInfo.Size()
-	_, err = u.service.CreateVideo(context.Background(), &db.CreateVideoParams{
-		ID:                id,
-		Title:             title,
-		Description:       nil, // No description for now
-		Filename:          videoFilename,
-		ThumbnailFilename: thumbnailFilename,
-		Duration:          &duration,
-		FileSize:          &fileSize,
-	})
+	// Save to database along with the probed metadata
+	_, err = u.service.CreateVideo(context.Background(), metadata.CreateVideoParams(id, title, videoFilename, thumbnailFilename, fileInfo.Size()))
 	if err != nil {
 		return fmt.Errorf("failed to save video to database: %w", err)
 	}
@@ -243,47 +244,23 @@ func (u *VideoUploader) UploadVideo(videoPath string) error {
 	return nil
 }
 
-func (u *VideoUploader) extractThumbnail(videoPath string) (string, int32, error) {
+func (u *VideoUploader) extractThumbnail(videoPath string) (string, error) {
 	// Generate thumbnail filename
 	thumbnailPath := filepath.Join(u.tempDir, fmt.Sprintf("thumb_%s.jpg", filepath.Base(videoPath)))
 
 	// Extract thumbnail using ffmpeg
-	cmd := exec.Command("ffmpeg",
-		"-i", videoPath,
-		"-ss", "00:00:01", // Extract frame at 1 second
-		"-vframes", "1",
-		"-q:v", "2", // High quality
-		"-y", // Overwrite output file
-		thumbnailPath,
-	)
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", 0, fmt.Errorf("ffmpeg failed: %s, error: %w", string(output), err)
+	cmd := ffmpeg.New().
+		Input(videoPath).
+		Seek(time.Second). // Extract frame at 1 second
+		Frames(1).
+		Quality(2). // High quality
+		Output(thumbnailPath)
+
+	if err := cmd.Run(context.Background(), nil); err != nil {
+		return "", err
 	}
 
-	// Get video duration using ffprobe
-	durationCmd := exec.Command("ffprobe",
-		"-v", "quiet",
-		"-show_entries", "format=duration",
-		"-of", "csv=p=0",
-		videoPath,
-	)
-
-	durationOutput, err := durationCmd.Output()
-	if err != nil {
-		return "", 0, fmt.Errorf("ffprobe failed: %w", err)
-	}
-
-	durationStr := strings.TrimSpace(string(durationOutput))
-	durationFloat, err := strconv.ParseFloat(durationStr, 32)
-	if err != nil {
-		return "", 0, fmt.Errorf("failed to parse duration: %w", err)
-	}
-
-	duration := int32(durationFloat)
-
-	return thumbnailPath, duration, nil
+	return thumbnailPath, nil
 }
 
 func (u *VideoUploader) uploadFile(filePath, key string) error {
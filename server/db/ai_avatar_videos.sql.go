@@ -19,10 +19,19 @@ INSERT INTO ai_avatar_videos (
     filename,
     thumbnail_filename,
     duration,
-    file_size
+    file_size,
+    width,
+    height,
+    rotation,
+    frame_rate,
+    video_codec,
+    audio_codec,
+    has_audio,
+    duration_ms,
+    probed_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, title, description, filename, thumbnail_filename, duration, file_size, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW()
+) RETURNING id, title, description, filename, thumbnail_filename, duration, file_size, created_at, updated_at, width, height, rotation, frame_rate, video_codec, audio_codec, has_audio, duration_ms, probed_at, unsupported_reason
 `
 
 type CreateVideoParams struct {
@@ -33,6 +42,14 @@ type CreateVideoParams struct {
 	ThumbnailFilename string    `json:"thumbnail_filename"`
 	Duration          *int32    `json:"duration"`
 	FileSize          *int64    `json:"file_size"`
+	Width             *int32    `json:"width"`
+	Height            *int32    `json:"height"`
+	Rotation          *int32    `json:"rotation"`
+	FrameRate         *float64  `json:"frame_rate"`
+	VideoCodec        *string   `json:"video_codec"`
+	AudioCodec        *string   `json:"audio_codec"`
+	HasAudio          *bool     `json:"has_audio"`
+	DurationMs        *int64    `json:"duration_ms"`
 }
 
 func (q *Queries) CreateVideo(ctx context.Context, arg *CreateVideoParams) (*AiAvatarVideo, error) {
@@ -44,6 +61,14 @@ func (q *Queries) CreateVideo(ctx context.Context, arg *CreateVideoParams) (*AiA
 		arg.ThumbnailFilename,
 		arg.Duration,
 		arg.FileSize,
+		arg.Width,
+		arg.Height,
+		arg.Rotation,
+		arg.FrameRate,
+		arg.VideoCodec,
+		arg.AudioCodec,
+		arg.HasAudio,
+		arg.DurationMs,
 	)
 	var i AiAvatarVideo
 	err := row.Scan(
@@ -56,6 +81,16 @@ func (q *Queries) CreateVideo(ctx context.Context, arg *CreateVideoParams) (*AiA
 		&i.FileSize,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Width,
+		&i.Height,
+		&i.Rotation,
+		&i.FrameRate,
+		&i.VideoCodec,
+		&i.AudioCodec,
+		&i.HasAudio,
+		&i.DurationMs,
+		&i.ProbedAt,
+		&i.UnsupportedReason,
 	)
 	return &i, err
 }
@@ -71,7 +106,8 @@ func (q *Queries) DeleteVideo(ctx context.Context, id uuid.UUID) error {
 }
 
 const GetAllVideos = `-- name: GetAllVideos :many
-SELECT id, title, description, filename, thumbnail_filename, duration, file_size, created_at, updated_at FROM ai_avatar_videos
+SELECT id, title, description, filename, thumbnail_filename, duration, file_size, created_at, updated_at, width, height, rotation, frame_rate, video_codec, audio_codec, has_audio, duration_ms, probed_at, unsupported_reason FROM ai_avatar_videos
+WHERE unsupported_reason IS NULL
 ORDER BY created_at DESC
 `
 
@@ -94,6 +130,16 @@ func (q *Queries) GetAllVideos(ctx context.Context) ([]*AiAvatarVideo, error) {
 			&i.FileSize,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Width,
+			&i.Height,
+			&i.Rotation,
+			&i.FrameRate,
+			&i.VideoCodec,
+			&i.AudioCodec,
+			&i.HasAudio,
+			&i.DurationMs,
+			&i.ProbedAt,
+			&i.UnsupportedReason,
 		); err != nil {
 			return nil, err
 		}
@@ -106,7 +152,7 @@ func (q *Queries) GetAllVideos(ctx context.Context) ([]*AiAvatarVideo, error) {
 }
 
 const GetVideoByID = `-- name: GetVideoByID :one
-SELECT id, title, description, filename, thumbnail_filename, duration, file_size, created_at, updated_at FROM ai_avatar_videos
+SELECT id, title, description, filename, thumbnail_filename, duration, file_size, created_at, updated_at, width, height, rotation, frame_rate, video_codec, audio_codec, has_audio, duration_ms, probed_at, unsupported_reason FROM ai_avatar_videos
 WHERE id = $1
 `
 
@@ -123,10 +169,66 @@ func (q *Queries) GetVideoByID(ctx context.Context, id uuid.UUID) (*AiAvatarVide
 		&i.FileSize,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Width,
+		&i.Height,
+		&i.Rotation,
+		&i.FrameRate,
+		&i.VideoCodec,
+		&i.AudioCodec,
+		&i.HasAudio,
+		&i.DurationMs,
+		&i.ProbedAt,
+		&i.UnsupportedReason,
 	)
 	return &i, err
 }
 
+const GetVideosMissingMediaMetadata = `-- name: GetVideosMissingMediaMetadata :many
+SELECT id, title, description, filename, thumbnail_filename, duration, file_size, created_at, updated_at, width, height, rotation, frame_rate, video_codec, audio_codec, has_audio, duration_ms, probed_at, unsupported_reason FROM ai_avatar_videos
+WHERE probed_at IS NULL
+ORDER BY created_at ASC
+`
+
+func (q *Queries) GetVideosMissingMediaMetadata(ctx context.Context) ([]*AiAvatarVideo, error) {
+	rows, err := q.db.Query(ctx, GetVideosMissingMediaMetadata)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*AiAvatarVideo{}
+	for rows.Next() {
+		var i AiAvatarVideo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Filename,
+			&i.ThumbnailFilename,
+			&i.Duration,
+			&i.FileSize,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Width,
+			&i.Height,
+			&i.Rotation,
+			&i.FrameRate,
+			&i.VideoCodec,
+			&i.AudioCodec,
+			&i.HasAudio,
+			&i.DurationMs,
+			&i.ProbedAt,
+			&i.UnsupportedReason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const UpdateVideo = `-- name: UpdateVideo :one
 UPDATE ai_avatar_videos
 SET 
@@ -135,7 +237,7 @@ SET
     duration = $4,
     file_size = $5
 WHERE id = $1
-RETURNING id, title, description, filename, thumbnail_filename, duration, file_size, created_at, updated_at
+RETURNING id, title, description, filename, thumbnail_filename, duration, file_size, created_at, updated_at, width, height, rotation, frame_rate, video_codec, audio_codec, has_audio, duration_ms, probed_at, unsupported_reason
 `
 
 type UpdateVideoParams struct {
@@ -165,6 +267,87 @@ func (q *Queries) UpdateVideo(ctx context.Context, arg *UpdateVideoParams) (*AiA
 		&i.FileSize,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Width,
+		&i.Height,
+		&i.Rotation,
+		&i.FrameRate,
+		&i.VideoCodec,
+		&i.AudioCodec,
+		&i.HasAudio,
+		&i.DurationMs,
+		&i.ProbedAt,
+		&i.UnsupportedReason,
+	)
+	return &i, err
+}
+
+const UpdateVideoMediaMetadata = `-- name: UpdateVideoMediaMetadata :one
+UPDATE ai_avatar_videos
+SET
+    duration = $2,
+    width = $3,
+    height = $4,
+    rotation = $5,
+    frame_rate = $6,
+    video_codec = $7,
+    audio_codec = $8,
+    has_audio = $9,
+    duration_ms = $10,
+    unsupported_reason = $11,
+    probed_at = NOW()
+WHERE id = $1
+RETURNING id, title, description, filename, thumbnail_filename, duration, file_size, created_at, updated_at, width, height, rotation, frame_rate, video_codec, audio_codec, has_audio, duration_ms, probed_at, unsupported_reason
+`
+
+type UpdateVideoMediaMetadataParams struct {
+	ID                uuid.UUID `json:"id"`
+	Duration          *int32    `json:"duration"`
+	Width             *int32    `json:"width"`
+	Height            *int32    `json:"height"`
+	Rotation          *int32    `json:"rotation"`
+	FrameRate         *float64  `json:"frame_rate"`
+	VideoCodec        *string   `json:"video_codec"`
+	AudioCodec        *string   `json:"audio_codec"`
+	HasAudio          *bool     `json:"has_audio"`
+	DurationMs        *int64    `json:"duration_ms"`
+	UnsupportedReason *string   `json:"unsupported_reason"`
+}
+
+func (q *Queries) UpdateVideoMediaMetadata(ctx context.Context, arg *UpdateVideoMediaMetadataParams) (*AiAvatarVideo, error) {
+	row := q.db.QueryRow(ctx, UpdateVideoMediaMetadata,
+		arg.ID,
+		arg.Duration,
+		arg.Width,
+		arg.Height,
+		arg.Rotation,
+		arg.FrameRate,
+		arg.VideoCodec,
+		arg.AudioCodec,
+		arg.HasAudio,
+		arg.DurationMs,
+		arg.UnsupportedReason,
+	)
+	var i AiAvatarVideo
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Filename,
+		&i.ThumbnailFilename,
+		&i.Duration,
+		&i.FileSize,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Width,
+		&i.Height,
+		&i.Rotation,
+		&i.FrameRate,
+		&i.VideoCodec,
+		&i.AudioCodec,
+		&i.HasAudio,
+		&i.DurationMs,
+		&i.ProbedAt,
+		&i.UnsupportedReason,
 	)
 	return &i, err
 }
@@ -42,6 +42,17 @@ func (q *Queries) CaptureCredits(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const CaptureCreditsByRequestID = `-- name: CaptureCreditsByRequestID :exec
+UPDATE public.credit_txns
+SET status = 'captured', updated_at = NOW()
+WHERE request_id = $1 AND status = 'reserved'
+`
+
+func (q *Queries) CaptureCreditsByRequestID(ctx context.Context, requestID string) error {
+	_, err := q.db.Exec(ctx, CaptureCreditsByRequestID, requestID)
+	return err
+}
+
 const GetStaleReservedTxns = `-- name: GetStaleReservedTxns :many
 SELECT id, user_id, amount 
 FROM public.credit_txns 
@@ -108,6 +119,26 @@ func (q *Queries) GetTxnStatus(ctx context.Context, id uuid.UUID) (string, error
 	return status, err
 }
 
+const MarkReservedTxnRefundedByRequestID = `-- name: MarkReservedTxnRefundedByRequestID :one
+UPDATE public.credit_txns
+SET status = 'refunded', updated_at = NOW()
+WHERE request_id = $1 AND status = 'reserved'
+RETURNING id, user_id, amount
+`
+
+type MarkReservedTxnRefundedByRequestIDRow struct {
+	ID     uuid.UUID   `json:"id"`
+	UserID pgtype.UUID `json:"user_id"`
+	Amount int32       `json:"amount"`
+}
+
+func (q *Queries) MarkReservedTxnRefundedByRequestID(ctx context.Context, requestID string) (*MarkReservedTxnRefundedByRequestIDRow, error) {
+	row := q.db.QueryRow(ctx, MarkReservedTxnRefundedByRequestID, requestID)
+	var i MarkReservedTxnRefundedByRequestIDRow
+	err := row.Scan(&i.ID, &i.UserID, &i.Amount)
+	return &i, err
+}
+
 const MarkTxnRefunded = `-- name: MarkTxnRefunded :exec
 UPDATE public.credit_txns
 SET status = 'refunded', updated_at = NOW()
@@ -13,18 +13,19 @@ import (
 )
 
 const CreateHook = `-- name: CreateHook :one
-INSERT INTO public.hooks (user_id, generation_id, prompt, hook_text, hook_index, credits_used)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at
+INSERT INTO public.hooks (user_id, generation_id, prompt, hook_text, hook_index, credits_used, prompt_template_version_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at, prompt_template_version_id, parent_hook_id, refinement_instruction
 `
 
 type CreateHookParams struct {
-	UserID       pgtype.UUID `json:"user_id"`
-	GenerationID pgtype.UUID `json:"generation_id"`
-	Prompt       string      `json:"prompt"`
-	HookText     string      `json:"hook_text"`
-	HookIndex    int32       `json:"hook_index"`
-	CreditsUsed  int32       `json:"credits_used"`
+	UserID                  pgtype.UUID `json:"user_id"`
+	GenerationID            pgtype.UUID `json:"generation_id"`
+	Prompt                  string      `json:"prompt"`
+	HookText                string      `json:"hook_text"`
+	HookIndex               int32       `json:"hook_index"`
+	CreditsUsed             int32       `json:"credits_used"`
+	PromptTemplateVersionID pgtype.UUID `json:"prompt_template_version_id"`
 }
 
 func (q *Queries) CreateHook(ctx context.Context, arg *CreateHookParams) (*Hook, error) {
@@ -35,6 +36,7 @@ func (q *Queries) CreateHook(ctx context.Context, arg *CreateHookParams) (*Hook,
 		arg.HookText,
 		arg.HookIndex,
 		arg.CreditsUsed,
+		arg.PromptTemplateVersionID,
 	)
 	var i Hook
 	err := row.Scan(
@@ -47,23 +49,88 @@ func (q *Queries) CreateHook(ctx context.Context, arg *CreateHookParams) (*Hook,
 		&i.CreditsUsed,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PromptTemplateVersionID,
+		&i.ParentHookID,
+		&i.RefinementInstruction,
 	)
 	return &i, err
 }
 
+const CreateHookRefinementsBatch = `-- name: CreateHookRefinementsBatch :many
+INSERT INTO public.hooks (user_id, generation_id, prompt, hook_text, hook_index, credits_used, parent_hook_id, refinement_instruction, prompt_template_version_id)
+SELECT $1, $2, $3, unnest($4::text[]), unnest($5::int[]), $6, $7, $8, $9
+RETURNING id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at, prompt_template_version_id, parent_hook_id, refinement_instruction
+`
+
+type CreateHookRefinementsBatchParams struct {
+	UserID                  pgtype.UUID `json:"user_id"`
+	GenerationID            pgtype.UUID `json:"generation_id"`
+	Prompt                  string      `json:"prompt"`
+	HookTexts               []string    `json:"hook_texts"`
+	HookIndices             []int32     `json:"hook_indices"`
+	CreditsUsed             int32       `json:"credits_used"`
+	ParentHookID            pgtype.UUID `json:"parent_hook_id"`
+	RefinementInstruction   *string     `json:"refinement_instruction"`
+	PromptTemplateVersionID pgtype.UUID `json:"prompt_template_version_id"`
+}
+
+func (q *Queries) CreateHookRefinementsBatch(ctx context.Context, arg *CreateHookRefinementsBatchParams) ([]*Hook, error) {
+	rows, err := q.db.Query(ctx, CreateHookRefinementsBatch,
+		arg.UserID,
+		arg.GenerationID,
+		arg.Prompt,
+		arg.HookTexts,
+		arg.HookIndices,
+		arg.CreditsUsed,
+		arg.ParentHookID,
+		arg.RefinementInstruction,
+		arg.PromptTemplateVersionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Hook{}
+	for rows.Next() {
+		var i Hook
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.GenerationID,
+			&i.Prompt,
+			&i.HookText,
+			&i.HookIndex,
+			&i.CreditsUsed,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PromptTemplateVersionID,
+			&i.ParentHookID,
+			&i.RefinementInstruction,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const CreateHooksBatch = `-- name: CreateHooksBatch :many
-INSERT INTO public.hooks (user_id, generation_id, prompt, hook_text, hook_index, credits_used)
-SELECT $1, $2, $3, unnest($4::text[]), unnest($5::int[]), $6
-RETURNING id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at
+INSERT INTO public.hooks (user_id, generation_id, prompt, hook_text, hook_index, credits_used, prompt_template_version_id)
+SELECT $1, $2, $3, unnest($4::text[]), unnest($5::int[]), $6, $7
+RETURNING id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at, prompt_template_version_id, parent_hook_id, refinement_instruction
 `
 
 type CreateHooksBatchParams struct {
-	UserID       pgtype.UUID `json:"user_id"`
-	GenerationID pgtype.UUID `json:"generation_id"`
-	Prompt       string      `json:"prompt"`
-	Column4      []string    `json:"column_4"`
-	Column5      []int32     `json:"column_5"`
-	CreditsUsed  int32       `json:"credits_used"`
+	UserID                  pgtype.UUID `json:"user_id"`
+	GenerationID            pgtype.UUID `json:"generation_id"`
+	Prompt                  string      `json:"prompt"`
+	Column4                 []string    `json:"column_4"`
+	Column5                 []int32     `json:"column_5"`
+	CreditsUsed             int32       `json:"credits_used"`
+	PromptTemplateVersionID pgtype.UUID `json:"prompt_template_version_id"`
 }
 
 func (q *Queries) CreateHooksBatch(ctx context.Context, arg *CreateHooksBatchParams) ([]*Hook, error) {
@@ -74,6 +141,7 @@ func (q *Queries) CreateHooksBatch(ctx context.Context, arg *CreateHooksBatchPar
 		arg.Column4,
 		arg.Column5,
 		arg.CreditsUsed,
+		arg.PromptTemplateVersionID,
 	)
 	if err != nil {
 		return nil, err
@@ -92,6 +160,9 @@ func (q *Queries) CreateHooksBatch(ctx context.Context, arg *CreateHooksBatchPar
 			&i.CreditsUsed,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PromptTemplateVersionID,
+			&i.ParentHookID,
+			&i.RefinementInstruction,
 		); err != nil {
 			return nil, err
 		}
@@ -121,7 +192,7 @@ func (q *Queries) DeleteHook(ctx context.Context, arg *DeleteHookParams) error {
 const DeleteHooks = `-- name: DeleteHooks :many
 DELETE FROM public.hooks
 WHERE id = ANY($1::uuid[]) AND user_id = $2
-RETURNING id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at
+RETURNING id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at, prompt_template_version_id, parent_hook_id, refinement_instruction
 `
 
 type DeleteHooksParams struct {
@@ -150,6 +221,9 @@ func (q *Queries) DeleteHooks(ctx context.Context, arg *DeleteHooksParams) ([]*H
 			&i.CreditsUsed,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PromptTemplateVersionID,
+			&i.ParentHookID,
+			&i.RefinementInstruction,
 		); err != nil {
 			return nil, err
 		}
@@ -162,7 +236,7 @@ func (q *Queries) DeleteHooks(ctx context.Context, arg *DeleteHooksParams) ([]*H
 }
 
 const GetHookByID = `-- name: GetHookByID :one
-SELECT id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at FROM public.hooks
+SELECT id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at, prompt_template_version_id, parent_hook_id, refinement_instruction FROM public.hooks
 WHERE id = $1
 `
 
@@ -179,12 +253,15 @@ func (q *Queries) GetHookByID(ctx context.Context, id uuid.UUID) (*Hook, error)
 		&i.CreditsUsed,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PromptTemplateVersionID,
+		&i.ParentHookID,
+		&i.RefinementInstruction,
 	)
 	return &i, err
 }
 
 const GetHooksByGeneration = `-- name: GetHooksByGeneration :many
-SELECT id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at FROM public.hooks
+SELECT id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at, prompt_template_version_id, parent_hook_id, refinement_instruction FROM public.hooks
 WHERE generation_id = $1
 ORDER BY hook_index ASC
 `
@@ -208,6 +285,54 @@ func (q *Queries) GetHooksByGeneration(ctx context.Context, generationID pgtype.
 			&i.CreditsUsed,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PromptTemplateVersionID,
+			&i.ParentHookID,
+			&i.RefinementInstruction,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const GetHooksByIDs = `-- name: GetHooksByIDs :many
+SELECT id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at, prompt_template_version_id, parent_hook_id, refinement_instruction FROM public.hooks
+WHERE id = ANY($1::uuid[]) AND user_id = $2
+`
+
+type GetHooksByIDsParams struct {
+	HookIds []pgtype.UUID `json:"hook_ids"`
+	UserID  pgtype.UUID   `json:"user_id"`
+}
+
+// sqlc:arg hook_ids uuid[]
+// sqlc:arg user_id uuid
+func (q *Queries) GetHooksByIDs(ctx context.Context, arg *GetHooksByIDsParams) ([]*Hook, error) {
+	rows, err := q.db.Query(ctx, GetHooksByIDs, arg.HookIds, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Hook{}
+	for rows.Next() {
+		var i Hook
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.GenerationID,
+			&i.Prompt,
+			&i.HookText,
+			&i.HookIndex,
+			&i.CreditsUsed,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PromptTemplateVersionID,
+			&i.ParentHookID,
+			&i.RefinementInstruction,
 		); err != nil {
 			return nil, err
 		}
@@ -220,7 +345,7 @@ func (q *Queries) GetHooksByGeneration(ctx context.Context, generationID pgtype.
 }
 
 const GetHooksByUser = `-- name: GetHooksByUser :many
-SELECT id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at FROM public.hooks
+SELECT id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at, prompt_template_version_id, parent_hook_id, refinement_instruction FROM public.hooks
 WHERE user_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -251,6 +376,9 @@ func (q *Queries) GetHooksByUser(ctx context.Context, arg *GetHooksByUserParams)
 			&i.CreditsUsed,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PromptTemplateVersionID,
+			&i.ParentHookID,
+			&i.RefinementInstruction,
 		); err != nil {
 			return nil, err
 		}
@@ -262,6 +390,36 @@ func (q *Queries) GetHooksByUser(ctx context.Context, arg *GetHooksByUserParams)
 	return items, nil
 }
 
+const GetUserHookByID = `-- name: GetUserHookByID :one
+SELECT id, user_id, generation_id, prompt, hook_text, hook_index, credits_used, created_at, updated_at, prompt_template_version_id, parent_hook_id, refinement_instruction FROM public.hooks
+WHERE id = $1 AND user_id = $2
+`
+
+type GetUserHookByIDParams struct {
+	ID     uuid.UUID   `json:"id"`
+	UserID pgtype.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetUserHookByID(ctx context.Context, arg *GetUserHookByIDParams) (*Hook, error) {
+	row := q.db.QueryRow(ctx, GetUserHookByID, arg.ID, arg.UserID)
+	var i Hook
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.GenerationID,
+		&i.Prompt,
+		&i.HookText,
+		&i.HookIndex,
+		&i.CreditsUsed,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PromptTemplateVersionID,
+		&i.ParentHookID,
+		&i.RefinementInstruction,
+	)
+	return &i, err
+}
+
 const GetUserHookCount = `-- name: GetUserHookCount :one
 SELECT COUNT(*) FROM public.hooks
 WHERE user_id = $1
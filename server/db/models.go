@@ -21,6 +21,72 @@ type AiAvatarVideo struct {
 	FileSize          *int64    `json:"file_size"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
+	// Coded frame width in pixels, before rotation
+	Width *int32 `json:"width"`
+	// Coded frame height in pixels, before rotation
+	Height *int32 `json:"height"`
+	// Display rotation in degrees (0, 90, 180 or 270)
+	Rotation *int32 `json:"rotation"`
+	// Average frames per second of the video stream
+	FrameRate *float64 `json:"frame_rate"`
+	// Codec of the first video stream, e.g. h264
+	VideoCodec *string `json:"video_codec"`
+	// Codec of the first audio stream (null if there is none)
+	AudioCodec *string `json:"audio_codec"`
+	// Whether the video has an audio stream
+	HasAudio *bool `json:"has_audio"`
+	// Exact duration in milliseconds
+	DurationMs *int64 `json:"duration_ms"`
+	// When the metadata was last read with ffprobe (null until probed)
+	ProbedAt pgtype.Timestamptz `json:"probed_at"`
+	// Why the render pipeline cannot use this video (null if it can)
+	UnsupportedReason *string `json:"unsupported_reason"`
+}
+
+// Saved fonts, colours and logos a user can apply to renders
+type BrandKit struct {
+	// Unique brand kit identifier
+	ID uuid.UUID `json:"id"`
+	// The user who owns the brand kit
+	UserID pgtype.UUID `json:"user_id"`
+	// Name of the brand kit, usually the brand or client it is for
+	Name string `json:"name"`
+	// Overlay text colour as #RRGGBB or #RRGGBBAA (null for the default)
+	FontColor *string `json:"font_color"`
+	// Overlay text outline colour as #RRGGBB or #RRGGBBAA (null for the default)
+	StrokeColor *string `json:"stroke_color"`
+	// Colour of the box behind the overlay text as #RRGGBB or #RRGGBBAA (null for no box)
+	BackgroundColor *string `json:"background_color"`
+	// Colour of the current word in animated captions as #RRGGBB or #RRGGBBAA (null for the default)
+	HighlightColor *string `json:"highlight_color"`
+	// When the brand kit was created
+	CreatedAt time.Time `json:"created_at"`
+	// When the brand kit was last updated
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Font, logo and background image files uploaded to brand kits, at most one of each per kit
+type BrandKitAsset struct {
+	// Unique asset identifier
+	ID uuid.UUID `json:"id"`
+	// The brand kit the asset belongs to
+	BrandKitID pgtype.UUID `json:"brand_kit_id"`
+	// What the asset is: font (TTF/OTF), logo (PNG) or background (PNG/JPEG)
+	Kind string `json:"kind"`
+	// Filename of the asset in S3 under brand-kits/fonts/, brand-kits/logos/ or brand-kits/backgrounds/, named by its content hash
+	Filename string `json:"filename"`
+	// Hex SHA-256 of the file contents
+	Sha256 string `json:"sha256"`
+	// Size of the file in bytes
+	SizeBytes int32 `json:"size_bytes"`
+	// Family name read from the font file (null for logos and backgrounds)
+	FontFamily *string `json:"font_family"`
+	// Whether the uploader confirmed they hold a licence to use the font in videos (false for logos and backgrounds)
+	Licensed bool `json:"licensed"`
+	// When the asset was first uploaded
+	CreatedAt time.Time `json:"created_at"`
+	// When the asset was last replaced
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // Tracks credit transactions for idempotency and audit purposes
@@ -61,6 +127,88 @@ type Hook struct {
 	CreatedAt time.Time `json:"created_at"`
 	// When the record was last updated
 	UpdatedAt time.Time `json:"updated_at"`
+	// The prompt template version the hook was generated with (null for the built-in prompt)
+	PromptTemplateVersionID pgtype.UUID `json:"prompt_template_version_id"`
+	// The hook this hook was refined from (null for generated hooks, or once the parent is deleted)
+	ParentHookID pgtype.UUID `json:"parent_hook_id"`
+	// The instruction the parent hook was refined with, such as "shorter" (null for generated hooks)
+	RefinementInstruction *string `json:"refinement_instruction"`
+}
+
+// Prompt templates admins write for hook generation, one writing style each
+type HookPromptTemplate struct {
+	// Unique prompt template identifier
+	ID uuid.UUID `json:"id"`
+	// Unique name of the template shown to users
+	Name string `json:"name"`
+	// Style of hook the template writes: listicle, pov, question, controversial or storytime
+	Style string `json:"style"`
+	// Whether users can generate hooks with the template; templates are deactivated rather than deleted
+	Active bool `json:"active"`
+	// When the template was created
+	CreatedAt time.Time `json:"created_at"`
+	// When the template was last updated
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Every version of each prompt template's instructions; the highest version is the one in use
+type HookPromptTemplateVersion struct {
+	// Unique template version identifier
+	ID uuid.UUID `json:"id"`
+	// The template this is a version of
+	TemplateID pgtype.UUID `json:"template_id"`
+	// Version number, starting at 1 and counting up with each edit
+	Version int32 `json:"version"`
+	// Instructions and examples given to the model before the fixed output format, topic and hook count
+	Instructions string `json:"instructions"`
+	// The admin who wrote this version (null for the starting templates)
+	CreatedBy pgtype.UUID `json:"created_by"`
+	// When this version was written
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Batches of renders requested together, one per hook text and avatar video pair
+type RenderBatch struct {
+	// Unique batch identifier
+	ID uuid.UUID `json:"id"`
+	// The user who requested the batch
+	UserID pgtype.UUID `json:"user_id"`
+	// Number of renders the batch fanned out into
+	RenderCount int32 `json:"render_count"`
+	// Credits held up front for the whole batch
+	CreditsCharged int32 `json:"credits_charged"`
+	// Render settings shared by every render in the batch, as JSON
+	RenderOptions []byte `json:"render_options"`
+	// When the batch was requested
+	CreatedAt time.Time `json:"created_at"`
+	// When the batch was last updated
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Queue of background render jobs for user-generated videos
+type RenderJob struct {
+	// Unique job identifier
+	ID uuid.UUID `json:"id"`
+	// The user-generated video this job renders
+	UserGeneratedVideoID pgtype.UUID `json:"user_generated_video_id"`
+	// Job status: queued, running, completed, failed or cancelled
+	Status string `json:"status"`
+	// Number of times a worker has picked up this job
+	Attempts int32 `json:"attempts"`
+	// Earliest time a worker may pick up this job
+	RunAfter pgtype.Timestamptz `json:"run_after"`
+	// When the worker running this job last checked in (null while queued); jobs that stop checking in are requeued
+	LockedAt pgtype.Timestamptz `json:"locked_at"`
+	// Error from the most recent failed attempt
+	LastError *string `json:"last_error"`
+	// When the job was enqueued
+	CreatedAt time.Time `json:"created_at"`
+	// When the job was last updated
+	UpdatedAt time.Time `json:"updated_at"`
+	// Percent of the source video encoded so far (0-100), parsed from ffmpeg -progress output
+	ProgressPercent float64 `json:"progress_percent"`
+	// Estimated seconds until the encode finishes (null until known)
+	EtaSeconds *int32 `json:"eta_seconds"`
 }
 
 type SchemaMigration struct {
@@ -91,4 +239,36 @@ type UserGeneratedVideo struct {
 	ErrorMessage           *string     `json:"error_message"`
 	CreatedAt              time.Time   `json:"created_at"`
 	UpdatedAt              time.Time   `json:"updated_at"`
+	// Per-request render settings such as overlay style, as JSON
+	RenderOptions []byte `json:"render_options"`
+	// SHA-256 of the canonical render spec (source video, text, style, profiles and renderer version)
+	RenderSpecHash *string `json:"render_spec_hash"`
+	// SRT sidecar of the overlay text in S3 (null unless soft subtitles were requested)
+	SubtitleSrtFilename *string `json:"subtitle_srt_filename"`
+	// WebVTT sidecar of the overlay text in S3 (null unless soft subtitles were requested)
+	SubtitleVttFilename *string `json:"subtitle_vtt_filename"`
+	// The render batch this video was rendered in (null for single renders)
+	RenderBatchID pgtype.UUID `json:"render_batch_id"`
+	// Whether the render carries the free plan watermark, decided from the user's plan when it was queued
+	Watermarked bool `json:"watermarked"`
+}
+
+// Per-platform renders of a user-generated video
+type UserGeneratedVideoVariant struct {
+	// Unique variant identifier
+	ID uuid.UUID `json:"id"`
+	// The user-generated video this is a variant of
+	UserGeneratedVideoID pgtype.UUID `json:"user_generated_video_id"`
+	// Name of the output profile the variant was rendered with
+	Profile string `json:"profile"`
+	// S3 filename of the rendered variant
+	VideoFilename string `json:"video_filename"`
+	// Output width in pixels
+	Width int32 `json:"width"`
+	// Output height in pixels
+	Height int32 `json:"height"`
+	// When the variant was first rendered
+	CreatedAt time.Time `json:"created_at"`
+	// When the variant was last rendered
+	UpdatedAt time.Time `json:"updated_at"`
 }
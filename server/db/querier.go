@@ -14,38 +14,86 @@ import (
 type Querier interface {
 	AddCreditsToUser(ctx context.Context, arg *AddCreditsToUserParams) error
 	AtomicDebitCredits(ctx context.Context, arg *AtomicDebitCreditsParams) (int32, error)
+	CancelRenderJobsForVideo(ctx context.Context, userGeneratedVideoID pgtype.UUID) ([]*RenderJob, error)
 	CaptureCredits(ctx context.Context, id uuid.UUID) error
+	CaptureCreditsByRequestID(ctx context.Context, requestID string) error
+	ClaimNextRenderJob(ctx context.Context) (*RenderJob, error)
+	CompleteRenderJob(ctx context.Context, id uuid.UUID) (int64, error)
+	CreateBrandKit(ctx context.Context, arg *CreateBrandKitParams) (*BrandKit, error)
 	CreateHook(ctx context.Context, arg *CreateHookParams) (*Hook, error)
+	CreateHookPromptTemplate(ctx context.Context, arg *CreateHookPromptTemplateParams) (*HookPromptTemplate, error)
+	CreateHookPromptTemplateVersion(ctx context.Context, arg *CreateHookPromptTemplateVersionParams) (*HookPromptTemplateVersion, error)
+	CreateHookRefinementsBatch(ctx context.Context, arg *CreateHookRefinementsBatchParams) ([]*Hook, error)
 	CreateHooksBatch(ctx context.Context, arg *CreateHooksBatchParams) ([]*Hook, error)
+	CreateRenderBatch(ctx context.Context, arg *CreateRenderBatchParams) (*RenderBatch, error)
+	CreateRenderJob(ctx context.Context, userGeneratedVideoID pgtype.UUID) (*RenderJob, error)
 	CreateUserGeneratedVideo(ctx context.Context, arg *CreateUserGeneratedVideoParams) (*UserGeneratedVideo, error)
 	CreateVideo(ctx context.Context, arg *CreateVideoParams) (*AiAvatarVideo, error)
+	DeleteBrandKit(ctx context.Context, arg *DeleteBrandKitParams) (int64, error)
+	DeleteBrandKitAsset(ctx context.Context, arg *DeleteBrandKitAssetParams) (int64, error)
 	DeleteHook(ctx context.Context, arg *DeleteHookParams) error
 	// sqlc:arg hook_ids uuid[]
 	// sqlc:arg user_id uuid
 	DeleteHooks(ctx context.Context, arg *DeleteHooksParams) ([]*Hook, error)
 	DeleteVideo(ctx context.Context, id uuid.UUID) error
+	FailRenderJob(ctx context.Context, arg *FailRenderJobParams) (int64, error)
+	FailStaleRenderJobs(ctx context.Context, arg *FailStaleRenderJobsParams) ([]*RenderJob, error)
+	GetActiveHookPromptTemplates(ctx context.Context) ([]*HookPromptTemplate, error)
 	GetAllVideos(ctx context.Context) ([]*AiAvatarVideo, error)
+	// sqlc:arg brand_kit_ids uuid[]
+	GetBrandKitAssetsByBrandKitIDs(ctx context.Context, brandKitIds []pgtype.UUID) ([]*BrandKitAsset, error)
+	GetBrandKitByID(ctx context.Context, arg *GetBrandKitByIDParams) (*BrandKit, error)
+	GetBrandKitsByUserID(ctx context.Context, userID pgtype.UUID) ([]*BrandKit, error)
+	GetCompletedUserGeneratedVideoByRenderSpecHash(ctx context.Context, arg *GetCompletedUserGeneratedVideoByRenderSpecHashParams) (*UserGeneratedVideo, error)
 	GetHookByID(ctx context.Context, id uuid.UUID) (*Hook, error)
+	GetHookPromptTemplateByID(ctx context.Context, id uuid.UUID) (*HookPromptTemplate, error)
+	GetHookPromptTemplateVersionByID(ctx context.Context, id uuid.UUID) (*HookPromptTemplateVersion, error)
+	GetHookPromptTemplateVersions(ctx context.Context, templateID pgtype.UUID) ([]*HookPromptTemplateVersion, error)
+	GetHookPromptTemplates(ctx context.Context) ([]*HookPromptTemplate, error)
 	GetHooksByGeneration(ctx context.Context, generationID pgtype.UUID) ([]*Hook, error)
+	// sqlc:arg hook_ids uuid[]
+	// sqlc:arg user_id uuid
+	GetHooksByIDs(ctx context.Context, arg *GetHooksByIDsParams) ([]*Hook, error)
 	GetHooksByUser(ctx context.Context, arg *GetHooksByUserParams) ([]*Hook, error)
+	GetLatestHookPromptTemplateVersion(ctx context.Context, templateID pgtype.UUID) (*HookPromptTemplateVersion, error)
+	// sqlc:arg template_ids uuid[]
+	GetLatestHookPromptTemplateVersions(ctx context.Context, templateIds []pgtype.UUID) ([]*HookPromptTemplateVersion, error)
+	GetLatestRenderJobByVideoID(ctx context.Context, userGeneratedVideoID pgtype.UUID) (*RenderJob, error)
+	GetRenderBatchByID(ctx context.Context, id uuid.UUID) (*RenderBatch, error)
+	GetRenderBatchSummariesByUserID(ctx context.Context, arg *GetRenderBatchSummariesByUserIDParams) ([]*GetRenderBatchSummariesByUserIDRow, error)
 	GetStaleReservedTxns(ctx context.Context) ([]*GetStaleReservedTxnsRow, error)
 	GetTxnByRequestID(ctx context.Context, requestID string) (*CreditTxn, error)
 	GetTxnStatus(ctx context.Context, id uuid.UUID) (string, error)
 	GetUserAccount(ctx context.Context, id uuid.UUID) (*UserAccount, error)
 	GetUserByBillingCustomerID(ctx context.Context, billingCustomerID *string) (*UserAccount, error)
 	GetUserGeneratedVideoByID(ctx context.Context, id uuid.UUID) (*UserGeneratedVideo, error)
+	GetUserGeneratedVideosByRenderBatchID(ctx context.Context, renderBatchID pgtype.UUID) ([]*UserGeneratedVideo, error)
 	GetUserGeneratedVideosByUserID(ctx context.Context, userID pgtype.UUID) ([]*UserGeneratedVideo, error)
+	GetUserHookByID(ctx context.Context, arg *GetUserHookByIDParams) (*Hook, error)
 	GetUserHookCount(ctx context.Context, userID pgtype.UUID) (int64, error)
+	GetVariantsByUserGeneratedVideoID(ctx context.Context, userGeneratedVideoID pgtype.UUID) ([]*UserGeneratedVideoVariant, error)
+	GetVariantsByUserGeneratedVideoIDs(ctx context.Context, videoIds []pgtype.UUID) ([]*UserGeneratedVideoVariant, error)
 	GetVideoByID(ctx context.Context, id uuid.UUID) (*AiAvatarVideo, error)
+	GetVideosMissingMediaMetadata(ctx context.Context) ([]*AiAvatarVideo, error)
+	HeartbeatRenderJob(ctx context.Context, id uuid.UUID) (int64, error)
+	MarkReservedTxnRefundedByRequestID(ctx context.Context, requestID string) (*MarkReservedTxnRefundedByRequestIDRow, error)
 	MarkTxnRefunded(ctx context.Context, id uuid.UUID) error
 	RefundCredits(ctx context.Context, arg *RefundCreditsParams) error
 	RemoveCreditsFromUser(ctx context.Context, arg *RemoveCreditsFromUserParams) error
+	RequeueStaleRenderJobs(ctx context.Context, arg *RequeueStaleRenderJobsParams) (int64, error)
 	ReserveCredits(ctx context.Context, arg *ReserveCreditsParams) (*ReserveCreditsRow, error)
+	RetryRenderJob(ctx context.Context, arg *RetryRenderJobParams) (int64, error)
+	UpdateBrandKit(ctx context.Context, arg *UpdateBrandKitParams) (*BrandKit, error)
+	UpdateHookPromptTemplate(ctx context.Context, arg *UpdateHookPromptTemplateParams) (*HookPromptTemplate, error)
+	UpdateRenderJobProgress(ctx context.Context, arg *UpdateRenderJobProgressParams) (int64, error)
 	UpdateUserBillingCustomerID(ctx context.Context, arg *UpdateUserBillingCustomerIDParams) error
 	UpdateUserGeneratedVideoFilenames(ctx context.Context, arg *UpdateUserGeneratedVideoFilenamesParams) (*UserGeneratedVideo, error)
 	UpdateUserGeneratedVideoStatus(ctx context.Context, arg *UpdateUserGeneratedVideoStatusParams) (*UserGeneratedVideo, error)
 	UpdateUserPlan(ctx context.Context, arg *UpdateUserPlanParams) error
 	UpdateVideo(ctx context.Context, arg *UpdateVideoParams) (*AiAvatarVideo, error)
+	UpdateVideoMediaMetadata(ctx context.Context, arg *UpdateVideoMediaMetadataParams) (*AiAvatarVideo, error)
+	UpsertBrandKitAsset(ctx context.Context, arg *UpsertBrandKitAssetParams) (*BrandKitAsset, error)
+	UpsertUserGeneratedVideoVariant(ctx context.Context, arg *UpsertUserGeneratedVideoVariantParams) (*UserGeneratedVideoVariant, error)
 }
 
 var _ Querier = (*Queries)(nil)
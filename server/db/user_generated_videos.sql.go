@@ -20,10 +20,14 @@ INSERT INTO user_generated_videos (
     overlay_text,
     generated_video_filename,
     thumbnail_filename,
-    status
+    status,
+    render_options,
+    render_spec_hash,
+    render_batch_id,
+    watermarked
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at, render_options, render_spec_hash, subtitle_srt_filename, subtitle_vtt_filename, render_batch_id, watermarked
 `
 
 type CreateUserGeneratedVideoParams struct {
@@ -34,6 +38,10 @@ type CreateUserGeneratedVideoParams struct {
 	GeneratedVideoFilename string      `json:"generated_video_filename"`
 	ThumbnailFilename      string      `json:"thumbnail_filename"`
 	Status                 *string     `json:"status"`
+	RenderOptions          []byte      `json:"render_options"`
+	RenderSpecHash         *string     `json:"render_spec_hash"`
+	RenderBatchID          pgtype.UUID `json:"render_batch_id"`
+	Watermarked            bool        `json:"watermarked"`
 }
 
 func (q *Queries) CreateUserGeneratedVideo(ctx context.Context, arg *CreateUserGeneratedVideoParams) (*UserGeneratedVideo, error) {
@@ -45,6 +53,10 @@ func (q *Queries) CreateUserGeneratedVideo(ctx context.Context, arg *CreateUserG
 		arg.GeneratedVideoFilename,
 		arg.ThumbnailFilename,
 		arg.Status,
+		arg.RenderOptions,
+		arg.RenderSpecHash,
+		arg.RenderBatchID,
+		arg.Watermarked,
 	)
 	var i UserGeneratedVideo
 	err := row.Scan(
@@ -58,12 +70,54 @@ func (q *Queries) CreateUserGeneratedVideo(ctx context.Context, arg *CreateUserG
 		&i.ErrorMessage,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RenderOptions,
+		&i.RenderSpecHash,
+		&i.SubtitleSrtFilename,
+		&i.SubtitleVttFilename,
+		&i.RenderBatchID,
+		&i.Watermarked,
+	)
+	return &i, err
+}
+
+const GetCompletedUserGeneratedVideoByRenderSpecHash = `-- name: GetCompletedUserGeneratedVideoByRenderSpecHash :one
+SELECT id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at, render_options, render_spec_hash, subtitle_srt_filename, subtitle_vtt_filename, render_batch_id, watermarked FROM user_generated_videos
+WHERE user_id = $1 AND render_spec_hash = $2 AND status = 'completed'
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetCompletedUserGeneratedVideoByRenderSpecHashParams struct {
+	UserID         pgtype.UUID `json:"user_id"`
+	RenderSpecHash *string     `json:"render_spec_hash"`
+}
+
+func (q *Queries) GetCompletedUserGeneratedVideoByRenderSpecHash(ctx context.Context, arg *GetCompletedUserGeneratedVideoByRenderSpecHashParams) (*UserGeneratedVideo, error) {
+	row := q.db.QueryRow(ctx, GetCompletedUserGeneratedVideoByRenderSpecHash, arg.UserID, arg.RenderSpecHash)
+	var i UserGeneratedVideo
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.AiAvatarVideoID,
+		&i.OverlayText,
+		&i.GeneratedVideoFilename,
+		&i.ThumbnailFilename,
+		&i.Status,
+		&i.ErrorMessage,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RenderOptions,
+		&i.RenderSpecHash,
+		&i.SubtitleSrtFilename,
+		&i.SubtitleVttFilename,
+		&i.RenderBatchID,
+		&i.Watermarked,
 	)
 	return &i, err
 }
 
 const GetUserGeneratedVideoByID = `-- name: GetUserGeneratedVideoByID :one
-SELECT id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at FROM user_generated_videos WHERE id = $1
+SELECT id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at, render_options, render_spec_hash, subtitle_srt_filename, subtitle_vtt_filename, render_batch_id, watermarked FROM user_generated_videos WHERE id = $1
 `
 
 func (q *Queries) GetUserGeneratedVideoByID(ctx context.Context, id uuid.UUID) (*UserGeneratedVideo, error) {
@@ -80,12 +134,59 @@ func (q *Queries) GetUserGeneratedVideoByID(ctx context.Context, id uuid.UUID) (
 		&i.ErrorMessage,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RenderOptions,
+		&i.RenderSpecHash,
+		&i.SubtitleSrtFilename,
+		&i.SubtitleVttFilename,
+		&i.RenderBatchID,
+		&i.Watermarked,
 	)
 	return &i, err
 }
 
+const GetUserGeneratedVideosByRenderBatchID = `-- name: GetUserGeneratedVideosByRenderBatchID :many
+SELECT id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at, render_options, render_spec_hash, subtitle_srt_filename, subtitle_vtt_filename, render_batch_id, watermarked FROM user_generated_videos WHERE render_batch_id = $1 ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) GetUserGeneratedVideosByRenderBatchID(ctx context.Context, renderBatchID pgtype.UUID) ([]*UserGeneratedVideo, error) {
+	rows, err := q.db.Query(ctx, GetUserGeneratedVideosByRenderBatchID, renderBatchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*UserGeneratedVideo{}
+	for rows.Next() {
+		var i UserGeneratedVideo
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.AiAvatarVideoID,
+			&i.OverlayText,
+			&i.GeneratedVideoFilename,
+			&i.ThumbnailFilename,
+			&i.Status,
+			&i.ErrorMessage,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RenderOptions,
+			&i.RenderSpecHash,
+			&i.SubtitleSrtFilename,
+			&i.SubtitleVttFilename,
+			&i.RenderBatchID,
+			&i.Watermarked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const GetUserGeneratedVideosByUserID = `-- name: GetUserGeneratedVideosByUserID :many
-SELECT id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at FROM user_generated_videos WHERE user_id = $1 ORDER BY created_at DESC
+SELECT id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at, render_options, render_spec_hash, subtitle_srt_filename, subtitle_vtt_filename, render_batch_id, watermarked FROM user_generated_videos WHERE user_id = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetUserGeneratedVideosByUserID(ctx context.Context, userID pgtype.UUID) ([]*UserGeneratedVideo, error) {
@@ -108,6 +209,12 @@ func (q *Queries) GetUserGeneratedVideosByUserID(ctx context.Context, userID pgt
 			&i.ErrorMessage,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RenderOptions,
+			&i.RenderSpecHash,
+			&i.SubtitleSrtFilename,
+			&i.SubtitleVttFilename,
+			&i.RenderBatchID,
+			&i.Watermarked,
 		); err != nil {
 			return nil, err
 		}
@@ -121,9 +228,15 @@ func (q *Queries) GetUserGeneratedVideosByUserID(ctx context.Context, userID pgt
 
 const UpdateUserGeneratedVideoFilenames = `-- name: UpdateUserGeneratedVideoFilenames :one
 UPDATE user_generated_videos 
-SET generated_video_filename = $2, thumbnail_filename = $3, status = $4, updated_at = NOW()
-WHERE id = $1
-RETURNING id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at
+SET
+    generated_video_filename = $2,
+    thumbnail_filename = $3,
+    status = $4,
+    subtitle_srt_filename = $5,
+    subtitle_vtt_filename = $6,
+    updated_at = NOW()
+WHERE id = $1 AND status = 'processing'
+RETURNING id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at, render_options, render_spec_hash, subtitle_srt_filename, subtitle_vtt_filename, render_batch_id, watermarked
 `
 
 type UpdateUserGeneratedVideoFilenamesParams struct {
@@ -131,6 +244,8 @@ type UpdateUserGeneratedVideoFilenamesParams struct {
 	GeneratedVideoFilename string    `json:"generated_video_filename"`
 	ThumbnailFilename      string    `json:"thumbnail_filename"`
 	Status                 *string   `json:"status"`
+	SubtitleSrtFilename    *string   `json:"subtitle_srt_filename"`
+	SubtitleVttFilename    *string   `json:"subtitle_vtt_filename"`
 }
 
 func (q *Queries) UpdateUserGeneratedVideoFilenames(ctx context.Context, arg *UpdateUserGeneratedVideoFilenamesParams) (*UserGeneratedVideo, error) {
@@ -139,6 +254,8 @@ func (q *Queries) UpdateUserGeneratedVideoFilenames(ctx context.Context, arg *Up
 		arg.GeneratedVideoFilename,
 		arg.ThumbnailFilename,
 		arg.Status,
+		arg.SubtitleSrtFilename,
+		arg.SubtitleVttFilename,
 	)
 	var i UserGeneratedVideo
 	err := row.Scan(
@@ -152,6 +269,12 @@ func (q *Queries) UpdateUserGeneratedVideoFilenames(ctx context.Context, arg *Up
 		&i.ErrorMessage,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RenderOptions,
+		&i.RenderSpecHash,
+		&i.SubtitleSrtFilename,
+		&i.SubtitleVttFilename,
+		&i.RenderBatchID,
+		&i.Watermarked,
 	)
 	return &i, err
 }
@@ -159,8 +282,8 @@ func (q *Queries) UpdateUserGeneratedVideoFilenames(ctx context.Context, arg *Up
 const UpdateUserGeneratedVideoStatus = `-- name: UpdateUserGeneratedVideoStatus :one
 UPDATE user_generated_videos 
 SET status = $2, error_message = $3, updated_at = NOW()
-WHERE id = $1
-RETURNING id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at
+WHERE id = $1 AND status = 'processing'
+RETURNING id, user_id, ai_avatar_video_id, overlay_text, generated_video_filename, thumbnail_filename, status, error_message, created_at, updated_at, render_options, render_spec_hash, subtitle_srt_filename, subtitle_vtt_filename, render_batch_id, watermarked
 `
 
 type UpdateUserGeneratedVideoStatusParams struct {
@@ -183,6 +306,12 @@ func (q *Queries) UpdateUserGeneratedVideoStatus(ctx context.Context, arg *Updat
 		&i.ErrorMessage,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RenderOptions,
+		&i.RenderSpecHash,
+		&i.SubtitleSrtFilename,
+		&i.SubtitleVttFilename,
+		&i.RenderBatchID,
+		&i.Watermarked,
 	)
 	return &i, err
 }
@@ -19,11 +19,76 @@ const (
 	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
+// Defines values for CaptionOptionsEffect.
+const (
+	Highlight CaptionOptionsEffect = "highlight"
+	Reveal    CaptionOptionsEffect = "reveal"
+)
+
+// Defines values for CaptionOptionsMode.
+const (
+	Animated CaptionOptionsMode = "animated"
+	Static   CaptionOptionsMode = "static"
+)
+
+// Defines values for CaptionOptionsUnit.
+const (
+	Phrase CaptionOptionsUnit = "phrase"
+	Word   CaptionOptionsUnit = "word"
+)
+
+// Defines values for OutputProfile.
+const (
+	Reels  OutputProfile = "reels"
+	Shorts OutputProfile = "shorts"
+	Square OutputProfile = "square"
+	Tiktok OutputProfile = "tiktok"
+)
+
+// Defines values for OverlayStyleAlignment.
+const (
+	OverlayStyleAlignmentCenter OverlayStyleAlignment = "center"
+	OverlayStyleAlignmentLeft   OverlayStyleAlignment = "left"
+	OverlayStyleAlignmentRight  OverlayStyleAlignment = "right"
+)
+
+// Defines values for OverlayStyleVerticalAnchor.
+const (
+	OverlayStyleVerticalAnchorBottom OverlayStyleVerticalAnchor = "bottom"
+	OverlayStyleVerticalAnchorCenter OverlayStyleVerticalAnchor = "center"
+	OverlayStyleVerticalAnchorTop    OverlayStyleVerticalAnchor = "top"
+)
+
+// Defines values for PromptTemplateStyle.
+const (
+	Controversial PromptTemplateStyle = "controversial"
+	Listicle      PromptTemplateStyle = "listicle"
+	Pov           PromptTemplateStyle = "pov"
+	Question      PromptTemplateStyle = "question"
+	Storytime     PromptTemplateStyle = "storytime"
+)
+
+// Defines values for RenderBatchStatus.
+const (
+	RenderBatchStatusCompleted          RenderBatchStatus = "completed"
+	RenderBatchStatusFailed             RenderBatchStatus = "failed"
+	RenderBatchStatusPartiallyCompleted RenderBatchStatus = "partially_completed"
+	RenderBatchStatusProcessing         RenderBatchStatus = "processing"
+)
+
+// Defines values for SubtitleMode.
+const (
+	BurnedIn        SubtitleMode = "burned_in"
+	BurnedInAndSoft SubtitleMode = "burned_in_and_soft"
+	Soft            SubtitleMode = "soft"
+)
+
 // Defines values for UserGeneratedVideoStatus.
 const (
-	Completed  UserGeneratedVideoStatus = "completed"
-	Failed     UserGeneratedVideoStatus = "failed"
-	Processing UserGeneratedVideoStatus = "processing"
+	UserGeneratedVideoStatusCancelled  UserGeneratedVideoStatus = "cancelled"
+	UserGeneratedVideoStatusCompleted  UserGeneratedVideoStatus = "completed"
+	UserGeneratedVideoStatusFailed     UserGeneratedVideoStatus = "failed"
+	UserGeneratedVideoStatusProcessing UserGeneratedVideoStatus = "processing"
 )
 
 // AIAvatarVideo defines model for AIAvatarVideo.
@@ -50,12 +115,146 @@ type AIAvatarVideosResponse struct {
 	Videos []AIAvatarVideo `json:"videos"`
 }
 
+// BrandKit Saved font, colours, logo and background image. A render that names the kit in brand_kit_id uses its font and colours wherever its style and captions leave them unset, and its end card can use the logo and background image.
+type BrandKit struct {
+	// Background The background image uploaded to a brand kit, used by end cards
+	Background *BrandKitBackground `json:"background,omitempty"`
+
+	// BackgroundColor Colour of the box behind the overlay text (omitted for no box)
+	BackgroundColor *string `json:"background_color,omitempty"`
+
+	// CreatedAt When the brand kit was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// Font The font uploaded to a brand kit
+	Font *BrandKitFont `json:"font,omitempty"`
+
+	// FontColor Overlay text colour (omitted for the default)
+	FontColor *string `json:"font_color,omitempty"`
+
+	// HighlightColor Colour of the current word in animated captions (omitted for the default)
+	HighlightColor *string `json:"highlight_color,omitempty"`
+
+	// Id Unique identifier for the brand kit
+	Id openapi_types.UUID `json:"id"`
+
+	// Logo The logo uploaded to a brand kit
+	Logo *BrandKitLogo `json:"logo,omitempty"`
+
+	// Name Name of the brand kit
+	Name string `json:"name"`
+
+	// StrokeColor Overlay text outline colour (omitted for the default)
+	StrokeColor *string `json:"stroke_color,omitempty"`
+
+	// UpdatedAt When the brand kit was last updated
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BrandKitBackground The background image uploaded to a brand kit, used by end cards
+type BrandKitBackground struct {
+	// SizeBytes Size of the image in bytes
+	SizeBytes int `json:"size_bytes"`
+
+	// Url Signed CloudFront URL for the image (expires after 24 hours)
+	Url string `json:"url"`
+}
+
+// BrandKitFont The font uploaded to a brand kit
+type BrandKitFont struct {
+	// Family Family name read from the font file
+	Family string `json:"family"`
+
+	// Licensed Whether the uploader confirmed they hold a licence to use the font in videos
+	Licensed bool `json:"licensed"`
+
+	// SizeBytes Size of the font file in bytes
+	SizeBytes int `json:"size_bytes"`
+
+	// Url Signed CloudFront URL for the font file (expires after 24 hours)
+	Url string `json:"url"`
+}
+
+// BrandKitLogo The logo uploaded to a brand kit
+type BrandKitLogo struct {
+	// SizeBytes Size of the PNG in bytes
+	SizeBytes int `json:"size_bytes"`
+
+	// Url Signed CloudFront URL for the logo (expires after 24 hours)
+	Url string `json:"url"`
+}
+
+// BrandKitRequest defines model for BrandKitRequest.
+type BrandKitRequest struct {
+	// BackgroundColor Colour of a box behind the overlay text as #RRGGBB or #RRGGBBAA; omit for no box
+	BackgroundColor *string `json:"background_color,omitempty"`
+
+	// FontColor Overlay text colour as #RRGGBB or #RRGGBBAA; omit for the default
+	FontColor *string `json:"font_color,omitempty"`
+
+	// HighlightColor Colour of the current word in animated captions as #RRGGBB or #RRGGBBAA; omit for the default
+	HighlightColor *string `json:"highlight_color,omitempty"`
+
+	// Name Name of the brand kit, usually the brand or client it is for
+	Name string `json:"name"`
+
+	// StrokeColor Overlay text outline colour as #RRGGBB or #RRGGBBAA; omit for the default
+	StrokeColor *string `json:"stroke_color,omitempty"`
+}
+
+// BrandKitsResponse defines model for BrandKitsResponse.
+type BrandKitsResponse struct {
+	BrandKits []BrandKit `json:"brand_kits"`
+}
+
+// CaptionOptions How the overlay text is animated. Omit for a single static block of text.
+type CaptionOptions struct {
+	// Effect highlight shows the full text and colours the current step; reveal shows the text up to and including the current step
+	Effect *CaptionOptionsEffect `json:"effect,omitempty"`
+
+	// HighlightColor Colour of the current step in highlight mode as #RRGGBB or #RRGGBBAA
+	HighlightColor *string `json:"highlight_color,omitempty"`
+
+	// Mode static draws the whole text for the length of the video; animated steps through it word by word or phrase by phrase
+	Mode *CaptionOptionsMode `json:"mode,omitempty"`
+
+	// Timings Explicit start time in seconds of each step, in order. Must have one entry per word or phrase; overrides words_per_second.
+	Timings *[]float64 `json:"timings,omitempty"`
+
+	// Unit What each animation step covers. Phrases end at punctuation (, . ! ? ; :) and line breaks.
+	Unit *CaptionOptionsUnit `json:"unit,omitempty"`
+
+	// WordsPerSecond Pace of the animation when timings are not given
+	WordsPerSecond *float64 `json:"words_per_second,omitempty"`
+}
+
+// CaptionOptionsEffect highlight shows the full text and colours the current step; reveal shows the text up to and including the current step
+type CaptionOptionsEffect string
+
+// CaptionOptionsMode static draws the whole text for the length of the video; animated steps through it word by word or phrase by phrase
+type CaptionOptionsMode string
+
+// CaptionOptionsUnit What each animation step covers. Phrases end at punctuation (, . ! ? ; :) and line breaks.
+type CaptionOptionsUnit string
+
 // CheckoutSessionResponse defines model for CheckoutSessionResponse.
 type CheckoutSessionResponse struct {
 	// CheckoutUrl Stripe checkout session URL
 	CheckoutUrl string `json:"checkout_url"`
 }
 
+// ClipOptions Which part of the AI avatar video to use and how long the render is. Omit to use the whole video. A duration shorter than the clip trims it; a longer one loops the clip, crossfading at each join.
+type ClipOptions struct {
+	// DurationSeconds Length of the rendered video; defaults to the length of the clip. May be at most 20 times the clip's length.
+	DurationSeconds *float64 `json:"duration_seconds,omitempty"`
+
+	// EndSeconds Offset into the AI avatar video the clip ends at; defaults to the end of the video. The clip must be at least 1 second long.
+	EndSeconds *float64 `json:"end_seconds,omitempty"`
+
+	// StartSeconds Offset into the AI avatar video the clip starts at
+	StartSeconds *float64 `json:"start_seconds,omitempty"`
+}
+
 // CreateCheckoutSessionRequest defines model for CreateCheckoutSessionRequest.
 type CreateCheckoutSessionRequest struct {
 	// CancelUrl URL to redirect to if payment is canceled
@@ -74,13 +273,67 @@ type CreateCustomerPortalRequest struct {
 	ReturnUrl string `json:"return_url"`
 }
 
+// CreateRenderBatchRequest defines model for CreateRenderBatchRequest.
+type CreateRenderBatchRequest struct {
+	// AiAvatarVideoIds AI avatar videos to render every overlay text over
+	AiAvatarVideoIds []openapi_types.UUID `json:"ai_avatar_video_ids"`
+
+	// BrandKitId Brand kit whose font and colours are used wherever style and captions leave them unset. The kit is read when the render is queued, so later changes to it do not affect the render.
+	BrandKitId *openapi_types.UUID `json:"brand_kit_id,omitempty"`
+
+	// Captions How the overlay text is animated. Omit for a single static block of text.
+	Captions *CaptionOptions `json:"captions,omitempty"`
+
+	// Clip Which part of the AI avatar video to use and how long the render is. Omit to use the whole video. A duration shorter than the clip trims it; a longer one loops the clip, crossfading at each join.
+	Clip *ClipOptions `json:"clip,omitempty"`
+
+	// EndCard A closing call-to-action card added after the avatar clip, crossfading in over its last half second. The card matches the video's resolution, and the video's audio fades out under it. Its text uses the overlay font and colours. It needs at least a headline, subtitle, logo or background image.
+	EndCard *EndCard `json:"end_card,omitempty"`
+
+	// HookIds Saved hooks whose text is used as overlay text
+	HookIds *[]openapi_types.UUID `json:"hook_ids,omitempty"`
+
+	// OutputProfiles Extra platform variants to render alongside each source-resolution video
+	OutputProfiles *[]OutputProfile `json:"output_profiles,omitempty"`
+
+	// Style How the overlay text is drawn. Omitted fields use the defaults shown.
+	Style *OverlayStyle `json:"style,omitempty"`
+
+	// SubtitleMode How the overlay text is delivered. burned_in draws it onto the video; soft leaves the picture clean and carries the text as a subtitle track in the MP4 instead; burned_in_and_soft does both. soft and burned_in_and_soft also publish SRT and WebVTT sidecar files for platforms with native captions.
+	SubtitleMode *SubtitleMode `json:"subtitle_mode,omitempty"`
+
+	// Texts Raw overlay texts, rendered after the hooks. At least one hook or text is required.
+	Texts *[]string `json:"texts,omitempty"`
+}
+
 // CreateUserGeneratedVideoRequest defines model for CreateUserGeneratedVideoRequest.
 type CreateUserGeneratedVideoRequest struct {
 	// AiAvatarVideoId ID of the AI avatar video to use as base
 	AiAvatarVideoId openapi_types.UUID `json:"ai_avatar_video_id"`
 
+	// BrandKitId Brand kit whose font and colours are used wherever style and captions leave them unset. The kit is read when the render is queued, so later changes to it do not affect the render.
+	BrandKitId *openapi_types.UUID `json:"brand_kit_id,omitempty"`
+
+	// Captions How the overlay text is animated. Omit for a single static block of text.
+	Captions *CaptionOptions `json:"captions,omitempty"`
+
+	// Clip Which part of the AI avatar video to use and how long the render is. Omit to use the whole video. A duration shorter than the clip trims it; a longer one loops the clip, crossfading at each join.
+	Clip *ClipOptions `json:"clip,omitempty"`
+
+	// EndCard A closing call-to-action card added after the avatar clip, crossfading in over its last half second. The card matches the video's resolution, and the video's audio fades out under it. Its text uses the overlay font and colours. It needs at least a headline, subtitle, logo or background image.
+	EndCard *EndCard `json:"end_card,omitempty"`
+
+	// OutputProfiles Extra platform variants to render alongside the source-resolution video
+	OutputProfiles *[]OutputProfile `json:"output_profiles,omitempty"`
+
 	// OverlayText Text to overlay on the video
 	OverlayText string `json:"overlay_text"`
+
+	// Style How the overlay text is drawn. Omitted fields use the defaults shown.
+	Style *OverlayStyle `json:"style,omitempty"`
+
+	// SubtitleMode How the overlay text is delivered. burned_in draws it onto the video; soft leaves the picture clean and carries the text as a subtitle track in the MP4 instead; burned_in_and_soft does both. soft and burned_in_and_soft also publish SRT and WebVTT sidecar files for platforms with native captions.
+	SubtitleMode *SubtitleMode `json:"subtitle_mode,omitempty"`
 }
 
 // CustomerPortalResponse defines model for CustomerPortalResponse.
@@ -89,6 +342,27 @@ type CustomerPortalResponse struct {
 	PortalUrl string `json:"portal_url"`
 }
 
+// EndCard A closing call-to-action card added after the avatar clip, crossfading in over its last half second. The card matches the video's resolution, and the video's audio fades out under it. Its text uses the overlay font and colours. It needs at least a headline, subtitle, logo or background image.
+type EndCard struct {
+	// BackgroundColor Colour filling the card as #RRGGBB
+	BackgroundColor *string `json:"background_color,omitempty"`
+
+	// DurationSeconds How long the card is shown for after the clip
+	DurationSeconds *float64 `json:"duration_seconds,omitempty"`
+
+	// Headline Large text in the middle of the card
+	Headline *string `json:"headline,omitempty"`
+
+	// ShowBackgroundImage Fill the card with the brand kit's background image instead of background_color. Needs brand_kit_id.
+	ShowBackgroundImage *bool `json:"show_background_image,omitempty"`
+
+	// ShowLogo Show the brand kit's logo above the text. Needs brand_kit_id.
+	ShowLogo *bool `json:"show_logo,omitempty"`
+
+	// Subtitle Smaller text under the headline
+	Subtitle *string `json:"subtitle,omitempty"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	// Details Additional error details
@@ -108,6 +382,9 @@ type GenerateHooksRequest struct {
 
 	// Prompt The topic or theme for generating hooks
 	Prompt string `json:"prompt"`
+
+	// PromptTemplateId Active prompt template to write the hooks with, at its latest version; omit for the built-in prompt
+	PromptTemplateId *openapi_types.UUID `json:"prompt_template_id,omitempty"`
 }
 
 // GenerateHooksResponse defines model for GenerateHooksResponse.
@@ -116,6 +393,18 @@ type GenerateHooksResponse struct {
 	Hooks []Hook `json:"hooks"`
 }
 
+// GeneratedHookEvent defines model for GeneratedHookEvent.
+type GeneratedHookEvent struct {
+	// Id ID of the saved hook
+	Id openapi_types.UUID `json:"id"`
+
+	// Index Position of the hook in the generation, starting at 0
+	Index int `json:"index"`
+
+	// Text The hook text content
+	Text string `json:"text"`
+}
+
 // GetHooksResponse defines model for GetHooksResponse.
 type GetHooksResponse struct {
 	// Hooks Array of user's hooks
@@ -149,10 +438,227 @@ type Hook struct {
 	// Id Unique identifier for the hook
 	Id openapi_types.UUID `json:"id"`
 
+	// ParentHookId The hook this hook was refined from (omitted for generated hooks, or once the parent is deleted)
+	ParentHookId *openapi_types.UUID `json:"parent_hook_id,omitempty"`
+
+	// RefinementInstruction The instruction the parent hook was refined with (omitted for generated hooks)
+	RefinementInstruction *string `json:"refinement_instruction,omitempty"`
+
 	// Text The hook text content
 	Text string `json:"text"`
 }
 
+// HookGenerationError defines model for HookGenerationError.
+type HookGenerationError struct {
+	// DeletedHookIds IDs of the hooks sent earlier in the stream that have been deleted
+	DeletedHookIds []openapi_types.UUID `json:"deleted_hook_ids"`
+
+	// Error Error type
+	Error string `json:"error"`
+
+	// Message Human-readable error message
+	Message string `json:"message"`
+}
+
+// HookGenerationSummary defines model for HookGenerationSummary.
+type HookGenerationSummary struct {
+	// CreditsUsed Credits charged for the generation
+	CreditsUsed int `json:"credits_used"`
+
+	// GenerationId ID shared by the hooks of this generation
+	GenerationId openapi_types.UUID `json:"generation_id"`
+
+	// Hooks The generated hooks, in order
+	Hooks []Hook `json:"hooks"`
+
+	// RemainingCredits The user's credits after the generation
+	RemainingCredits int `json:"remaining_credits"`
+}
+
+// OutputProfile A named output format. tiktok and reels are 1080x1920 cropped to fill the frame, shorts is 1080x1920 letterboxed to keep the whole picture and square is 1080x1080 letterboxed.
+type OutputProfile string
+
+// OverlayBackground A filled box drawn behind the overlay text
+type OverlayBackground struct {
+	// Color Box colour as #RRGGBB or #RRGGBBAA
+	Color string `json:"color"`
+
+	// Padding Space between the text and the edge of the box in pixels
+	Padding *int `json:"padding,omitempty"`
+}
+
+// OverlayStyle How the overlay text is drawn. Omitted fields use the defaults shown.
+type OverlayStyle struct {
+	// Alignment Horizontal alignment of the text block and its lines
+	Alignment *OverlayStyleAlignment `json:"alignment,omitempty"`
+
+	// Background A filled box drawn behind the overlay text
+	Background *OverlayBackground `json:"background,omitempty"`
+
+	// FontColor Text fill colour as #RRGGBB or #RRGGBBAA
+	FontColor *string `json:"font_color,omitempty"`
+
+	// FontFamily Font from the server's font library (tiktok-display, dejavu-sans, liberation-sans or liberation-serif)
+	FontFamily *string `json:"font_family,omitempty"`
+
+	// FontSize Font size in pixels; the text is shrunk automatically if it would wrap past max_lines
+	FontSize *int `json:"font_size,omitempty"`
+
+	// MaxLines Most lines the text may wrap to at the frame width before the font is shrunk
+	MaxLines *int `json:"max_lines,omitempty"`
+
+	// StrokeColor Text outline colour as #RRGGBB or #RRGGBBAA
+	StrokeColor *string `json:"stroke_color,omitempty"`
+
+	// StrokeWidth Text outline width in pixels (0 for no outline)
+	StrokeWidth *int `json:"stroke_width,omitempty"`
+
+	// VerticalAnchor Where the text block sits vertically (ignored when y is set)
+	VerticalAnchor *OverlayStyleVerticalAnchor `json:"vertical_anchor,omitempty"`
+
+	// Y Custom offset of the text block from the top of the frame in pixels, overriding vertical_anchor
+	Y *int `json:"y,omitempty"`
+}
+
+// OverlayStyleAlignment Horizontal alignment of the text block and its lines
+type OverlayStyleAlignment string
+
+// OverlayStyleVerticalAnchor Where the text block sits vertically (ignored when y is set)
+type OverlayStyleVerticalAnchor string
+
+// PromptTemplate defines model for PromptTemplate.
+type PromptTemplate struct {
+	// Active Whether users can generate hooks with the template
+	Active bool `json:"active"`
+
+	// CreatedAt When the template was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// Id Unique identifier for the prompt template
+	Id openapi_types.UUID `json:"id"`
+
+	// Instructions Instructions of the latest version
+	Instructions string `json:"instructions"`
+
+	// Name Name of the template
+	Name string `json:"name"`
+
+	// Style Style of hook a prompt template writes
+	Style PromptTemplateStyle `json:"style"`
+
+	// UpdatedAt When the template was last updated
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Version The latest version, which hook generation uses
+	Version int `json:"version"`
+}
+
+// PromptTemplateRequest defines model for PromptTemplateRequest.
+type PromptTemplateRequest struct {
+	// Active Whether users can generate hooks with the template
+	Active *bool `json:"active,omitempty"`
+
+	// Instructions Instructions and examples for the model. The output format, the user's topic and the number of hooks are added after them, so they should only describe the style.
+	Instructions string `json:"instructions"`
+
+	// Name Unique name of the template shown to users
+	Name string `json:"name"`
+
+	// Style Style of hook a prompt template writes
+	Style PromptTemplateStyle `json:"style"`
+}
+
+// PromptTemplateStyle Style of hook a prompt template writes
+type PromptTemplateStyle string
+
+// PromptTemplateVersion defines model for PromptTemplateVersion.
+type PromptTemplateVersion struct {
+	// CreatedAt When this version was written
+	CreatedAt time.Time `json:"created_at"`
+
+	// CreatedBy The admin who wrote this version (omitted for the starting templates)
+	CreatedBy *openapi_types.UUID `json:"created_by,omitempty"`
+
+	// Id Unique identifier for the version, recorded on the hooks generated with it
+	Id openapi_types.UUID `json:"id"`
+
+	// Instructions The instructions of this version
+	Instructions string `json:"instructions"`
+
+	// Version Version number, starting at 1
+	Version int `json:"version"`
+}
+
+// PromptTemplateVersionsResponse defines model for PromptTemplateVersionsResponse.
+type PromptTemplateVersionsResponse struct {
+	// Versions Every version of the template, newest first
+	Versions []PromptTemplateVersion `json:"versions"`
+}
+
+// PromptTemplatesResponse defines model for PromptTemplatesResponse.
+type PromptTemplatesResponse struct {
+	PromptTemplates []PromptTemplate `json:"prompt_templates"`
+}
+
+// RefineHookRequest defines model for RefineHookRequest.
+type RefineHookRequest struct {
+	// Instruction How the variants should differ from the hook
+	Instruction string `json:"instruction"`
+
+	// NumVariants Number of variants to generate
+	NumVariants *int `json:"num_variants,omitempty"`
+}
+
+// RenderBatch defines model for RenderBatch.
+type RenderBatch struct {
+	// CancelledCount Renders that were cancelled (their credits are refunded)
+	CancelledCount int `json:"cancelled_count"`
+
+	// CompletedCount Renders that finished successfully
+	CompletedCount int `json:"completed_count"`
+
+	// CreatedAt When the batch was requested
+	CreatedAt time.Time `json:"created_at"`
+
+	// CreditsCharged Credits held for the whole batch when it was created
+	CreditsCharged int `json:"credits_charged"`
+
+	// FailedCount Renders that failed (their credits are refunded)
+	FailedCount int `json:"failed_count"`
+
+	// Id Unique identifier for the batch
+	Id openapi_types.UUID `json:"id"`
+
+	// ProcessingCount Renders still queued or running
+	ProcessingCount int `json:"processing_count"`
+
+	// RenderCount Number of renders the batch fanned out into
+	RenderCount int `json:"render_count"`
+
+	// Status processing while any render is still running; once they have all finished, completed if every render succeeded, failed if none did and partially_completed otherwise
+	Status RenderBatchStatus `json:"status"`
+}
+
+// RenderBatchStatus processing while any render is still running; once they have all finished, completed if every render succeeded, failed if none did and partially_completed otherwise
+type RenderBatchStatus string
+
+// RenderBatchResponse defines model for RenderBatchResponse.
+type RenderBatchResponse struct {
+	Batch RenderBatch `json:"batch"`
+
+	// Videos Every video in the batch
+	Videos []UserGeneratedVideo `json:"videos"`
+}
+
+// RenderBatchesResponse defines model for RenderBatchesResponse.
+type RenderBatchesResponse struct {
+	// Batches The user's render batches, newest first
+	Batches []RenderBatch `json:"batches"`
+}
+
+// SubtitleMode How the overlay text is delivered. burned_in draws it onto the video; soft leaves the picture clean and carries the text as a subtitle track in the MP4 instead; burned_in_and_soft does both. soft and burned_in_and_soft also publish SRT and WebVTT sidecar files for platforms with native captions.
+type SubtitleMode string
+
 // UserAccount defines model for UserAccount.
 type UserAccount struct {
 	// BillingCustomerId External billing system customer ID
@@ -188,26 +694,50 @@ type UserGeneratedVideo struct {
 	// CreatedAt When the video was created
 	CreatedAt time.Time `json:"created_at"`
 
+	// ErrorMessage Why rendering failed, written for the user (only set when status is failed). Temporary problems such as storage or network errors are retried automatically and only end in failed once retries run out; either way any credits the render used are refunded.
+	ErrorMessage *string `json:"error_message"`
+
+	// EtaSeconds Estimated seconds until the render finishes (only set while processing)
+	EtaSeconds *int `json:"eta_seconds"`
+
 	// Id Unique identifier for the user-generated video
 	Id openapi_types.UUID `json:"id"`
 
 	// OverlayText Text that was overlaid on the video
 	OverlayText string `json:"overlay_text"`
 
-	// Status Current processing status
+	// ProgressPercent How much of the render has been encoded (only set on single-video responses)
+	ProgressPercent *float64 `json:"progress_percent,omitempty"`
+
+	// RenderBatchId The render batch the video was created in (null for single renders)
+	RenderBatchId *openapi_types.UUID `json:"render_batch_id"`
+
+	// Status Current processing status (stays processing while a render that hit a temporary problem waits to be retried)
 	Status UserGeneratedVideoStatus `json:"status"`
 
-	// ThumbnailUrl CloudFront URL for the video thumbnail
-	ThumbnailUrl string `json:"thumbnail_url"`
+	// SubtitleSrtUrl Signed CloudFront URL for the SRT sidecar (only set once completed, when the subtitle mode includes soft subtitles)
+	SubtitleSrtUrl *string `json:"subtitle_srt_url,omitempty"`
+
+	// SubtitleVttUrl Signed CloudFront URL for the WebVTT sidecar (only set once completed, when the subtitle mode includes soft subtitles)
+	SubtitleVttUrl *string `json:"subtitle_vtt_url,omitempty"`
+
+	// ThumbnailUrl Signed CloudFront URL for the video thumbnail (only set once the video is completed)
+	ThumbnailUrl *string `json:"thumbnail_url,omitempty"`
 
 	// UserId ID of the user who generated the video
 	UserId openapi_types.UUID `json:"user_id"`
 
-	// VideoUrl CloudFront URL for the generated video
-	VideoUrl string `json:"video_url"`
+	// Variants Platform variants rendered from the requested output profiles
+	Variants *[]UserGeneratedVideoVariant `json:"variants,omitempty"`
+
+	// VideoUrl Signed CloudFront URL for the generated video (only set once the video is completed)
+	VideoUrl *string `json:"video_url,omitempty"`
+
+	// Watermarked Whether the video carries the free plan watermark; re-render it after upgrading for a clean copy
+	Watermarked bool `json:"watermarked"`
 }
 
-// UserGeneratedVideoStatus Current processing status
+// UserGeneratedVideoStatus Current processing status (stays processing while a render that hit a temporary problem waits to be retried)
 type UserGeneratedVideoStatus string
 
 // UserGeneratedVideoResponse defines model for UserGeneratedVideoResponse.
@@ -215,12 +745,48 @@ type UserGeneratedVideoResponse struct {
 	Video UserGeneratedVideo `json:"video"`
 }
 
+// UserGeneratedVideoVariant defines model for UserGeneratedVideoVariant.
+type UserGeneratedVideoVariant struct {
+	// Height Frame height of the variant in pixels
+	Height int `json:"height"`
+
+	// Profile A named output format. tiktok and reels are 1080x1920 cropped to fill the frame, shorts is 1080x1920 letterboxed to keep the whole picture and square is 1080x1080 letterboxed.
+	Profile OutputProfile `json:"profile"`
+
+	// VideoUrl Signed CloudFront URL for the variant (only set once the video is completed)
+	VideoUrl *string `json:"video_url,omitempty"`
+
+	// Width Frame width of the variant in pixels
+	Width int `json:"width"`
+}
+
 // UserGeneratedVideosResponse defines model for UserGeneratedVideosResponse.
 type UserGeneratedVideosResponse struct {
 	// Videos List of user-generated videos
 	Videos []UserGeneratedVideo `json:"videos"`
 }
 
+// UploadBrandKitBackgroundMultipartBody defines parameters for UploadBrandKitBackground.
+type UploadBrandKitBackgroundMultipartBody struct {
+	// File PNG or JPEG image, at most 10 MB and 4096x4096 pixels
+	File openapi_types.File `json:"file"`
+}
+
+// UploadBrandKitFontMultipartBody defines parameters for UploadBrandKitFont.
+type UploadBrandKitFontMultipartBody struct {
+	// File TTF or OTF font file, at most 10 MB
+	File openapi_types.File `json:"file"`
+
+	// Licensed Confirms the uploader holds a licence to use the font in videos; must be true
+	Licensed bool `json:"licensed"`
+}
+
+// UploadBrandKitLogoMultipartBody defines parameters for UploadBrandKitLogo.
+type UploadBrandKitLogoMultipartBody struct {
+	// File PNG logo, at most 5 MB and 4096x4096 pixels
+	File openapi_types.File `json:"file"`
+}
+
 // GetHooksParams defines parameters for GetHooks.
 type GetHooksParams struct {
 	// Limit Number of hooks to return
@@ -236,12 +802,51 @@ type DeleteHooksBulkJSONBody struct {
 	HookIds []openapi_types.UUID `json:"hook_ids"`
 }
 
+// GetRenderBatchesParams defines parameters for GetRenderBatches.
+type GetRenderBatchesParams struct {
+	// Limit Number of batches to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Number of batches to skip
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// CreatePromptTemplateJSONRequestBody defines body for CreatePromptTemplate for application/json ContentType.
+type CreatePromptTemplateJSONRequestBody = PromptTemplateRequest
+
+// UpdatePromptTemplateJSONRequestBody defines body for UpdatePromptTemplate for application/json ContentType.
+type UpdatePromptTemplateJSONRequestBody = PromptTemplateRequest
+
+// CreateBrandKitJSONRequestBody defines body for CreateBrandKit for application/json ContentType.
+type CreateBrandKitJSONRequestBody = BrandKitRequest
+
+// UpdateBrandKitJSONRequestBody defines body for UpdateBrandKit for application/json ContentType.
+type UpdateBrandKitJSONRequestBody = BrandKitRequest
+
+// UploadBrandKitBackgroundMultipartRequestBody defines body for UploadBrandKitBackground for multipart/form-data ContentType.
+type UploadBrandKitBackgroundMultipartRequestBody UploadBrandKitBackgroundMultipartBody
+
+// UploadBrandKitFontMultipartRequestBody defines body for UploadBrandKitFont for multipart/form-data ContentType.
+type UploadBrandKitFontMultipartRequestBody UploadBrandKitFontMultipartBody
+
+// UploadBrandKitLogoMultipartRequestBody defines body for UploadBrandKitLogo for multipart/form-data ContentType.
+type UploadBrandKitLogoMultipartRequestBody UploadBrandKitLogoMultipartBody
+
 // DeleteHooksBulkJSONRequestBody defines body for DeleteHooksBulk for application/json ContentType.
 type DeleteHooksBulkJSONRequestBody DeleteHooksBulkJSONBody
 
 // GenerateHooksJSONRequestBody defines body for GenerateHooks for application/json ContentType.
 type GenerateHooksJSONRequestBody = GenerateHooksRequest
 
+// GenerateHooksStreamJSONRequestBody defines body for GenerateHooksStream for application/json ContentType.
+type GenerateHooksStreamJSONRequestBody = GenerateHooksRequest
+
+// RefineHookJSONRequestBody defines body for RefineHook for application/json ContentType.
+type RefineHookJSONRequestBody = RefineHookRequest
+
+// CreateRenderBatchJSONRequestBody defines body for CreateRenderBatch for application/json ContentType.
+type CreateRenderBatchJSONRequestBody = CreateRenderBatchRequest
+
 // CreateCheckoutSessionJSONRequestBody defines body for CreateCheckoutSession for application/json ContentType.
 type CreateCheckoutSessionJSONRequestBody = CreateCheckoutSessionRequest
 
@@ -253,9 +858,57 @@ type CreateUserGeneratedVideoJSONRequestBody = CreateUserGeneratedVideoRequest
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Get all prompt templates
+	// (GET /admin/prompt-templates)
+	GetAdminPromptTemplates(w http.ResponseWriter, r *http.Request)
+	// Create a prompt template
+	// (POST /admin/prompt-templates)
+	CreatePromptTemplate(w http.ResponseWriter, r *http.Request)
+	// Get a prompt template
+	// (GET /admin/prompt-templates/{templateId})
+	GetPromptTemplate(w http.ResponseWriter, r *http.Request, templateId openapi_types.UUID)
+	// Update a prompt template
+	// (PUT /admin/prompt-templates/{templateId})
+	UpdatePromptTemplate(w http.ResponseWriter, r *http.Request, templateId openapi_types.UUID)
+	// Get a prompt template's versions
+	// (GET /admin/prompt-templates/{templateId}/versions)
+	GetPromptTemplateVersions(w http.ResponseWriter, r *http.Request, templateId openapi_types.UUID)
 	// Get all AI avatar videos
 	// (GET /ai-avatar/videos)
 	GetAIAvatarVideos(w http.ResponseWriter, r *http.Request)
+	// Get brand kits
+	// (GET /brand-kits)
+	GetBrandKits(w http.ResponseWriter, r *http.Request)
+	// Create a brand kit
+	// (POST /brand-kits)
+	CreateBrandKit(w http.ResponseWriter, r *http.Request)
+	// Delete a brand kit
+	// (DELETE /brand-kits/{brandKitId})
+	DeleteBrandKit(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID)
+	// Get a brand kit
+	// (GET /brand-kits/{brandKitId})
+	GetBrandKit(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID)
+	// Update a brand kit
+	// (PUT /brand-kits/{brandKitId})
+	UpdateBrandKit(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID)
+	// Remove a brand kit background image
+	// (DELETE /brand-kits/{brandKitId}/background)
+	DeleteBrandKitBackground(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID)
+	// Upload a brand kit background image
+	// (PUT /brand-kits/{brandKitId}/background)
+	UploadBrandKitBackground(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID)
+	// Remove a brand kit font
+	// (DELETE /brand-kits/{brandKitId}/font)
+	DeleteBrandKitFont(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID)
+	// Upload a brand kit font
+	// (PUT /brand-kits/{brandKitId}/font)
+	UploadBrandKitFont(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID)
+	// Remove a brand kit logo
+	// (DELETE /brand-kits/{brandKitId}/logo)
+	DeleteBrandKitLogo(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID)
+	// Upload a brand kit logo
+	// (PUT /brand-kits/{brandKitId}/logo)
+	UploadBrandKitLogo(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID)
 	// Health check endpoint
 	// (GET /health)
 	GetHealth(w http.ResponseWriter, r *http.Request)
@@ -268,9 +921,27 @@ type ServerInterface interface {
 	// Generate hooks for TikTok slideshow
 	// (POST /hooks/generate)
 	GenerateHooks(w http.ResponseWriter, r *http.Request)
+	// Generate hooks for TikTok slideshow as a stream
+	// (POST /hooks/generate/stream)
+	GenerateHooksStream(w http.ResponseWriter, r *http.Request)
 	// Delete a hook
 	// (DELETE /hooks/{hookId})
 	DeleteHook(w http.ResponseWriter, r *http.Request, hookId openapi_types.UUID)
+	// Refine a hook
+	// (POST /hooks/{hookId}/refine)
+	RefineHook(w http.ResponseWriter, r *http.Request, hookId openapi_types.UUID)
+	// Get prompt templates
+	// (GET /prompt-templates)
+	GetPromptTemplates(w http.ResponseWriter, r *http.Request)
+	// Get render batches
+	// (GET /render-batches)
+	GetRenderBatches(w http.ResponseWriter, r *http.Request, params GetRenderBatchesParams)
+	// Render every hook over every avatar video
+	// (POST /render-batches)
+	CreateRenderBatch(w http.ResponseWriter, r *http.Request)
+	// Get a render batch
+	// (GET /render-batches/{batchId})
+	GetRenderBatch(w http.ResponseWriter, r *http.Request, batchId openapi_types.UUID)
 	// Create Stripe checkout session
 	// (POST /subscription/create-checkout-session)
 	CreateCheckoutSession(w http.ResponseWriter, r *http.Request)
@@ -286,6 +957,15 @@ type ServerInterface interface {
 	// Generate a video with text overlay
 	// (POST /user-generated-videos)
 	CreateUserGeneratedVideo(w http.ResponseWriter, r *http.Request)
+	// Get a user-generated video
+	// (GET /user-generated-videos/{videoId})
+	GetUserGeneratedVideo(w http.ResponseWriter, r *http.Request, videoId openapi_types.UUID)
+	// Cancel a user-generated video render
+	// (POST /user-generated-videos/{videoId}/cancel)
+	CancelUserGeneratedVideo(w http.ResponseWriter, r *http.Request, videoId openapi_types.UUID)
+	// Re-render a user-generated video
+	// (POST /user-generated-videos/{videoId}/rerender)
+	RerenderUserGeneratedVideo(w http.ResponseWriter, r *http.Request, videoId openapi_types.UUID)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -297,8 +977,8 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
-// GetAIAvatarVideos operation middleware
-func (siw *ServerInterfaceWrapper) GetAIAvatarVideos(w http.ResponseWriter, r *http.Request) {
+// GetAdminPromptTemplates operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminPromptTemplates(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
@@ -307,7 +987,7 @@ func (siw *ServerInterfaceWrapper) GetAIAvatarVideos(w http.ResponseWriter, r *h
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetAIAvatarVideos(w, r)
+		siw.Handler.GetAdminPromptTemplates(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -317,11 +997,17 @@ func (siw *ServerInterfaceWrapper) GetAIAvatarVideos(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r)
 }
 
-// GetHealth operation middleware
-func (siw *ServerInterfaceWrapper) GetHealth(w http.ResponseWriter, r *http.Request) {
+// CreatePromptTemplate operation middleware
+func (siw *ServerInterfaceWrapper) CreatePromptTemplate(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetHealth(w, r)
+		siw.Handler.CreatePromptTemplate(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -331,20 +1017,466 @@ func (siw *ServerInterfaceWrapper) GetHealth(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r)
 }
 
-// GetHooks operation middleware
-func (siw *ServerInterfaceWrapper) GetHooks(w http.ResponseWriter, r *http.Request) {
+// GetPromptTemplate operation middleware
+func (siw *ServerInterfaceWrapper) GetPromptTemplate(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
+	// ------------- Path parameter "templateId" -------------
+	var templateId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "templateId", r.PathValue("templateId"), &templateId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "templateId", Err: err})
+		return
+	}
+
 	ctx := r.Context()
 
 	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
 	r = r.WithContext(ctx)
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetHooksParams
-
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPromptTemplate(w, r, templateId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdatePromptTemplate operation middleware
+func (siw *ServerInterfaceWrapper) UpdatePromptTemplate(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "templateId" -------------
+	var templateId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "templateId", r.PathValue("templateId"), &templateId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "templateId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdatePromptTemplate(w, r, templateId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPromptTemplateVersions operation middleware
+func (siw *ServerInterfaceWrapper) GetPromptTemplateVersions(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "templateId" -------------
+	var templateId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "templateId", r.PathValue("templateId"), &templateId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "templateId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPromptTemplateVersions(w, r, templateId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAIAvatarVideos operation middleware
+func (siw *ServerInterfaceWrapper) GetAIAvatarVideos(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAIAvatarVideos(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetBrandKits operation middleware
+func (siw *ServerInterfaceWrapper) GetBrandKits(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBrandKits(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateBrandKit operation middleware
+func (siw *ServerInterfaceWrapper) CreateBrandKit(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateBrandKit(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteBrandKit operation middleware
+func (siw *ServerInterfaceWrapper) DeleteBrandKit(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "brandKitId" -------------
+	var brandKitId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "brandKitId", r.PathValue("brandKitId"), &brandKitId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "brandKitId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteBrandKit(w, r, brandKitId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetBrandKit operation middleware
+func (siw *ServerInterfaceWrapper) GetBrandKit(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "brandKitId" -------------
+	var brandKitId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "brandKitId", r.PathValue("brandKitId"), &brandKitId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "brandKitId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBrandKit(w, r, brandKitId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateBrandKit operation middleware
+func (siw *ServerInterfaceWrapper) UpdateBrandKit(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "brandKitId" -------------
+	var brandKitId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "brandKitId", r.PathValue("brandKitId"), &brandKitId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "brandKitId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateBrandKit(w, r, brandKitId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteBrandKitBackground operation middleware
+func (siw *ServerInterfaceWrapper) DeleteBrandKitBackground(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "brandKitId" -------------
+	var brandKitId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "brandKitId", r.PathValue("brandKitId"), &brandKitId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "brandKitId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteBrandKitBackground(w, r, brandKitId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UploadBrandKitBackground operation middleware
+func (siw *ServerInterfaceWrapper) UploadBrandKitBackground(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "brandKitId" -------------
+	var brandKitId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "brandKitId", r.PathValue("brandKitId"), &brandKitId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "brandKitId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UploadBrandKitBackground(w, r, brandKitId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteBrandKitFont operation middleware
+func (siw *ServerInterfaceWrapper) DeleteBrandKitFont(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "brandKitId" -------------
+	var brandKitId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "brandKitId", r.PathValue("brandKitId"), &brandKitId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "brandKitId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteBrandKitFont(w, r, brandKitId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UploadBrandKitFont operation middleware
+func (siw *ServerInterfaceWrapper) UploadBrandKitFont(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "brandKitId" -------------
+	var brandKitId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "brandKitId", r.PathValue("brandKitId"), &brandKitId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "brandKitId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UploadBrandKitFont(w, r, brandKitId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteBrandKitLogo operation middleware
+func (siw *ServerInterfaceWrapper) DeleteBrandKitLogo(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "brandKitId" -------------
+	var brandKitId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "brandKitId", r.PathValue("brandKitId"), &brandKitId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "brandKitId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteBrandKitLogo(w, r, brandKitId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UploadBrandKitLogo operation middleware
+func (siw *ServerInterfaceWrapper) UploadBrandKitLogo(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "brandKitId" -------------
+	var brandKitId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "brandKitId", r.PathValue("brandKitId"), &brandKitId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "brandKitId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UploadBrandKitLogo(w, r, brandKitId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetHealth operation middleware
+func (siw *ServerInterfaceWrapper) GetHealth(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetHealth(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetHooks operation middleware
+func (siw *ServerInterfaceWrapper) GetHooks(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetHooksParams
+
 	// ------------- Optional query parameter "limit" -------------
 
 	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
@@ -412,6 +1544,26 @@ func (siw *ServerInterfaceWrapper) GenerateHooks(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r)
 }
 
+// GenerateHooksStream operation middleware
+func (siw *ServerInterfaceWrapper) GenerateHooksStream(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GenerateHooksStream(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // DeleteHook operation middleware
 func (siw *ServerInterfaceWrapper) DeleteHook(w http.ResponseWriter, r *http.Request) {
 
@@ -443,6 +1595,149 @@ func (siw *ServerInterfaceWrapper) DeleteHook(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r)
 }
 
+// RefineHook operation middleware
+func (siw *ServerInterfaceWrapper) RefineHook(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "hookId" -------------
+	var hookId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "hookId", r.PathValue("hookId"), &hookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "hookId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RefineHook(w, r, hookId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetPromptTemplates operation middleware
+func (siw *ServerInterfaceWrapper) GetPromptTemplates(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPromptTemplates(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetRenderBatches operation middleware
+func (siw *ServerInterfaceWrapper) GetRenderBatches(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRenderBatchesParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRenderBatches(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateRenderBatch operation middleware
+func (siw *ServerInterfaceWrapper) CreateRenderBatch(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateRenderBatch(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetRenderBatch operation middleware
+func (siw *ServerInterfaceWrapper) GetRenderBatch(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "batchId" -------------
+	var batchId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "batchId", r.PathValue("batchId"), &batchId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "batchId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRenderBatch(w, r, batchId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // CreateCheckoutSession operation middleware
 func (siw *ServerInterfaceWrapper) CreateCheckoutSession(w http.ResponseWriter, r *http.Request) {
 
@@ -543,6 +1838,99 @@ func (siw *ServerInterfaceWrapper) CreateUserGeneratedVideo(w http.ResponseWrite
 	handler.ServeHTTP(w, r)
 }
 
+// GetUserGeneratedVideo operation middleware
+func (siw *ServerInterfaceWrapper) GetUserGeneratedVideo(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "videoId" -------------
+	var videoId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "videoId", r.PathValue("videoId"), &videoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "videoId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUserGeneratedVideo(w, r, videoId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CancelUserGeneratedVideo operation middleware
+func (siw *ServerInterfaceWrapper) CancelUserGeneratedVideo(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "videoId" -------------
+	var videoId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "videoId", r.PathValue("videoId"), &videoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "videoId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CancelUserGeneratedVideo(w, r, videoId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RerenderUserGeneratedVideo operation middleware
+func (siw *ServerInterfaceWrapper) RerenderUserGeneratedVideo(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "videoId" -------------
+	var videoId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "videoId", r.PathValue("videoId"), &videoId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "videoId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RerenderUserGeneratedVideo(w, r, videoId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -663,17 +2051,42 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	m.HandleFunc("GET "+options.BaseURL+"/admin/prompt-templates", wrapper.GetAdminPromptTemplates)
+	m.HandleFunc("POST "+options.BaseURL+"/admin/prompt-templates", wrapper.CreatePromptTemplate)
+	m.HandleFunc("GET "+options.BaseURL+"/admin/prompt-templates/{templateId}", wrapper.GetPromptTemplate)
+	m.HandleFunc("PUT "+options.BaseURL+"/admin/prompt-templates/{templateId}", wrapper.UpdatePromptTemplate)
+	m.HandleFunc("GET "+options.BaseURL+"/admin/prompt-templates/{templateId}/versions", wrapper.GetPromptTemplateVersions)
 	m.HandleFunc("GET "+options.BaseURL+"/ai-avatar/videos", wrapper.GetAIAvatarVideos)
+	m.HandleFunc("GET "+options.BaseURL+"/brand-kits", wrapper.GetBrandKits)
+	m.HandleFunc("POST "+options.BaseURL+"/brand-kits", wrapper.CreateBrandKit)
+	m.HandleFunc("DELETE "+options.BaseURL+"/brand-kits/{brandKitId}", wrapper.DeleteBrandKit)
+	m.HandleFunc("GET "+options.BaseURL+"/brand-kits/{brandKitId}", wrapper.GetBrandKit)
+	m.HandleFunc("PUT "+options.BaseURL+"/brand-kits/{brandKitId}", wrapper.UpdateBrandKit)
+	m.HandleFunc("DELETE "+options.BaseURL+"/brand-kits/{brandKitId}/background", wrapper.DeleteBrandKitBackground)
+	m.HandleFunc("PUT "+options.BaseURL+"/brand-kits/{brandKitId}/background", wrapper.UploadBrandKitBackground)
+	m.HandleFunc("DELETE "+options.BaseURL+"/brand-kits/{brandKitId}/font", wrapper.DeleteBrandKitFont)
+	m.HandleFunc("PUT "+options.BaseURL+"/brand-kits/{brandKitId}/font", wrapper.UploadBrandKitFont)
+	m.HandleFunc("DELETE "+options.BaseURL+"/brand-kits/{brandKitId}/logo", wrapper.DeleteBrandKitLogo)
+	m.HandleFunc("PUT "+options.BaseURL+"/brand-kits/{brandKitId}/logo", wrapper.UploadBrandKitLogo)
 	m.HandleFunc("GET "+options.BaseURL+"/health", wrapper.GetHealth)
 	m.HandleFunc("GET "+options.BaseURL+"/hooks", wrapper.GetHooks)
 	m.HandleFunc("DELETE "+options.BaseURL+"/hooks/bulk", wrapper.DeleteHooksBulk)
 	m.HandleFunc("POST "+options.BaseURL+"/hooks/generate", wrapper.GenerateHooks)
+	m.HandleFunc("POST "+options.BaseURL+"/hooks/generate/stream", wrapper.GenerateHooksStream)
 	m.HandleFunc("DELETE "+options.BaseURL+"/hooks/{hookId}", wrapper.DeleteHook)
+	m.HandleFunc("POST "+options.BaseURL+"/hooks/{hookId}/refine", wrapper.RefineHook)
+	m.HandleFunc("GET "+options.BaseURL+"/prompt-templates", wrapper.GetPromptTemplates)
+	m.HandleFunc("GET "+options.BaseURL+"/render-batches", wrapper.GetRenderBatches)
+	m.HandleFunc("POST "+options.BaseURL+"/render-batches", wrapper.CreateRenderBatch)
+	m.HandleFunc("GET "+options.BaseURL+"/render-batches/{batchId}", wrapper.GetRenderBatch)
 	m.HandleFunc("POST "+options.BaseURL+"/subscription/create-checkout-session", wrapper.CreateCheckoutSession)
 	m.HandleFunc("POST "+options.BaseURL+"/subscription/customer-portal", wrapper.CreateCustomerPortalSession)
 	m.HandleFunc("GET "+options.BaseURL+"/user", wrapper.GetUserAccount)
 	m.HandleFunc("GET "+options.BaseURL+"/user-generated-videos", wrapper.GetUserGeneratedVideos)
 	m.HandleFunc("POST "+options.BaseURL+"/user-generated-videos", wrapper.CreateUserGeneratedVideo)
+	m.HandleFunc("GET "+options.BaseURL+"/user-generated-videos/{videoId}", wrapper.GetUserGeneratedVideo)
+	m.HandleFunc("POST "+options.BaseURL+"/user-generated-videos/{videoId}/cancel", wrapper.CancelUserGeneratedVideo)
+	m.HandleFunc("POST "+options.BaseURL+"/user-generated-videos/{videoId}/rerender", wrapper.RerenderUserGeneratedVideo)
 
 	return m
 }
@@ -0,0 +1,232 @@
+// Package ffmpeg builds and runs ffmpeg and ffprobe commands.
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Command is an ffmpeg invocation with one output, built up with chained calls:
+//
+//	ffmpeg.New().Input("in.mp4").VideoFilter(chain).VideoCodec("libx264").CRF(23).Output("out.mp4")
+type Command struct {
+	inputs     []input
+	outputArgs []string
+	output     string
+}
+
+// input is an input file with the options that apply to it
+type input struct {
+	options []string
+	path    string
+}
+
+// New creates an empty ffmpeg command
+func New() *Command {
+	return &Command{}
+}
+
+// Input adds an input file; options (such as "-ss", "5") are placed before its -i
+func (c *Command) Input(path string, options ...string) *Command {
+	c.inputs = append(c.inputs, input{options: options, path: path})
+	return c
+}
+
+// InputCount is the number of inputs added so far, which is the index the next input will have
+func (c *Command) InputCount() int {
+	return len(c.inputs)
+}
+
+// SourceInput adds a lavfi source filter, such as AudioNullSource, as an input
+func (c *Command) SourceInput(source Filter) *Command {
+	return c.Input(source.String(), "-f", "lavfi")
+}
+
+// ConcatInput adds a concat demuxer script, such as one rendered by ConcatScript, as an input.
+// Paths in the script may be absolute.
+func (c *Command) ConcatInput(scriptPath string) *Command {
+	return c.Input(scriptPath, "-f", "concat", "-safe", "0")
+}
+
+// VideoFilter sets the simple video filter chain (-vf)
+func (c *Command) VideoFilter(chain Chain) *Command {
+	return c.Option("-vf", chain.String())
+}
+
+// FilterComplex sets a filter graph over all inputs (-filter_complex)
+func (c *Command) FilterComplex(graph *Graph) *Command {
+	return c.Option("-filter_complex", graph.String())
+}
+
+// Map selects an input stream or filter graph output pad for the output, e.g. "0:a?" or "[v]"
+func (c *Command) Map(stream string) *Command {
+	return c.Option("-map", stream)
+}
+
+// VideoCodec sets the video encoder, or "copy"
+func (c *Command) VideoCodec(codec string) *Command {
+	return c.Option("-c:v", codec)
+}
+
+// Preset sets the encoder speed/quality preset
+func (c *Command) Preset(preset string) *Command {
+	return c.Option("-preset", preset)
+}
+
+// CRF sets the constant rate factor
+func (c *Command) CRF(crf int) *Command {
+	return c.Option("-crf", strconv.Itoa(crf))
+}
+
+// MaxRate caps the video bitrate, e.g. "6M", with the given rate control buffer size
+func (c *Command) MaxRate(rate, bufferSize string) *Command {
+	return c.Option("-maxrate", rate, "-bufsize", bufferSize)
+}
+
+// PixelFormat sets the output pixel format
+func (c *Command) PixelFormat(format string) *Command {
+	return c.Option("-pix_fmt", format)
+}
+
+// FastStart moves the MP4 index to the front of the file so playback can start before it has downloaded
+func (c *Command) FastStart() *Command {
+	return c.Option("-movflags", "+faststart")
+}
+
+// AudioCodec sets the audio encoder, or "copy"
+func (c *Command) AudioCodec(codec string) *Command {
+	return c.Option("-c:a", codec)
+}
+
+// AudioBitrate sets the audio bitrate, e.g. "128k"
+func (c *Command) AudioBitrate(rate string) *Command {
+	return c.Option("-b:a", rate)
+}
+
+// AudioSampleRate sets the audio sample rate in Hz
+func (c *Command) AudioSampleRate(hz int) *Command {
+	return c.Option("-ar", strconv.Itoa(hz))
+}
+
+// AudioChannels sets the number of audio channels
+func (c *Command) AudioChannels(channels int) *Command {
+	return c.Option("-ac", strconv.Itoa(channels))
+}
+
+// SubtitleCodec sets the subtitle encoder, e.g. "mov_text" for MP4
+func (c *Command) SubtitleCodec(codec string) *Command {
+	return c.Option("-c:s", codec)
+}
+
+// Threads limits how many threads ffmpeg uses
+func (c *Command) Threads(threads int) *Command {
+	return c.Option("-threads", strconv.Itoa(threads))
+}
+
+// Seek starts the output at the given offset into the input, decoding up to it for an exact frame
+func (c *Command) Seek(offset time.Duration) *Command {
+	return c.Option("-ss", FormatTimestamp(offset))
+}
+
+// Frames limits the number of video frames written
+func (c *Command) Frames(frames int) *Command {
+	return c.Option("-frames:v", strconv.Itoa(frames))
+}
+
+// Quality sets the fixed quality scale used by encoders such as mjpeg (lower is better)
+func (c *Command) Quality(quality int) *Command {
+	return c.Option("-q:v", strconv.Itoa(quality))
+}
+
+// Option adds raw output options for anything without a dedicated method
+func (c *Command) Option(args ...string) *Command {
+	c.outputArgs = append(c.outputArgs, args...)
+	return c
+}
+
+// Output sets the output file, which is overwritten if it exists
+func (c *Command) Output(path string) *Command {
+	c.output = path
+	return c
+}
+
+// Args returns the arguments ffmpeg is run with
+func (c *Command) Args() []string {
+	return c.args(false)
+}
+
+// String returns the command line, for logging
+func (c *Command) String() string {
+	return "ffmpeg " + strings.Join(c.Args(), " ")
+}
+
+// args builds the argument list, asking ffmpeg for machine-readable progress on stdout if progress is set
+func (c *Command) args(progress bool) []string {
+	args := []string{"-hide_banner", "-y"}
+	if progress {
+		args = append(args, "-progress", "pipe:1", "-nostats")
+	}
+	for _, in := range c.inputs {
+		args = append(args, in.options...)
+		args = append(args, "-i", in.path)
+	}
+	args = append(args, c.outputArgs...)
+	return append(args, c.output)
+}
+
+// Run runs the command, calling onProgress (if set) each time ffmpeg reports progress. Cancelling
+// ctx kills ffmpeg. A failed run returns an *Error carrying the end of ffmpeg's stderr.
+func (c *Command) Run(ctx context.Context, onProgress ProgressFunc) error {
+	if len(c.inputs) == 0 || c.output == "" {
+		return fmt.Errorf("ffmpeg command needs at least one input and an output")
+	}
+
+	args := c.args(onProgress != nil)
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+
+	stderr := &tailBuffer{}
+	cmd.Stderr = stderr
+
+	var stdout io.ReadCloser
+	if onProgress != nil {
+		var err error
+		stdout, err = cmd.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("failed to create stdout pipe: %w", err)
+		}
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	// Wait for the progress reader to see EOF before Wait closes the pipe
+	if stdout != nil {
+		readProgress(stdout, onProgress)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("ffmpeg interrupted: %w", ctx.Err())
+		}
+		return newError("ffmpeg", args, err, stderr.String())
+	}
+	return nil
+}
+
+// FormatTimestamp formats d as an ffmpeg HH:MM:SS.mmm timestamp
+func FormatTimestamp(d time.Duration) string {
+	d = d.Round(time.Millisecond)
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+	d -= seconds * time.Second
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", hours, minutes, seconds, d/time.Millisecond)
+}
@@ -0,0 +1,27 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConcatEntry is one file in a concat demuxer script, played for Duration; the last entry's
+// Duration may be zero, in which case it plays to its end
+type ConcatEntry struct {
+	Path     string
+	Duration time.Duration
+}
+
+// ConcatScript renders entries as an ffconcat script, to be read with ConcatInput
+func ConcatScript(entries []ConcatEntry) string {
+	var b strings.Builder
+	b.WriteString("ffconcat version 1.0\n")
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "file '%s'\n", strings.ReplaceAll(entry.Path, "'", `'\''`))
+		if entry.Duration > 0 {
+			fmt.Fprintf(&b, "duration %.3f\n", entry.Duration.Seconds())
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package ffmpeg
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// stderrTailBytes is how much of the end of a command's stderr is kept for its error
+const stderrTailBytes = 4096
+
+// Error is a failed ffmpeg or ffprobe run
+type Error struct {
+	Program string   // "ffmpeg" or "ffprobe"
+	Args    []string // The arguments it was run with
+	Err     error    // Usually an *exec.ExitError
+	Stderr  string   // The end of its stderr output
+}
+
+// newError wraps a failed run of program
+func newError(program string, args []string, err error, stderr string) *Error {
+	return &Error{Program: program, Args: args, Err: err, Stderr: stderr}
+}
+
+// Error describes the failure with the last line ffmpeg wrote to stderr, which is usually the reason
+func (e *Error) Error() string {
+	if line := e.LastLine(); line != "" {
+		return fmt.Sprintf("%s failed: %v: %s", e.Program, e.Err, line)
+	}
+	return fmt.Sprintf("%s failed: %v", e.Program, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// LastLine returns the last non-empty line of the captured stderr
+func (e *Error) LastLine() string {
+	lines := strings.Split(strings.TrimSpace(e.Stderr), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// ExitCode returns the process exit code, or -1 if the process did not exit normally
+func (e *Error) ExitCode() int {
+	var exitErr *exec.ExitError
+	if errors.As(e.Err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// tailBuffer is an io.Writer that keeps only the last stderrTailBytes written to it
+type tailBuffer struct {
+	buf       []byte
+	truncated bool
+}
+
+// Write appends p, dropping the oldest bytes beyond the limit
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - stderrTailBytes; over > 0 {
+		t.buf = append(t.buf[:0], t.buf[over:]...)
+		t.truncated = true
+	}
+	return len(p), nil
+}
+
+// String returns the kept output, starting at a line boundary if earlier output was dropped
+func (t *tailBuffer) String() string {
+	s := string(t.buf)
+	if t.truncated {
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			s = s[i+1:]
+		}
+	}
+	return s
+}
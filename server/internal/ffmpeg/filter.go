@@ -0,0 +1,229 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Filter is a single ffmpeg filter with named options, such as scale=w=1080:h=1920
+type Filter struct {
+	Name    string
+	Options []Option
+}
+
+// Option is a key=value filter option. Values are escaped when the filter is rendered, so they
+// can hold paths and text containing ffmpeg's special characters.
+type Option struct {
+	Key   string
+	Value string
+}
+
+// NewFilter creates a filter with no options
+func NewFilter(name string) Filter {
+	return Filter{Name: name}
+}
+
+// With returns a copy of the filter with an option added; value is formatted with fmt.Sprint
+func (f Filter) With(key string, value any) Filter {
+	options := make([]Option, len(f.Options), len(f.Options)+1)
+	copy(options, f.Options)
+	f.Options = append(options, Option{Key: key, Value: fmt.Sprint(value)})
+	return f
+}
+
+// String renders the filter for use in a filter graph, escaping option values at both the
+// filter option level and the filter graph level
+func (f Filter) String() string {
+	if len(f.Options) == 0 {
+		return f.Name
+	}
+
+	options := make([]string, len(f.Options))
+	for i, option := range f.Options {
+		options[i] = option.Key + "=" + escapeGraph(escapeOptionValue(option.Value))
+	}
+	return f.Name + "=" + strings.Join(options, ":")
+}
+
+// Chain is a sequence of filters applied one after another
+type Chain []Filter
+
+// String renders the chain as comma-separated filters
+func (c Chain) String() string {
+	filters := make([]string, len(c))
+	for i, filter := range c {
+		filters[i] = filter.String()
+	}
+	return strings.Join(filters, ",")
+}
+
+// Graph is a filter graph of labelled chains, for -filter_complex
+type Graph struct {
+	chains []graphChain
+}
+
+// graphChain is a chain reading from and writing to labelled pads such as [0:v] or [overlaid]
+type graphChain struct {
+	inputs  []string
+	chain   Chain
+	outputs []string
+}
+
+// Add appends a chain that reads the given input pads and writes the given output pads. Labels
+// are given without brackets, e.g. "0:v".
+func (g *Graph) Add(inputs []string, chain Chain, outputs []string) *Graph {
+	g.chains = append(g.chains, graphChain{inputs: inputs, chain: chain, outputs: outputs})
+	return g
+}
+
+// String renders the graph as semicolon-separated labelled chains
+func (g *Graph) String() string {
+	chains := make([]string, len(g.chains))
+	for i, c := range g.chains {
+		var b strings.Builder
+		for _, label := range c.inputs {
+			b.WriteString("[" + label + "]")
+		}
+		b.WriteString(c.chain.String())
+		for _, label := range c.outputs {
+			b.WriteString("[" + label + "]")
+		}
+		chains[i] = b.String()
+	}
+	return strings.Join(chains, ";")
+}
+
+// escapeOptionValue escapes the characters that are special inside a filter option value
+func escapeOptionValue(value string) string {
+	return optionValueEscaper.Replace(value)
+}
+
+// escapeGraph escapes the characters that are special in a filter graph description
+func escapeGraph(value string) string {
+	return graphEscaper.Replace(value)
+}
+
+var (
+	optionValueEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`)
+	graphEscaper       = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `[`, `\[`, `]`, `\]`, `,`, `\,`, `;`, `\;`)
+)
+
+// Scale resizes the frame to width x height
+func Scale(width, height int) Filter {
+	return NewFilter("scale").With("w", width).With("h", height)
+}
+
+// ScaleToFit resizes the frame to fit inside width x height, keeping its aspect ratio
+func ScaleToFit(width, height int) Filter {
+	return Scale(width, height).With("force_original_aspect_ratio", "decrease")
+}
+
+// ScaleToFill resizes the frame to cover width x height, keeping its aspect ratio
+func ScaleToFill(width, height int) Filter {
+	return Scale(width, height).With("force_original_aspect_ratio", "increase")
+}
+
+// EvenDimensions rounds the frame size down to even numbers, which yuv420p encoders require
+func EvenDimensions() Filter {
+	return NewFilter("scale").With("w", "trunc(iw/2)*2").With("h", "trunc(ih/2)*2")
+}
+
+// PadCentered pads the frame to width x height with the picture centred
+func PadCentered(width, height int, color string) Filter {
+	return NewFilter("pad").
+		With("w", width).
+		With("h", height).
+		With("x", "(ow-iw)/2").
+		With("y", "(oh-ih)/2").
+		With("color", color)
+}
+
+// CropCentered crops the centre width x height of the frame
+func CropCentered(width, height int) Filter {
+	return NewFilter("crop").With("w", width).With("h", height)
+}
+
+// SetSAR sets the sample aspect ratio, e.g. 1 for square pixels
+func SetSAR(sar int) Filter {
+	return NewFilter("setsar").With("sar", sar)
+}
+
+// FPS converts the video to a constant frame rate
+func FPS(fps int) Filter {
+	return NewFilter("fps").With("fps", fps)
+}
+
+// ToPixelFormat converts the video to the given pixel format, e.g. yuv420p
+func ToPixelFormat(pixelFormat string) Filter {
+	return NewFilter("format").With("pix_fmts", pixelFormat)
+}
+
+// Trim cuts the video off after duration
+func Trim(duration time.Duration) Filter {
+	return NewFilter("trim").With("duration", fmt.Sprintf("%.3f", duration.Seconds()))
+}
+
+// ATrim cuts the audio off after duration
+func ATrim(duration time.Duration) Filter {
+	return NewFilter("atrim").With("duration", fmt.Sprintf("%.3f", duration.Seconds()))
+}
+
+// AFadeOut fades the audio out to silence over duration, starting start into it
+func AFadeOut(start, duration time.Duration) Filter {
+	return NewFilter("afade").
+		With("type", "out").
+		With("start_time", fmt.Sprintf("%.3f", start.Seconds())).
+		With("duration", fmt.Sprintf("%.3f", duration.Seconds()))
+}
+
+// APad pads the end of the audio with silence until it lasts duration
+func APad(duration time.Duration) Filter {
+	return NewFilter("apad").With("whole_dur", fmt.Sprintf("%.3f", duration.Seconds()))
+}
+
+// Split copies the video to the given number of outputs
+func Split(outputs int) Filter {
+	return NewFilter("split").With("outputs", outputs)
+}
+
+// ASplit copies the audio to the given number of outputs
+func ASplit(outputs int) Filter {
+	return NewFilter("asplit").With("outputs", outputs)
+}
+
+// XFade crossfades from the first input to the second over duration, starting offset into the first
+func XFade(duration, offset time.Duration) Filter {
+	return NewFilter("xfade").
+		With("transition", "fade").
+		With("duration", fmt.Sprintf("%.3f", duration.Seconds())).
+		With("offset", fmt.Sprintf("%.3f", offset.Seconds()))
+}
+
+// ACrossfade crossfades the end of the first audio input into the start of the second over duration
+func ACrossfade(duration time.Duration) Filter {
+	return NewFilter("acrossfade").With("duration", fmt.Sprintf("%.3f", duration.Seconds()))
+}
+
+// Overlay draws the second input over the first with its top-left corner at (x, y). When the second
+// input ends its last frame stays on screen, so a single image covers the whole video.
+func Overlay(x, y int) Filter {
+	return NewFilter("overlay").With("x", x).With("y", y)
+}
+
+// DrawtextFile draws the contents of textFile with fontFile. Text expansion is turned off so the
+// text is drawn literally, including any % or \ characters.
+func DrawtextFile(textFile, fontFile string) Filter {
+	return NewFilter("drawtext").
+		With("textfile", textFile).
+		With("fontfile", fontFile).
+		With("expansion", "none")
+}
+
+// AudioNullSource is a source of stereo silence at the given sample rate lasting duration, used as a lavfi input
+func AudioNullSource(sampleRate int, duration time.Duration) Filter {
+	return NewFilter("anullsrc").
+		With("channel_layout", "stereo").
+		With("sample_rate", sampleRate).
+		With("duration", fmt.Sprintf("%.3f", duration.Seconds()))
+}
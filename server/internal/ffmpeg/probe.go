@@ -0,0 +1,245 @@
+package ffmpeg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProbeResult is the parsed ffprobe description of a media file
+type ProbeResult struct {
+	Format  Format
+	Streams []Stream
+}
+
+// Format is the container-level information about a media file
+type Format struct {
+	Name     string // e.g. "mov,mp4,m4a,3gp,3g2,mj2"
+	Duration time.Duration
+	Size     int64
+	BitRate  int64
+}
+
+// Stream is one audio, video or other stream in a media file
+type Stream struct {
+	Index      int
+	CodecType  string // "video", "audio", "subtitle", ...
+	CodecName  string // e.g. "h264", "aac"
+	Width      int
+	Height     int
+	Rotation   int // Display rotation in degrees, normalised to 0, 90, 180 or 270
+	FrameRate  float64
+	PixelFmt   string
+	SampleRate int
+	Channels   int
+	Duration   time.Duration
+	BitRate    int64
+}
+
+// Probe describes a media file using ffprobe
+func Probe(ctx context.Context, path string) (*ProbeResult, error) {
+	args := []string{
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		"-show_streams",
+		path,
+	}
+	cmd := exec.CommandContext(ctx, "ffprobe", args...)
+
+	stderr := &tailBuffer{}
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("ffprobe interrupted: %w", ctx.Err())
+		}
+		return nil, newError("ffprobe", args, err, stderr.String())
+	}
+
+	return ParseProbeOutput(output)
+}
+
+// VideoStream returns the first video stream, or nil if there is none
+func (p *ProbeResult) VideoStream() *Stream {
+	return p.firstStream("video")
+}
+
+// AudioStream returns the first audio stream, or nil if there is none
+func (p *ProbeResult) AudioStream() *Stream {
+	return p.firstStream("audio")
+}
+
+// firstStream returns the first stream of the given codec type
+func (p *ProbeResult) firstStream(codecType string) *Stream {
+	for i := range p.Streams {
+		if p.Streams[i].CodecType == codecType {
+			return &p.Streams[i]
+		}
+	}
+	return nil
+}
+
+// Duration is the container duration, falling back to the longest stream's
+func (p *ProbeResult) Duration() time.Duration {
+	if p.Format.Duration > 0 {
+		return p.Format.Duration
+	}
+	var longest time.Duration
+	for _, stream := range p.Streams {
+		longest = max(longest, stream.Duration)
+	}
+	return longest
+}
+
+// DisplaySize is the frame size as the stream is displayed, with width and height swapped for
+// streams that are rotated a quarter turn. ffmpeg applies the rotation when decoding, so this is
+// the size filters see.
+func (s *Stream) DisplaySize() (width, height int) {
+	if s.Rotation == 90 || s.Rotation == 270 {
+		return s.Height, s.Width
+	}
+	return s.Width, s.Height
+}
+
+// probeOutput mirrors the parts of ffprobe's JSON output that are parsed. ffprobe writes most
+// numbers as strings.
+type probeOutput struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		Size       string `json:"size"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+	Streams []struct {
+		Index        int               `json:"index"`
+		CodecType    string            `json:"codec_type"`
+		CodecName    string            `json:"codec_name"`
+		Width        int               `json:"width"`
+		Height       int               `json:"height"`
+		PixFmt       string            `json:"pix_fmt"`
+		AvgFrameRate string            `json:"avg_frame_rate"`
+		RFrameRate   string            `json:"r_frame_rate"`
+		SampleRate   string            `json:"sample_rate"`
+		Channels     int               `json:"channels"`
+		Duration     string            `json:"duration"`
+		BitRate      string            `json:"bit_rate"`
+		Tags         map[string]string `json:"tags"`
+		SideDataList []struct {
+			Rotation *float64 `json:"rotation"`
+		} `json:"side_data_list"`
+	} `json:"streams"`
+}
+
+// ParseProbeOutput parses the output of ffprobe -print_format json -show_format -show_streams
+func ParseProbeOutput(data []byte) (*ProbeResult, error) {
+	var out probeOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	duration, err := parseSeconds(out.Format.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse duration: %w", err)
+	}
+
+	result := &ProbeResult{
+		Format: Format{
+			Name:     out.Format.FormatName,
+			Duration: duration,
+			Size:     parseInt(out.Format.Size),
+			BitRate:  parseInt(out.Format.BitRate),
+		},
+	}
+
+	for _, s := range out.Streams {
+		stream := Stream{
+			Index:      s.Index,
+			CodecType:  s.CodecType,
+			CodecName:  s.CodecName,
+			Width:      s.Width,
+			Height:     s.Height,
+			PixelFmt:   s.PixFmt,
+			SampleRate: int(parseInt(s.SampleRate)),
+			Channels:   s.Channels,
+			BitRate:    parseInt(s.BitRate),
+		}
+
+		if stream.Duration, err = parseSeconds(s.Duration); err != nil {
+			return nil, fmt.Errorf("failed to parse duration of stream %d: %w", s.Index, err)
+		}
+
+		// avg_frame_rate is 0/0 for some containers; r_frame_rate is the stream's base rate
+		stream.FrameRate = parseRational(s.AvgFrameRate)
+		if stream.FrameRate == 0 {
+			stream.FrameRate = parseRational(s.RFrameRate)
+		}
+
+		// Older ffmpeg versions report rotation as a tag, newer ones as display matrix side data
+		var rotation float64
+		if rotate, ok := s.Tags["rotate"]; ok {
+			rotation, _ = strconv.ParseFloat(rotate, 64)
+		}
+		for _, sideData := range s.SideDataList {
+			if sideData.Rotation != nil {
+				rotation = *sideData.Rotation
+			}
+		}
+		stream.Rotation = normaliseRotation(rotation)
+
+		result.Streams = append(result.Streams, stream)
+	}
+
+	return result, nil
+}
+
+// parseSeconds parses a duration in seconds such as "12.345000"; empty and "N/A" are zero
+func parseSeconds(value string) (time.Duration, error) {
+	if value == "" || value == "N/A" {
+		return 0, nil
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// parseInt parses an integer, treating anything unparseable (such as "N/A") as zero
+func parseInt(value string) int64 {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// parseRational parses a rate such as "30000/1001", treating anything unparseable (such as "0/0") as zero
+func parseRational(value string) float64 {
+	num, den, ok := strings.Cut(value, "/")
+	if !ok {
+		f, _ := strconv.ParseFloat(value, 64)
+		return f
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
+
+// normaliseRotation rounds a rotation to the nearest quarter turn in [0, 360)
+func normaliseRotation(degrees float64) int {
+	quarterTurns := int(math.Round(degrees / 90))
+	return ((quarterTurns % 4) + 4) % 4 * 90
+}
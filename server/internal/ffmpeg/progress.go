@@ -0,0 +1,65 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Progress is one progress report from ffmpeg
+type Progress struct {
+	OutTime time.Duration // How much of the output has been encoded
+	Done    bool          // Set on the final report
+}
+
+// ProgressFunc receives progress reports as ffmpeg makes them
+type ProgressFunc func(Progress)
+
+// readProgress parses ffmpeg "-progress" output (blocks of key=value lines, each ending with
+// progress=continue or progress=end) until r is closed, reporting each block to onProgress
+func readProgress(r io.Reader, onProgress ProgressFunc) {
+	var outTime time.Duration
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "out_time":
+			if t, err := ParseTimestamp(value); err == nil {
+				outTime = t
+			}
+		case "progress":
+			onProgress(Progress{OutTime: outTime, Done: value == "end"})
+		}
+	}
+}
+
+// ParseTimestamp parses an ffmpeg HH:MM:SS.micro timestamp such as "00:01:02.500000"
+func ParseTimestamp(value string) (time.Duration, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", value)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid hours in timestamp %q: %w", value, err)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid minutes in timestamp %q: %w", value, err)
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid seconds in timestamp %q: %w", value, err)
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second)), nil
+}
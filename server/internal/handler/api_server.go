@@ -1,15 +1,20 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/ethanhosier/reel-farm/db"
 	"github.com/ethanhosier/reel-farm/internal/api"
 	"github.com/ethanhosier/reel-farm/internal/context_keys"
+	"github.com/ethanhosier/reel-farm/internal/repository"
 	"github.com/ethanhosier/reel-farm/internal/service"
 	"github.com/google/uuid"
 	openapi_types "github.com/oapi-codegen/runtime/types"
@@ -17,19 +22,25 @@ import (
 
 // APIServer implements the generated ServerInterface
 type APIServer struct {
-	userService         *service.UserService
-	subscriptionService *service.SubscriptionService
-	hookService         *service.HookService
-	aiAvatarService     *service.AIAvatarService
+	userService           *service.UserService
+	subscriptionService   *service.SubscriptionService
+	hookService           *service.HookService
+	aiAvatarService       *service.AIAvatarService
+	renderJobService      *service.RenderJobService
+	brandKitService       *service.BrandKitService
+	promptTemplateService *service.PromptTemplateService
 }
 
 // NewAPIServer creates a new API server handler
-func NewAPIServer(userService *service.UserService, subscriptionService *service.SubscriptionService, hookService *service.HookService, aiAvatarService *service.AIAvatarService) *APIServer {
+func NewAPIServer(userService *service.UserService, subscriptionService *service.SubscriptionService, hookService *service.HookService, aiAvatarService *service.AIAvatarService, renderJobService *service.RenderJobService, brandKitService *service.BrandKitService, promptTemplateService *service.PromptTemplateService) *APIServer {
 	return &APIServer{
-		userService:         userService,
-		subscriptionService: subscriptionService,
-		hookService:         hookService,
-		aiAvatarService:     aiAvatarService,
+		userService:           userService,
+		subscriptionService:   subscriptionService,
+		hookService:           hookService,
+		aiAvatarService:       aiAvatarService,
+		renderJobService:      renderJobService,
+		brandKitService:       brandKitService,
+		promptTemplateService: promptTemplateService,
 	}
 }
 
@@ -65,6 +76,163 @@ func (s *APIServer) toVideoAPIResponse(video *db.AiAvatarVideo) (*api.AIAvatarVi
 	}, nil
 }
 
+// toUserGeneratedVideoAPIResponse converts a database user-generated video and its output profile
+// variants to API response format, signing the video, thumbnail and variant URLs (24 hour expiration)
+// once the render has completed
+func (s *APIServer) toUserGeneratedVideoAPIResponse(video *db.UserGeneratedVideo, variants []*db.UserGeneratedVideoVariant) (*api.UserGeneratedVideo, error) {
+	response := &api.UserGeneratedVideo{
+		Id:              openapi_types.UUID(video.ID),
+		UserId:          openapi_types.UUID(video.UserID.Bytes),
+		AiAvatarVideoId: openapi_types.UUID(video.AiAvatarVideoID.Bytes),
+		OverlayText:     video.OverlayText,
+		Status:          api.UserGeneratedVideoStatus(*video.Status),
+		ErrorMessage:    video.ErrorMessage,
+		Watermarked:     video.Watermarked,
+		CreatedAt:       video.CreatedAt,
+	}
+	if video.RenderBatchID.Valid {
+		batchID := openapi_types.UUID(video.RenderBatchID.Bytes)
+		response.RenderBatchId = &batchID
+	}
+
+	if len(variants) > 0 {
+		variantResponses := make([]api.UserGeneratedVideoVariant, 0, len(variants))
+		for _, variant := range variants {
+			variantResponse := api.UserGeneratedVideoVariant{
+				Profile: api.OutputProfile(variant.Profile),
+				Width:   int(variant.Width),
+				Height:  int(variant.Height),
+			}
+			if response.Status == api.UserGeneratedVideoStatusCompleted {
+				variantPath := fmt.Sprintf("user-generated-videos/videos/%s", variant.VideoFilename)
+				variantURL, err := s.aiAvatarService.GenerateSignedURL(variantPath, 24*time.Hour)
+				if err != nil {
+					return nil, fmt.Errorf("failed to generate signed %s variant URL: %w", variant.Profile, err)
+				}
+				variantResponse.VideoUrl = &variantURL
+			}
+			variantResponses = append(variantResponses, variantResponse)
+		}
+		response.Variants = &variantResponses
+	}
+
+	if response.Status != api.UserGeneratedVideoStatusCompleted {
+		return response, nil
+	}
+
+	videoPath := fmt.Sprintf("user-generated-videos/videos/%s", video.GeneratedVideoFilename)
+	thumbnailPath := fmt.Sprintf("user-generated-videos/thumbnails/%s", video.ThumbnailFilename)
+
+	videoURL, err := s.aiAvatarService.GenerateSignedURL(videoPath, 24*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signed video URL: %w", err)
+	}
+	thumbnailURL, err := s.aiAvatarService.GenerateSignedURL(thumbnailPath, 24*time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signed thumbnail URL: %w", err)
+	}
+
+	response.VideoUrl = &videoURL
+	response.ThumbnailUrl = &thumbnailURL
+
+	if video.SubtitleSrtFilename != nil {
+		srtURL, err := s.aiAvatarService.GenerateSignedURL(fmt.Sprintf("user-generated-videos/videos/%s", *video.SubtitleSrtFilename), 24*time.Hour)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate signed SRT URL: %w", err)
+		}
+		response.SubtitleSrtUrl = &srtURL
+	}
+	if video.SubtitleVttFilename != nil {
+		vttURL, err := s.aiAvatarService.GenerateSignedURL(fmt.Sprintf("user-generated-videos/videos/%s", *video.SubtitleVttFilename), 24*time.Hour)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate signed WebVTT URL: %w", err)
+		}
+		response.SubtitleVttUrl = &vttURL
+	}
+
+	return response, nil
+}
+
+// writeRenderOptionsError writes a 400 response if err is a render options validation error, reporting whether it did
+func writeRenderOptionsError(w http.ResponseWriter, err error) bool {
+	var code string
+	switch {
+	case errors.Is(err, service.ErrInvalidOverlayStyle):
+		code = "invalid_overlay_style"
+	case errors.Is(err, service.ErrInvalidCaptionOptions):
+		code = "invalid_caption_options"
+	case errors.Is(err, service.ErrInvalidOutputProfiles):
+		code = "invalid_output_profiles"
+	case errors.Is(err, service.ErrInvalidSubtitleMode):
+		code = "invalid_subtitle_mode"
+	case errors.Is(err, service.ErrInvalidClipOptions):
+		code = "invalid_clip_options"
+	case errors.Is(err, service.ErrInvalidEndCard):
+		code = "invalid_end_card"
+	default:
+		return false
+	}
+
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(api.ErrorResponse{
+		Error:   code,
+		Message: err.Error(),
+	})
+	return true
+}
+
+// writeRenderBrandKitError writes an error response if err is a problem with the brand kit a render
+// names, reporting whether it did
+func writeRenderBrandKitError(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, service.ErrBrandKitNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "brand_kit_not_found",
+			Message: "Brand kit not found or doesn't belong to user",
+		})
+		return true
+	}
+	return false
+}
+
+// toUserGeneratedVideoAPIResponses converts a list of user-generated videos to API response format,
+// looking up the output profile variants of every video in one query
+func (s *APIServer) toUserGeneratedVideoAPIResponses(ctx context.Context, videos []*db.UserGeneratedVideo) ([]api.UserGeneratedVideo, error) {
+	videoIDs := make([]uuid.UUID, len(videos))
+	for i, video := range videos {
+		videoIDs[i] = video.ID
+	}
+	variantsByVideoID, err := s.aiAvatarService.GetUserGeneratedVideoVariantsByVideoIDs(ctx, videoIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve video variants: %w", err)
+	}
+
+	videoResponses := make([]api.UserGeneratedVideo, 0, len(videos))
+	for _, video := range videos {
+		videoResponse, err := s.toUserGeneratedVideoAPIResponse(video, variantsByVideoID[video.ID])
+		if err != nil {
+			return nil, err
+		}
+		videoResponses = append(videoResponses, *videoResponse)
+	}
+	return videoResponses, nil
+}
+
+// toRenderBatchAPIResponse converts a render batch summary to API response format
+func toRenderBatchAPIResponse(summary *service.RenderBatchSummary) api.RenderBatch {
+	return api.RenderBatch{
+		Id:              openapi_types.UUID(summary.Batch.ID),
+		Status:          summary.Status(),
+		RenderCount:     int(summary.Batch.RenderCount),
+		CreditsCharged:  int(summary.Batch.CreditsCharged),
+		ProcessingCount: summary.ProcessingCount,
+		CompletedCount:  summary.CompletedCount,
+		FailedCount:     summary.FailedCount,
+		CancelledCount:  summary.CancelledCount,
+		CreatedAt:       summary.Batch.CreatedAt,
+	}
+}
+
 // GetHealth handles GET /health
 func (s *APIServer) GetHealth(w http.ResponseWriter, r *http.Request) {
 	// Set content type to JSON
@@ -418,7 +586,16 @@ func (s *APIServer) GenerateHooks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate hooks
-	hooks, err := s.hookService.GenerateHooks(r.Context(), userID, req.Prompt, int(req.NumHooks))
+	hooks, err := s.hookService.GenerateHooks(r.Context(), userID, req.Prompt, int(req.NumHooks), req.PromptTemplateId)
+	if errors.Is(err, service.ErrPromptTemplateNotFound) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "prompt_template_not_found",
+			Message: "Prompt template not found or inactive",
+		})
+		return
+	}
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
@@ -438,6 +615,110 @@ func (s *APIServer) GenerateHooks(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// GenerateHooksStream handles POST /hooks/generate/stream, sending each hook as a Server-Sent Event
+// as soon as it is saved
+func (s *APIServer) GenerateHooksStream(w http.ResponseWriter, r *http.Request) {
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	// Convert string to UUID
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Parse request body
+	var req api.GenerateHooksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	// Validate request
+	if req.Prompt == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "missing_prompt",
+			Message: "prompt is required",
+		})
+		return
+	}
+
+	if req.NumHooks < 1 || req.NumHooks > 10 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_num_hooks",
+			Message: "num_hooks must be between 1 and 10",
+		})
+		return
+	}
+
+	// Generate hooks, streaming each one as it is saved. Nothing is written until the first event, so
+	// failing before then is still a plain JSON error.
+	events := newEventStream(w)
+	summary, err := s.hookService.StreamHooks(r.Context(), userID, req.Prompt, int(req.NumHooks), req.PromptTemplateId, func(hook api.GeneratedHookEvent) {
+		events.send("hook", hook)
+	})
+	if errors.Is(err, service.ErrPromptTemplateNotFound) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "prompt_template_not_found",
+			Message: "Prompt template not found or inactive",
+		})
+		return
+	}
+	if err != nil {
+		if !events.started {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(api.ErrorResponse{
+				Error:   "hook_generation_failed",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		// Tell the client which of the hooks it was sent have been deleted
+		deletedHookIDs := []openapi_types.UUID{}
+		var streamErr *service.HookStreamError
+		if errors.As(err, &streamErr) {
+			deletedHookIDs = streamErr.DeletedHookIDs
+		}
+		events.send("error", api.HookGenerationError{
+			Error:          "hook_generation_failed",
+			Message:        err.Error(),
+			DeletedHookIds: deletedHookIDs,
+		})
+		return
+	}
+
+	// Finish with the summary
+	events.send("done", summary)
+}
+
 // GetHooks handles GET /hooks
 func (s *APIServer) GetHooks(w http.ResponseWriter, r *http.Request, params api.GetHooksParams) {
 	// Extract user ID from context
@@ -556,6 +837,110 @@ func (s *APIServer) DeleteHook(w http.ResponseWriter, r *http.Request, hookId op
 	json.NewEncoder(w).Encode(response)
 }
 
+// RefineHook handles POST /hooks/{hookId}/refine
+func (s *APIServer) RefineHook(w http.ResponseWriter, r *http.Request, hookId openapi_types.UUID) {
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	// Convert string to UUID
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Parse request body
+	var req api.RefineHookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	// Validate request
+	instruction := strings.TrimSpace(req.Instruction)
+	if instruction == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "missing_instruction",
+			Message: "instruction is required",
+		})
+		return
+	}
+
+	if utf8.RuneCountInString(instruction) > 200 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_instruction",
+			Message: "instruction must be at most 200 characters",
+		})
+		return
+	}
+
+	numVariants := 3
+	if req.NumVariants != nil {
+		numVariants = *req.NumVariants
+	}
+	if numVariants < 1 || numVariants > 10 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_num_variants",
+			Message: "num_variants must be between 1 and 10",
+		})
+		return
+	}
+
+	// Refine the hook
+	hooks, err := s.hookService.RefineHook(r.Context(), userID, uuid.UUID(hookId), instruction, numVariants)
+	if errors.Is(err, service.ErrHookNotFound) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "hook_not_found",
+			Message: "Hook not found or doesn't belong to user",
+		})
+		return
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "hook_refinement_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Return the variants
+	response := api.GenerateHooksResponse{
+		Hooks: hooks,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
 // DeleteHooksBulk handles DELETE /hooks/bulk
 func (s *APIServer) DeleteHooksBulk(w http.ResponseWriter, r *http.Request) {
 	// Extract user ID from context
@@ -690,82 +1075,102 @@ func (s *APIServer) CreateUserGeneratedVideo(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Parse AI avatar video ID
-	aiAvatarVideoID := uuid.UUID(req.AiAvatarVideoId)
+	// Validate overlay text
+	if strings.TrimSpace(req.OverlayText) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "missing_overlay_text",
+			Message: "overlay_text is required",
+		})
+		return
+	}
 
-	// Get the AI avatar video to get its URL
-	aiAvatarVideo, err := s.aiAvatarService.GetVideoByID(r.Context(), aiAvatarVideoID)
-	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
+	if utf8.RuneCountInString(req.OverlayText) > 500 {
+		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(api.ErrorResponse{
-			Error:   "video_not_found",
-			Message: "AI avatar video not found",
+			Error:   "invalid_overlay_text",
+			Message: "overlay_text must be at most 500 characters",
 		})
 		return
 	}
 
-	// Generate CloudFront URL for the video
-	videoPath := fmt.Sprintf("ai-avatar/videos/%s", aiAvatarVideo.Filename)
-	videoURL, err := s.generateCloudFrontURL(videoPath)
+	// Parse AI avatar video ID
+	aiAvatarVideoID := uuid.UUID(req.AiAvatarVideoId)
+
+	// Make sure the AI avatar video exists before queueing the render
+	exists, err := s.aiAvatarService.VideoExists(r.Context(), aiAvatarVideoID)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(api.ErrorResponse{
 			Error:   "internal_error",
-			Message: "Failed to generate video URL",
+			Message: "Failed to look up AI avatar video",
 		})
 		return
 	}
-
-	// Process video with text overlay
-	userGeneratedVideo, err := s.aiAvatarService.ProcessVideoWithTextOverlay(r.Context(), userID, aiAvatarVideoID, videoURL, req.OverlayText)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(api.ErrorResponse{
-			Error:   "processing_error",
-			Message: "Failed to process video with text overlay" + err.Error(),
+			Error:   "video_not_found",
+			Message: "AI avatar video not found",
 		})
 		return
 	}
 
-	// Generate signed CloudFront URLs for the generated video (24 hour expiration)
-	generatedVideoPath := fmt.Sprintf("user-generated-videos/videos/%s", userGeneratedVideo.GeneratedVideoFilename)
-	generatedThumbnailPath := fmt.Sprintf("user-generated-videos/thumbnails/%s", userGeneratedVideo.ThumbnailFilename)
+	// Create the record and queue the render; a worker picks it up in the background
+	options := &service.RenderOptions{
+		Style:      req.Style,
+		Captions:   req.Captions,
+		Clip:       req.Clip,
+		EndCard:    req.EndCard,
+		BrandKitID: (*uuid.UUID)(req.BrandKitId),
+	}
+	if req.OutputProfiles != nil {
+		options.OutputProfiles = *req.OutputProfiles
+	}
+	if req.SubtitleMode != nil {
+		options.SubtitleMode = *req.SubtitleMode
+	}
 
-	generatedVideoURL, err := s.aiAvatarService.GenerateSignedURL(generatedVideoPath, 24*time.Hour)
+	userGeneratedVideo, err := s.renderJobService.EnqueueRender(r.Context(), userID, aiAvatarVideoID, req.OverlayText, options)
+	if writeRenderOptionsError(w, err) {
+		return
+	}
+	if writeRenderBrandKitError(w, err) {
+		return
+	}
+	if errors.Is(err, service.ErrUnsupportedSource) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unsupported_source",
+			Message: err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(api.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to generate signed video URL",
+			Error:   "processing_error",
+			Message: "Failed to queue video for rendering",
 		})
 		return
 	}
 
-	generatedThumbnailURL, err := s.aiAvatarService.GenerateSignedURL(generatedThumbnailPath, 24*time.Hour)
+	videoResponse, err := s.toUserGeneratedVideoAPIResponse(userGeneratedVideo, nil)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(api.ErrorResponse{
 			Error:   "internal_error",
-			Message: "Failed to generate signed thumbnail URL",
+			Message: "Failed to convert video to API response: " + err.Error(),
 		})
 		return
 	}
 
 	// Create response
 	response := api.UserGeneratedVideoResponse{
-		Video: api.UserGeneratedVideo{
-			Id:              openapi_types.UUID(userGeneratedVideo.ID),
-			UserId:          openapi_types.UUID(userGeneratedVideo.UserID.Bytes),
-			AiAvatarVideoId: openapi_types.UUID(userGeneratedVideo.AiAvatarVideoID.Bytes),
-			OverlayText:     userGeneratedVideo.OverlayText,
-			VideoUrl:        generatedVideoURL,
-			ThumbnailUrl:    generatedThumbnailURL,
-			Status:          api.UserGeneratedVideoStatus(*userGeneratedVideo.Status),
-			CreatedAt:       userGeneratedVideo.CreatedAt,
-		},
+		Video: *videoResponse,
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -805,46 +1210,509 @@ func (s *APIServer) GetUserGeneratedVideos(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Convert to API response format
-	var videoResponses []api.UserGeneratedVideo
-	for _, video := range userGeneratedVideos {
-		// Generate signed CloudFront URLs for each video (24 hour expiration)
-		videoPath := fmt.Sprintf("user-generated-videos/videos/%s", video.GeneratedVideoFilename)
-		thumbnailPath := fmt.Sprintf("user-generated-videos/thumbnails/%s", video.ThumbnailFilename)
-
-		videoURL, err := s.aiAvatarService.GenerateSignedURL(videoPath, 24*time.Hour)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(api.ErrorResponse{
-				Error:   "internal_error",
-				Message: "Failed to generate signed video URL",
+	// Look up the output profile variants of every video in one query
+	videoIDs := make([]uuid.UUID, len(userGeneratedVideos))
+	for i, video := range userGeneratedVideos {
+		videoIDs[i] = video.ID
+	}
+	variantsByVideoID, err := s.aiAvatarService.GetUserGeneratedVideoVariantsByVideoIDs(r.Context(), videoIDs)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve video variants",
+		})
+		return
+	}
+
+	// Convert to API response format
+	var videoResponses []api.UserGeneratedVideo
+	for _, video := range userGeneratedVideos {
+		videoResponse, err := s.toUserGeneratedVideoAPIResponse(video, variantsByVideoID[video.ID])
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(api.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to convert video to API response: " + err.Error(),
 			})
 			return
 		}
+		videoResponses = append(videoResponses, *videoResponse)
+	}
+
+	response := api.UserGeneratedVideosResponse{
+		Videos: videoResponses,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetUserGeneratedVideo handles GET /user-generated-videos/{videoId}
+func (s *APIServer) GetUserGeneratedVideo(w http.ResponseWriter, r *http.Request, videoId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Get the video, hiding videos that belong to other users
+	video, err := s.aiAvatarService.GetUserGeneratedVideoByID(r.Context(), uuid.UUID(videoId))
+	if err != nil || uuid.UUID(video.UserID.Bytes) != userID {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "video_not_found",
+			Message: "Video not found or doesn't belong to user",
+		})
+		return
+	}
+
+	variants, err := s.aiAvatarService.GetUserGeneratedVideoVariants(r.Context(), video.ID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve video variants",
+		})
+		return
+	}
+
+	videoResponse, err := s.toUserGeneratedVideoAPIResponse(video, variants)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to convert video to API response: " + err.Error(),
+		})
+		return
+	}
+
+	// Attach render progress; videos created before the job queue have no job
+	job, err := s.renderJobService.GetLatestRenderJob(r.Context(), video.ID)
+	if err == nil {
+		videoResponse.ProgressPercent = &job.ProgressPercent
+		if videoResponse.Status == api.UserGeneratedVideoStatusProcessing && job.EtaSeconds != nil {
+			etaSeconds := int(*job.EtaSeconds)
+			videoResponse.EtaSeconds = &etaSeconds
+		}
+	}
+
+	response := api.UserGeneratedVideoResponse{
+		Video: *videoResponse,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// CancelUserGeneratedVideo handles POST /user-generated-videos/{videoId}/cancel
+func (s *APIServer) CancelUserGeneratedVideo(w http.ResponseWriter, r *http.Request, videoId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Cancel the render, stopping ffmpeg and refunding the credits held for it
+	video, err := s.renderJobService.CancelRender(r.Context(), userID, uuid.UUID(videoId))
+	if errors.Is(err, service.ErrRenderNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "video_not_found",
+			Message: "Video not found or doesn't belong to user",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrRenderNotCancellable) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "render_not_cancellable",
+			Message: "Video has already finished rendering",
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to cancel render",
+		})
+		return
+	}
+
+	videoResponse, err := s.toUserGeneratedVideoAPIResponse(video, nil)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to convert video to API response: " + err.Error(),
+		})
+		return
+	}
+
+	response := api.UserGeneratedVideoResponse{
+		Video: *videoResponse,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// RerenderUserGeneratedVideo handles POST /user-generated-videos/{videoId}/rerender
+func (s *APIServer) RerenderUserGeneratedVideo(w http.ResponseWriter, r *http.Request, videoId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Queue a fresh copy, watermarked or not according to the user's current plan
+	video, err := s.renderJobService.RerenderVideo(r.Context(), userID, uuid.UUID(videoId))
+	if errors.Is(err, service.ErrRenderNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "video_not_found",
+			Message: "Video not found or doesn't belong to user",
+		})
+		return
+	}
+	if writeRenderOptionsError(w, err) {
+		return
+	}
+	if writeRenderBrandKitError(w, err) {
+		return
+	}
+	if errors.Is(err, service.ErrUnsupportedSource) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unsupported_source",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "processing_error",
+			Message: "Failed to queue video for rendering",
+		})
+		return
+	}
+
+	videoResponse, err := s.toUserGeneratedVideoAPIResponse(video, nil)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to convert video to API response: " + err.Error(),
+		})
+		return
+	}
+
+	response := api.UserGeneratedVideoResponse{
+		Video: *videoResponse,
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateRenderBatch handles POST /render-batches
+func (s *APIServer) CreateRenderBatch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Parse request body
+	var req api.CreateRenderBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	batchRequest := &service.RenderBatchRequest{
+		Options: &service.RenderOptions{
+			Style:      req.Style,
+			Captions:   req.Captions,
+			Clip:       req.Clip,
+			EndCard:    req.EndCard,
+			BrandKitID: (*uuid.UUID)(req.BrandKitId),
+		},
+	}
+	for _, id := range req.AiAvatarVideoIds {
+		batchRequest.AIAvatarVideoIDs = append(batchRequest.AIAvatarVideoIDs, uuid.UUID(id))
+	}
+	if req.HookIds != nil {
+		for _, id := range *req.HookIds {
+			batchRequest.HookIDs = append(batchRequest.HookIDs, uuid.UUID(id))
+		}
+	}
+	if req.Texts != nil {
+		batchRequest.Texts = *req.Texts
+	}
+	if req.OutputProfiles != nil {
+		batchRequest.Options.OutputProfiles = *req.OutputProfiles
+	}
+	if req.SubtitleMode != nil {
+		batchRequest.Options.SubtitleMode = *req.SubtitleMode
+	}
 
-		thumbnailURL, err := s.aiAvatarService.GenerateSignedURL(thumbnailPath, 24*time.Hour)
+	// Charge for and queue every render in the batch; workers pick them up in the background
+	summary, videos, err := s.renderJobService.EnqueueRenderBatch(r.Context(), userID, batchRequest)
+	if writeRenderOptionsError(w, err) {
+		return
+	}
+	if writeRenderBrandKitError(w, err) {
+		return
+	}
+	if errors.Is(err, service.ErrInvalidRenderBatch) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_render_batch",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrRenderBatchInputNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrUnsupportedSource) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unsupported_source",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, repository.ErrInsufficientCredits) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "insufficient_credits",
+			Message: "Not enough credits to render every video in this batch",
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "processing_error",
+			Message: "Failed to queue render batch",
+		})
+		return
+	}
+
+	videoResponses := make([]api.UserGeneratedVideo, 0, len(videos))
+	for _, video := range videos {
+		videoResponse, err := s.toUserGeneratedVideoAPIResponse(video, nil)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			json.NewEncoder(w).Encode(api.ErrorResponse{
 				Error:   "internal_error",
-				Message: "Failed to generate signed thumbnail URL",
+				Message: "Failed to convert video to API response: " + err.Error(),
 			})
 			return
 		}
+		videoResponses = append(videoResponses, *videoResponse)
+	}
 
-		videoResponses = append(videoResponses, api.UserGeneratedVideo{
-			Id:              openapi_types.UUID(video.ID),
-			UserId:          openapi_types.UUID(video.UserID.Bytes),
-			AiAvatarVideoId: openapi_types.UUID(video.AiAvatarVideoID.Bytes),
-			OverlayText:     video.OverlayText,
-			VideoUrl:        videoURL,
-			ThumbnailUrl:    thumbnailURL,
-			Status:          api.UserGeneratedVideoStatus(*video.Status),
-			CreatedAt:       video.CreatedAt,
+	response := api.RenderBatchResponse{
+		Batch:  toRenderBatchAPIResponse(summary),
+		Videos: videoResponses,
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetRenderBatches handles GET /render-batches
+func (s *APIServer) GetRenderBatches(w http.ResponseWriter, r *http.Request, params api.GetRenderBatchesParams) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
 		})
+		return
 	}
 
-	response := api.UserGeneratedVideosResponse{
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Set default pagination values
+	limit := int32(20)
+	offset := int32(0)
+
+	if params.Limit != nil {
+		limit = int32(*params.Limit)
+	}
+	if params.Offset != nil {
+		offset = int32(*params.Offset)
+	}
+
+	summaries, err := s.renderJobService.GetRenderBatches(r.Context(), userID, limit, offset)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve render batches",
+		})
+		return
+	}
+
+	batches := make([]api.RenderBatch, 0, len(summaries))
+	for _, summary := range summaries {
+		batches = append(batches, toRenderBatchAPIResponse(summary))
+	}
+
+	response := api.RenderBatchesResponse{
+		Batches: batches,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetRenderBatch handles GET /render-batches/{batchId}
+func (s *APIServer) GetRenderBatch(w http.ResponseWriter, r *http.Request, batchId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	summary, videos, err := s.renderJobService.GetRenderBatch(r.Context(), userID, uuid.UUID(batchId))
+	if errors.Is(err, service.ErrRenderBatchNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "batch_not_found",
+			Message: "Render batch not found or doesn't belong to user",
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve render batch",
+		})
+		return
+	}
+
+	videoResponses, err := s.toUserGeneratedVideoAPIResponses(r.Context(), videos)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response := api.RenderBatchResponse{
+		Batch:  toRenderBatchAPIResponse(summary),
 		Videos: videoResponses,
 	}
 
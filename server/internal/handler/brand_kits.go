@@ -0,0 +1,669 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/ethanhosier/reel-farm/internal/context_keys"
+	"github.com/ethanhosier/reel-farm/internal/service"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// multipartOverhead is the room allowed for the multipart framing and other fields around an uploaded file
+const multipartOverhead = 1 << 20
+
+// toBrandKitAPIResponse converts a brand kit to API response format, signing URLs for its font and logo
+func (s *APIServer) toBrandKitAPIResponse(brandKit *service.BrandKit) (*api.BrandKit, error) {
+	kit := brandKit.Kit
+	response := &api.BrandKit{
+		Id:              openapi_types.UUID(kit.ID),
+		Name:            kit.Name,
+		FontColor:       kit.FontColor,
+		StrokeColor:     kit.StrokeColor,
+		BackgroundColor: kit.BackgroundColor,
+		HighlightColor:  kit.HighlightColor,
+		CreatedAt:       kit.CreatedAt,
+		UpdatedAt:       kit.UpdatedAt,
+	}
+
+	if font := brandKit.Font; font != nil {
+		url, err := s.brandKitService.AssetURL(font)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign font URL: %w", err)
+		}
+		response.Font = &api.BrandKitFont{
+			Family:    *font.FontFamily,
+			Licensed:  font.Licensed,
+			SizeBytes: int(font.SizeBytes),
+			Url:       url,
+		}
+	}
+
+	if logo := brandKit.Logo; logo != nil {
+		url, err := s.brandKitService.AssetURL(logo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign logo URL: %w", err)
+		}
+		response.Logo = &api.BrandKitLogo{
+			SizeBytes: int(logo.SizeBytes),
+			Url:       url,
+		}
+	}
+
+	if background := brandKit.Background; background != nil {
+		url, err := s.brandKitService.AssetURL(background)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign background image URL: %w", err)
+		}
+		response.Background = &api.BrandKitBackground{
+			SizeBytes: int(background.SizeBytes),
+			Url:       url,
+		}
+	}
+
+	return response, nil
+}
+
+// writeBrandKit writes a brand kit as the response with the given status
+func (s *APIServer) writeBrandKit(w http.ResponseWriter, status int, brandKit *service.BrandKit) {
+	response, err := s.toBrandKitAPIResponse(brandKit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to convert brand kit to API response: " + err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// readUploadedFile reads the "file" part of a multipart upload of at most maxBytes, writing an error
+// response and returning false if it is missing or too large
+func readUploadedFile(w http.ResponseWriter, r *http.Request, maxBytes int64) ([]byte, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartOverhead); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(api.ErrorResponse{
+				Error:   "file_too_large",
+				Message: fmt.Sprintf("file must be at most %d MB", maxBytes>>20),
+			})
+			return nil, false
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Request body must be multipart/form-data",
+		})
+		return nil, false
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "file_required",
+			Message: "file is required",
+		})
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Failed to read uploaded file",
+		})
+		return nil, false
+	}
+	return data, true
+}
+
+// GetBrandKits handles GET /brand-kits
+func (s *APIServer) GetBrandKits(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	brandKits, err := s.brandKitService.GetBrandKits(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve brand kits",
+		})
+		return
+	}
+
+	brandKitResponses := make([]api.BrandKit, 0, len(brandKits))
+	for _, brandKit := range brandKits {
+		brandKitResponse, err := s.toBrandKitAPIResponse(brandKit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(api.ErrorResponse{
+				Error:   "internal_error",
+				Message: "Failed to convert brand kit to API response: " + err.Error(),
+			})
+			return
+		}
+		brandKitResponses = append(brandKitResponses, *brandKitResponse)
+	}
+
+	response := api.BrandKitsResponse{
+		BrandKits: brandKitResponses,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// CreateBrandKit handles POST /brand-kits
+func (s *APIServer) CreateBrandKit(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Parse request body
+	var req api.BrandKitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	brandKit, err := s.brandKitService.CreateBrandKit(r.Context(), userID, &req)
+	if errors.Is(err, service.ErrInvalidBrandKit) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_brand_kit",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to create brand kit",
+		})
+		return
+	}
+
+	s.writeBrandKit(w, http.StatusCreated, brandKit)
+}
+
+// GetBrandKit handles GET /brand-kits/{brandKitId}
+func (s *APIServer) GetBrandKit(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	brandKit, err := s.brandKitService.GetBrandKit(r.Context(), userID, uuid.UUID(brandKitId))
+	if errors.Is(err, service.ErrBrandKitNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "brand_kit_not_found",
+			Message: "Brand kit not found or doesn't belong to user",
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve brand kit",
+		})
+		return
+	}
+
+	s.writeBrandKit(w, http.StatusOK, brandKit)
+}
+
+// UpdateBrandKit handles PUT /brand-kits/{brandKitId}
+func (s *APIServer) UpdateBrandKit(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Parse request body
+	var req api.BrandKitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	brandKit, err := s.brandKitService.UpdateBrandKit(r.Context(), userID, uuid.UUID(brandKitId), &req)
+	if errors.Is(err, service.ErrInvalidBrandKit) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_brand_kit",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrBrandKitNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "brand_kit_not_found",
+			Message: "Brand kit not found or doesn't belong to user",
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to update brand kit",
+		})
+		return
+	}
+
+	s.writeBrandKit(w, http.StatusOK, brandKit)
+}
+
+// DeleteBrandKit handles DELETE /brand-kits/{brandKitId}
+func (s *APIServer) DeleteBrandKit(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	err = s.brandKitService.DeleteBrandKit(r.Context(), userID, uuid.UUID(brandKitId))
+	if errors.Is(err, service.ErrBrandKitNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "brand_kit_not_found",
+			Message: "Brand kit not found or doesn't belong to user",
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to delete brand kit",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UploadBrandKitFont handles PUT /brand-kits/{brandKitId}/font
+func (s *APIServer) UploadBrandKitFont(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	data, ok := readUploadedFile(w, r, service.MaxBrandKitFontBytes)
+	if !ok {
+		return
+	}
+
+	// The uploader has to confirm they hold a licence for the font
+	licensed, err := strconv.ParseBool(r.FormValue("licensed"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "licensed must be true or false",
+		})
+		return
+	}
+
+	brandKit, err := s.brandKitService.UploadFont(r.Context(), userID, uuid.UUID(brandKitId), data, licensed)
+	if errors.Is(err, service.ErrBrandKitNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "brand_kit_not_found",
+			Message: "Brand kit not found or doesn't belong to user",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidBrandKitFont) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_font",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrBrandKitFontNotLicensed) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "font_not_licensed",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to upload font",
+		})
+		return
+	}
+
+	s.writeBrandKit(w, http.StatusOK, brandKit)
+}
+
+// DeleteBrandKitFont handles DELETE /brand-kits/{brandKitId}/font
+func (s *APIServer) DeleteBrandKitFont(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID) {
+	s.deleteBrandKitAsset(w, r, brandKitId, s.brandKitService.DeleteFont)
+}
+
+// UploadBrandKitLogo handles PUT /brand-kits/{brandKitId}/logo
+func (s *APIServer) UploadBrandKitLogo(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	data, ok := readUploadedFile(w, r, service.MaxBrandKitLogoBytes)
+	if !ok {
+		return
+	}
+
+	brandKit, err := s.brandKitService.UploadLogo(r.Context(), userID, uuid.UUID(brandKitId), data)
+	if errors.Is(err, service.ErrBrandKitNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "brand_kit_not_found",
+			Message: "Brand kit not found or doesn't belong to user",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidBrandKitLogo) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_logo",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to upload logo",
+		})
+		return
+	}
+
+	s.writeBrandKit(w, http.StatusOK, brandKit)
+}
+
+// DeleteBrandKitLogo handles DELETE /brand-kits/{brandKitId}/logo
+func (s *APIServer) DeleteBrandKitLogo(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID) {
+	s.deleteBrandKitAsset(w, r, brandKitId, s.brandKitService.DeleteLogo)
+}
+
+// UploadBrandKitBackground handles PUT /brand-kits/{brandKitId}/background
+func (s *APIServer) UploadBrandKitBackground(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	data, ok := readUploadedFile(w, r, service.MaxBrandKitBackgroundBytes)
+	if !ok {
+		return
+	}
+
+	brandKit, err := s.brandKitService.UploadBackground(r.Context(), userID, uuid.UUID(brandKitId), data)
+	if errors.Is(err, service.ErrBrandKitNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "brand_kit_not_found",
+			Message: "Brand kit not found or doesn't belong to user",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidBrandKitBackground) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_background",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to upload background image",
+		})
+		return
+	}
+
+	s.writeBrandKit(w, http.StatusOK, brandKit)
+}
+
+// DeleteBrandKitBackground handles DELETE /brand-kits/{brandKitId}/background
+func (s *APIServer) DeleteBrandKitBackground(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID) {
+	s.deleteBrandKitAsset(w, r, brandKitId, s.brandKitService.DeleteBackground)
+}
+
+// deleteBrandKitAsset removes a brand kit's font, logo or background image with deleteAsset and responds with the updated kit
+func (s *APIServer) deleteBrandKitAsset(w http.ResponseWriter, r *http.Request, brandKitId openapi_types.UUID, deleteAsset func(ctx context.Context, userID, brandKitID uuid.UUID) (*service.BrandKit, error)) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	brandKit, err := deleteAsset(r.Context(), userID, uuid.UUID(brandKitId))
+	if errors.Is(err, service.ErrBrandKitNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "brand_kit_not_found",
+			Message: "Brand kit not found or doesn't belong to user",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrBrandKitAssetNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "asset_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to update brand kit",
+		})
+		return
+	}
+
+	s.writeBrandKit(w, http.StatusOK, brandKit)
+}
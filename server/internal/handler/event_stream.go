@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// eventStream writes Server-Sent Events, sending the stream's headers with the first one
+type eventStream struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	started    bool
+}
+
+// newEventStream creates an event stream that writes to w
+func newEventStream(w http.ResponseWriter) *eventStream {
+	return &eventStream{
+		w:          w,
+		controller: http.NewResponseController(w),
+	}
+}
+
+// send writes an event with data encoded as JSON and flushes it to the client
+func (e *eventStream) send(event string, data any) {
+	if !e.started {
+		e.w.Header().Set("Content-Type", "text/event-stream")
+		e.w.Header().Set("Cache-Control", "no-cache")
+		e.w.Header().Set("Connection", "keep-alive")
+		e.w.WriteHeader(http.StatusOK)
+		e.started = true
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Failed to encode %s event: %v", event, err)
+		return
+	}
+
+	// Write errors mean the client has gone, which cancels the request and so the generation
+	fmt.Fprintf(e.w, "event: %s\ndata: %s\n\n", event, payload)
+	_ = e.controller.Flush()
+}
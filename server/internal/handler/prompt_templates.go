@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/ethanhosier/reel-farm/internal/context_keys"
+	"github.com/ethanhosier/reel-farm/internal/repository"
+	"github.com/ethanhosier/reel-farm/internal/service"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// toPromptTemplateAPIResponse converts a prompt template to API response format
+func toPromptTemplateAPIResponse(promptTemplate *service.PromptTemplate) api.PromptTemplate {
+	template := promptTemplate.Template
+	return api.PromptTemplate{
+		Id:           openapi_types.UUID(template.ID),
+		Name:         template.Name,
+		Style:        api.PromptTemplateStyle(template.Style),
+		Version:      int(promptTemplate.Version.Version),
+		Instructions: promptTemplate.Version.Instructions,
+		Active:       template.Active,
+		CreatedAt:    template.CreatedAt,
+		UpdatedAt:    template.UpdatedAt,
+	}
+}
+
+// toPromptTemplateVersionAPIResponse converts a prompt template version to API response format
+func toPromptTemplateVersionAPIResponse(version *db.HookPromptTemplateVersion) api.PromptTemplateVersion {
+	response := api.PromptTemplateVersion{
+		Id:           openapi_types.UUID(version.ID),
+		Version:      int(version.Version),
+		Instructions: version.Instructions,
+		CreatedAt:    version.CreatedAt,
+	}
+	if version.CreatedBy.Valid {
+		createdBy := openapi_types.UUID(version.CreatedBy.Bytes)
+		response.CreatedBy = &createdBy
+	}
+	return response
+}
+
+// writePromptTemplates writes a list of prompt templates as the response
+func writePromptTemplates(w http.ResponseWriter, promptTemplates []*service.PromptTemplate) {
+	promptTemplateResponses := make([]api.PromptTemplate, 0, len(promptTemplates))
+	for _, promptTemplate := range promptTemplates {
+		promptTemplateResponses = append(promptTemplateResponses, toPromptTemplateAPIResponse(promptTemplate))
+	}
+
+	response := api.PromptTemplatesResponse{
+		PromptTemplates: promptTemplateResponses,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetPromptTemplates handles GET /prompt-templates
+func (s *APIServer) GetPromptTemplates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	promptTemplates, err := s.promptTemplateService.GetPromptTemplates(r.Context(), true)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve prompt templates",
+		})
+		return
+	}
+
+	writePromptTemplates(w, promptTemplates)
+}
+
+// GetAdminPromptTemplates handles GET /admin/prompt-templates
+func (s *APIServer) GetAdminPromptTemplates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	promptTemplates, err := s.promptTemplateService.GetPromptTemplates(r.Context(), false)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve prompt templates",
+		})
+		return
+	}
+
+	writePromptTemplates(w, promptTemplates)
+}
+
+// CreatePromptTemplate handles POST /admin/prompt-templates
+func (s *APIServer) CreatePromptTemplate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Parse request body
+	var req api.PromptTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	promptTemplate, err := s.promptTemplateService.CreatePromptTemplate(r.Context(), userID, &req)
+	if errors.Is(err, service.ErrInvalidPromptTemplate) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_prompt_template",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, repository.ErrPromptTemplateNameTaken) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "prompt_template_name_taken",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to create prompt template",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(toPromptTemplateAPIResponse(promptTemplate))
+}
+
+// GetPromptTemplate handles GET /admin/prompt-templates/{templateId}
+func (s *APIServer) GetPromptTemplate(w http.ResponseWriter, r *http.Request, templateId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	promptTemplate, err := s.promptTemplateService.GetPromptTemplate(r.Context(), uuid.UUID(templateId))
+	if errors.Is(err, service.ErrPromptTemplateNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "prompt_template_not_found",
+			Message: "Prompt template not found",
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve prompt template",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toPromptTemplateAPIResponse(promptTemplate))
+}
+
+// UpdatePromptTemplate handles PUT /admin/prompt-templates/{templateId}
+func (s *APIServer) UpdatePromptTemplate(w http.ResponseWriter, r *http.Request, templateId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract user ID from context
+	userIDStr := context_keys.GetUserID(r.Context())
+	if userIDStr == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found in context",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID format",
+		})
+		return
+	}
+
+	// Parse request body
+	var req api.PromptTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	promptTemplate, err := s.promptTemplateService.UpdatePromptTemplate(r.Context(), userID, uuid.UUID(templateId), &req)
+	if errors.Is(err, service.ErrInvalidPromptTemplate) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "invalid_prompt_template",
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrPromptTemplateNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "prompt_template_not_found",
+			Message: "Prompt template not found",
+		})
+		return
+	}
+	if errors.Is(err, repository.ErrPromptTemplateNameTaken) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "prompt_template_name_taken",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to update prompt template",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(toPromptTemplateAPIResponse(promptTemplate))
+}
+
+// GetPromptTemplateVersions handles GET /admin/prompt-templates/{templateId}/versions
+func (s *APIServer) GetPromptTemplateVersions(w http.ResponseWriter, r *http.Request, templateId openapi_types.UUID) {
+	w.Header().Set("Content-Type", "application/json")
+
+	versions, err := s.promptTemplateService.GetPromptTemplateVersions(r.Context(), uuid.UUID(templateId))
+	if errors.Is(err, service.ErrPromptTemplateNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "prompt_template_not_found",
+			Message: "Prompt template not found",
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(api.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to retrieve prompt template versions",
+		})
+		return
+	}
+
+	versionResponses := make([]api.PromptTemplateVersion, 0, len(versions))
+	for _, version := range versions {
+		versionResponses = append(versionResponses, toPromptTemplateVersionAPIResponse(version))
+	}
+
+	response := api.PromptTemplateVersionsResponse{
+		Versions: versionResponses,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/ethanhosier/reel-farm/internal/context_keys"
+	"github.com/google/uuid"
+)
+
+// adminPathPrefix is the path prefix of the endpoints only admins may call
+const adminPathPrefix = "/admin/"
+
+// AdminMiddleware restricts /admin/ endpoints to the users in adminUserIDs. It reads the user ID set by
+// AuthMiddleware, so must run after it. In no-auth mode every request is treated as an admin's.
+func AdminMiddleware(noAuth bool, adminUserIDs []uuid.UUID) func(http.Handler) http.Handler {
+	admins := make(map[uuid.UUID]bool, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		admins[id] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if noAuth || !strings.HasPrefix(r.URL.Path, adminPathPrefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID, err := uuid.Parse(context_keys.GetUserID(r.Context()))
+			if err != nil || !admins[userID] {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(api.ErrorResponse{
+					Error:   "forbidden",
+					Message: "Admin access required",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
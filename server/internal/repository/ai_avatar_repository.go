@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/ethanhosier/reel-farm/db"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -20,7 +23,7 @@ func NewAIAvatarRepository(pool *pgxpool.Pool) *AIAvatarRepository {
 	}
 }
 
-// GetAllVideos retrieves all AI avatar videos
+// GetAllVideos retrieves all AI avatar videos the render pipeline can use
 func (r *AIAvatarRepository) GetAllVideos(ctx context.Context) ([]*db.AiAvatarVideo, error) {
 	return r.queries.GetAllVideos(ctx)
 }
@@ -40,6 +43,16 @@ func (r *AIAvatarRepository) UpdateVideo(ctx context.Context, params *db.UpdateV
 	return r.queries.UpdateVideo(ctx, params)
 }
 
+// UpdateVideoMediaMetadata records the probed media metadata of a video
+func (r *AIAvatarRepository) UpdateVideoMediaMetadata(ctx context.Context, params *db.UpdateVideoMediaMetadataParams) (*db.AiAvatarVideo, error) {
+	return r.queries.UpdateVideoMediaMetadata(ctx, params)
+}
+
+// GetVideosMissingMediaMetadata retrieves the videos that have never been probed
+func (r *AIAvatarRepository) GetVideosMissingMediaMetadata(ctx context.Context) ([]*db.AiAvatarVideo, error) {
+	return r.queries.GetVideosMissingMediaMetadata(ctx)
+}
+
 // DeleteVideo deletes a video by ID
 func (r *AIAvatarRepository) DeleteVideo(ctx context.Context, id uuid.UUID) error {
 	return r.queries.DeleteVideo(ctx, id)
@@ -72,6 +85,22 @@ func (r *AIAvatarRepository) GetUserGeneratedVideoByID(ctx context.Context, id u
 	return r.queries.GetUserGeneratedVideoByID(ctx, id)
 }
 
+// GetCompletedUserGeneratedVideoByRenderSpecHash retrieves a user's most recent completed render with the given
+// render spec hash, or nil if there is none
+func (r *AIAvatarRepository) GetCompletedUserGeneratedVideoByRenderSpecHash(ctx context.Context, userID uuid.UUID, renderSpecHash string) (*db.UserGeneratedVideo, error) {
+	video, err := r.queries.GetCompletedUserGeneratedVideoByRenderSpecHash(ctx, &db.GetCompletedUserGeneratedVideoByRenderSpecHashParams{
+		UserID:         pgtype.UUID{Bytes: userID, Valid: true},
+		RenderSpecHash: &renderSpecHash,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user-generated video by render spec hash: %w", err)
+	}
+	return video, nil
+}
+
 // GetUserGeneratedVideosByUserID retrieves all user-generated videos for a specific user
 func (r *AIAvatarRepository) GetUserGeneratedVideosByUserID(ctx context.Context, userID uuid.UUID) ([]*db.UserGeneratedVideo, error) {
 	return r.queries.GetUserGeneratedVideosByUserID(ctx, pgtype.UUID{Bytes: userID, Valid: true})
@@ -86,3 +115,22 @@ func (r *AIAvatarRepository) UpdateUserGeneratedVideoStatus(ctx context.Context,
 func (r *AIAvatarRepository) UpdateUserGeneratedVideoFilenames(ctx context.Context, params *db.UpdateUserGeneratedVideoFilenamesParams) (*db.UserGeneratedVideo, error) {
 	return r.queries.UpdateUserGeneratedVideoFilenames(ctx, params)
 }
+
+// UpsertUserGeneratedVideoVariant records a rendered output profile variant, replacing any earlier render of the same profile
+func (r *AIAvatarRepository) UpsertUserGeneratedVideoVariant(ctx context.Context, params *db.UpsertUserGeneratedVideoVariantParams) (*db.UserGeneratedVideoVariant, error) {
+	return r.queries.UpsertUserGeneratedVideoVariant(ctx, params)
+}
+
+// GetVariantsByUserGeneratedVideoID retrieves the output profile variants of a user-generated video
+func (r *AIAvatarRepository) GetVariantsByUserGeneratedVideoID(ctx context.Context, videoID uuid.UUID) ([]*db.UserGeneratedVideoVariant, error) {
+	return r.queries.GetVariantsByUserGeneratedVideoID(ctx, pgtype.UUID{Bytes: videoID, Valid: true})
+}
+
+// GetVariantsByUserGeneratedVideoIDs retrieves the output profile variants of several user-generated videos
+func (r *AIAvatarRepository) GetVariantsByUserGeneratedVideoIDs(ctx context.Context, videoIDs []uuid.UUID) ([]*db.UserGeneratedVideoVariant, error) {
+	ids := make([]pgtype.UUID, len(videoIDs))
+	for i, id := range videoIDs {
+		ids[i] = pgtype.UUID{Bytes: id, Valid: true}
+	}
+	return r.queries.GetVariantsByUserGeneratedVideoIDs(ctx, ids)
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BrandKitRepository handles brand kit operations
+type BrandKitRepository struct {
+	queries *db.Queries
+	pool    *pgxpool.Pool
+}
+
+// NewBrandKitRepository creates a new brand kit repository
+func NewBrandKitRepository(pool *pgxpool.Pool) *BrandKitRepository {
+	return &BrandKitRepository{
+		queries: db.New(pool),
+		pool:    pool,
+	}
+}
+
+// CreateBrandKit creates a brand kit
+func (r *BrandKitRepository) CreateBrandKit(ctx context.Context, params *db.CreateBrandKitParams) (*db.BrandKit, error) {
+	kit, err := r.queries.CreateBrandKit(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create brand kit: %w", err)
+	}
+	return kit, nil
+}
+
+// GetBrandKitsByUserID gets a user's brand kits, newest first
+func (r *BrandKitRepository) GetBrandKitsByUserID(ctx context.Context, userID uuid.UUID) ([]*db.BrandKit, error) {
+	kits, err := r.queries.GetBrandKitsByUserID(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brand kits: %w", err)
+	}
+	return kits, nil
+}
+
+// GetBrandKitByID gets one of a user's brand kits, or nil if the user has no kit with that ID
+func (r *BrandKitRepository) GetBrandKitByID(ctx context.Context, id, userID uuid.UUID) (*db.BrandKit, error) {
+	kit, err := r.queries.GetBrandKitByID(ctx, &db.GetBrandKitByIDParams{
+		ID:     id,
+		UserID: pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get brand kit: %w", err)
+	}
+	return kit, nil
+}
+
+// UpdateBrandKit updates one of a user's brand kits, returning nil if the user has no kit with that ID
+func (r *BrandKitRepository) UpdateBrandKit(ctx context.Context, params *db.UpdateBrandKitParams) (*db.BrandKit, error) {
+	kit, err := r.queries.UpdateBrandKit(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update brand kit: %w", err)
+	}
+	return kit, nil
+}
+
+// DeleteBrandKit deletes one of a user's brand kits and its assets, reporting whether it existed
+func (r *BrandKitRepository) DeleteBrandKit(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	deleted, err := r.queries.DeleteBrandKit(ctx, &db.DeleteBrandKitParams{
+		ID:     id,
+		UserID: pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete brand kit: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// GetBrandKitAssetsByBrandKitIDs gets the assets of several brand kits in one query
+func (r *BrandKitRepository) GetBrandKitAssetsByBrandKitIDs(ctx context.Context, brandKitIDs []uuid.UUID) ([]*db.BrandKitAsset, error) {
+	// Convert []uuid.UUID to []pgtype.UUID
+	pgtypes := make([]pgtype.UUID, len(brandKitIDs))
+	for i, id := range brandKitIDs {
+		pgtypes[i] = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	assets, err := r.queries.GetBrandKitAssetsByBrandKitIDs(ctx, pgtypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brand kit assets: %w", err)
+	}
+	return assets, nil
+}
+
+// UpsertBrandKitAsset records a brand kit's font or logo, replacing any it already had
+func (r *BrandKitRepository) UpsertBrandKitAsset(ctx context.Context, params *db.UpsertBrandKitAssetParams) (*db.BrandKitAsset, error) {
+	asset, err := r.queries.UpsertBrandKitAsset(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save brand kit %s: %w", params.Kind, err)
+	}
+	return asset, nil
+}
+
+// DeleteBrandKitAsset removes a brand kit's font or logo, reporting whether it had one
+func (r *BrandKitRepository) DeleteBrandKitAsset(ctx context.Context, brandKitID uuid.UUID, kind string) (bool, error) {
+	deleted, err := r.queries.DeleteBrandKitAsset(ctx, &db.DeleteBrandKitAssetParams{
+		BrandKitID: pgtype.UUID{Bytes: brandKitID, Valid: true},
+		Kind:       kind,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete brand kit %s: %w", kind, err)
+	}
+	return deleted > 0, nil
+}
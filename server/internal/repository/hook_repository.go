@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ethanhosier/reel-farm/db"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -24,8 +26,9 @@ func NewHookRepository(pool *pgxpool.Pool) *HookRepository {
 	}
 }
 
-// CreateHooksBatch creates multiple hooks in a single database call
-func (r *HookRepository) CreateHooksBatch(ctx context.Context, userID uuid.UUID, generationID uuid.UUID, prompt string, hookTexts []string, creditsUsed int32) ([]*db.Hook, error) {
+// CreateHooksBatch creates multiple hooks in a single database call. promptTemplateVersionID is the
+// prompt template version they were generated with, or nil for the built-in prompt.
+func (r *HookRepository) CreateHooksBatch(ctx context.Context, userID uuid.UUID, generationID uuid.UUID, prompt string, hookTexts []string, creditsUsed int32, promptTemplateVersionID *uuid.UUID) ([]*db.Hook, error) {
 	// Create hook indices array
 	hookIndices := make([]int32, len(hookTexts))
 	for i := range hookTexts {
@@ -40,6 +43,9 @@ func (r *HookRepository) CreateHooksBatch(ctx context.Context, userID uuid.UUID,
 		Column5:      hookIndices,
 		CreditsUsed:  creditsUsed,
 	}
+	if promptTemplateVersionID != nil {
+		params.PromptTemplateVersionID = pgtype.UUID{Bytes: *promptTemplateVersionID, Valid: true}
+	}
 
 	hooks, err := r.queries.CreateHooksBatch(ctx, params)
 	if err != nil {
@@ -48,6 +54,56 @@ func (r *HookRepository) CreateHooksBatch(ctx context.Context, userID uuid.UUID,
 	return hooks, nil
 }
 
+// CreateHook creates a single hook, for generations that save each hook as it is produced.
+// promptTemplateVersionID is the prompt template version it was generated with, or nil for the built-in prompt.
+func (r *HookRepository) CreateHook(ctx context.Context, userID uuid.UUID, generationID uuid.UUID, prompt string, hookText string, hookIndex int32, creditsUsed int32, promptTemplateVersionID *uuid.UUID) (*db.Hook, error) {
+	params := &db.CreateHookParams{
+		UserID:       pgtype.UUID{Bytes: userID, Valid: true},
+		GenerationID: pgtype.UUID{Bytes: generationID, Valid: true},
+		Prompt:       prompt,
+		HookText:     hookText,
+		HookIndex:    hookIndex,
+		CreditsUsed:  creditsUsed,
+	}
+	if promptTemplateVersionID != nil {
+		params.PromptTemplateVersionID = pgtype.UUID{Bytes: *promptTemplateVersionID, Valid: true}
+	}
+
+	hook, err := r.queries.CreateHook(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create hook: %w", err)
+	}
+	return hook, nil
+}
+
+// CreateHookRefinements creates the hooks refined from parent with instruction in a single database
+// call. They share the parent's prompt and are linked to it as its children.
+func (r *HookRepository) CreateHookRefinements(ctx context.Context, userID uuid.UUID, generationID uuid.UUID, parent *db.Hook, instruction string, hookTexts []string, creditsUsed int32) ([]*db.Hook, error) {
+	// Create hook indices array
+	hookIndices := make([]int32, len(hookTexts))
+	for i := range hookTexts {
+		hookIndices[i] = int32(i)
+	}
+
+	params := &db.CreateHookRefinementsBatchParams{
+		UserID:                  pgtype.UUID{Bytes: userID, Valid: true},
+		GenerationID:            pgtype.UUID{Bytes: generationID, Valid: true},
+		Prompt:                  parent.Prompt,
+		HookTexts:               hookTexts,
+		HookIndices:             hookIndices,
+		CreditsUsed:             creditsUsed,
+		ParentHookID:            pgtype.UUID{Bytes: parent.ID, Valid: true},
+		RefinementInstruction:   &instruction,
+		PromptTemplateVersionID: parent.PromptTemplateVersionID,
+	}
+
+	hooks, err := r.queries.CreateHookRefinementsBatch(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create hook refinements: %w", err)
+	}
+	return hooks, nil
+}
+
 // GetHooksByUser gets hooks for a user with pagination
 func (r *HookRepository) GetHooksByUser(ctx context.Context, userID uuid.UUID, limit int32, offset int32) ([]*db.Hook, error) {
 	params := &db.GetHooksByUserParams{
@@ -82,6 +138,23 @@ func (r *HookRepository) GetHookByID(ctx context.Context, hookID uuid.UUID) (*db
 	return hook, nil
 }
 
+// GetUserHookByID gets one of a user's hooks, or nil if the user has no hook with that ID
+func (r *HookRepository) GetUserHookByID(ctx context.Context, hookID uuid.UUID, userID uuid.UUID) (*db.Hook, error) {
+	params := &db.GetUserHookByIDParams{
+		ID:     hookID,
+		UserID: pgtype.UUID{Bytes: userID, Valid: true},
+	}
+
+	hook, err := r.queries.GetUserHookByID(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get hook by ID: %w", err)
+	}
+	return hook, nil
+}
+
 // DeleteHook deletes a hook (only if it belongs to the user)
 func (r *HookRepository) DeleteHook(ctx context.Context, hookID uuid.UUID, userID uuid.UUID) error {
 	params := &db.DeleteHookParams{
@@ -116,6 +189,26 @@ func (r *HookRepository) DeleteHooks(ctx context.Context, hookIDs []uuid.UUID, u
 	return hooks, nil
 }
 
+// GetHooksByIDs gets the hooks with the given IDs that belong to the user
+func (r *HookRepository) GetHooksByIDs(ctx context.Context, hookIDs []uuid.UUID, userID uuid.UUID) ([]*db.Hook, error) {
+	// Convert []uuid.UUID to []pgtype.UUID
+	pgtypes := make([]pgtype.UUID, len(hookIDs))
+	for i, id := range hookIDs {
+		pgtypes[i] = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	params := &db.GetHooksByIDsParams{
+		HookIds: pgtypes,
+		UserID:  pgtype.UUID{Bytes: userID, Valid: true},
+	}
+
+	hooks, err := r.queries.GetHooksByIDs(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hooks by IDs: %w", err)
+	}
+	return hooks, nil
+}
+
 // GetUserHookCount gets the total number of hooks for a user
 func (r *HookRepository) GetUserHookCount(ctx context.Context, userID uuid.UUID) (int64, error) {
 	count, err := r.queries.GetUserHookCount(ctx, pgtype.UUID{Bytes: userID, Valid: true})
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// uniqueViolationCode is the Postgres error code for a unique constraint violation
+const uniqueViolationCode = "23505"
+
+// ErrPromptTemplateNameTaken is returned when a prompt template is given the name of another template
+var ErrPromptTemplateNameTaken = errors.New("a prompt template with this name already exists")
+
+// PromptTemplateRepository handles hook prompt template operations
+type PromptTemplateRepository struct {
+	queries *db.Queries
+	pool    *pgxpool.Pool
+}
+
+// NewPromptTemplateRepository creates a new prompt template repository
+func NewPromptTemplateRepository(pool *pgxpool.Pool) *PromptTemplateRepository {
+	return &PromptTemplateRepository{
+		queries: db.New(pool),
+		pool:    pool,
+	}
+}
+
+// CreatePromptTemplate creates a prompt template with instructions as its first version in a single transaction
+func (r *PromptTemplateRepository) CreatePromptTemplate(ctx context.Context, params *db.CreateHookPromptTemplateParams, instructions string, createdBy uuid.UUID) (*db.HookPromptTemplate, *db.HookPromptTemplateVersion, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Always rollback unless committed
+
+	txQueries := db.New(tx)
+
+	template, err := txQueries.CreateHookPromptTemplate(ctx, params)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, nil, ErrPromptTemplateNameTaken
+		}
+		return nil, nil, fmt.Errorf("failed to create prompt template: %w", err)
+	}
+
+	version, err := txQueries.CreateHookPromptTemplateVersion(ctx, &db.CreateHookPromptTemplateVersionParams{
+		TemplateID:   pgtype.UUID{Bytes: template.ID, Valid: true},
+		Version:      1,
+		Instructions: instructions,
+		CreatedBy:    pgtype.UUID{Bytes: createdBy, Valid: true},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create prompt template version: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return template, version, nil
+}
+
+// UpdatePromptTemplate updates a prompt template and, if instructions differ from its latest version, adds
+// them as a new version, in a single transaction. Updating the template first locks it, so concurrent edits
+// can't both claim the same version number. It returns nil if there is no template with the ID.
+func (r *PromptTemplateRepository) UpdatePromptTemplate(ctx context.Context, params *db.UpdateHookPromptTemplateParams, instructions string, updatedBy uuid.UUID) (*db.HookPromptTemplate, *db.HookPromptTemplateVersion, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Always rollback unless committed
+
+	txQueries := db.New(tx)
+
+	template, err := txQueries.UpdateHookPromptTemplate(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, nil
+		}
+		if isUniqueViolation(err) {
+			return nil, nil, ErrPromptTemplateNameTaken
+		}
+		return nil, nil, fmt.Errorf("failed to update prompt template: %w", err)
+	}
+
+	templateID := pgtype.UUID{Bytes: template.ID, Valid: true}
+	version, err := txQueries.GetLatestHookPromptTemplateVersion(ctx, templateID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get latest prompt template version: %w", err)
+	}
+
+	if version.Instructions != instructions {
+		version, err = txQueries.CreateHookPromptTemplateVersion(ctx, &db.CreateHookPromptTemplateVersionParams{
+			TemplateID:   templateID,
+			Version:      version.Version + 1,
+			Instructions: instructions,
+			CreatedBy:    pgtype.UUID{Bytes: updatedBy, Valid: true},
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create prompt template version: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return template, version, nil
+}
+
+// GetPromptTemplates gets prompt templates by name, only the active ones if activeOnly is set
+func (r *PromptTemplateRepository) GetPromptTemplates(ctx context.Context, activeOnly bool) ([]*db.HookPromptTemplate, error) {
+	var templates []*db.HookPromptTemplate
+	var err error
+	if activeOnly {
+		templates, err = r.queries.GetActiveHookPromptTemplates(ctx)
+	} else {
+		templates, err = r.queries.GetHookPromptTemplates(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt templates: %w", err)
+	}
+	return templates, nil
+}
+
+// GetPromptTemplateByID gets a prompt template, or nil if there is no template with that ID
+func (r *PromptTemplateRepository) GetPromptTemplateByID(ctx context.Context, id uuid.UUID) (*db.HookPromptTemplate, error) {
+	template, err := r.queries.GetHookPromptTemplateByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get prompt template: %w", err)
+	}
+	return template, nil
+}
+
+// GetVersionByID gets a prompt template version, or nil if there is no version with that ID
+func (r *PromptTemplateRepository) GetVersionByID(ctx context.Context, id uuid.UUID) (*db.HookPromptTemplateVersion, error) {
+	version, err := r.queries.GetHookPromptTemplateVersionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get prompt template version: %w", err)
+	}
+	return version, nil
+}
+
+// GetLatestVersion gets the version of a prompt template hook generation uses
+func (r *PromptTemplateRepository) GetLatestVersion(ctx context.Context, templateID uuid.UUID) (*db.HookPromptTemplateVersion, error) {
+	version, err := r.queries.GetLatestHookPromptTemplateVersion(ctx, pgtype.UUID{Bytes: templateID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest prompt template version: %w", err)
+	}
+	return version, nil
+}
+
+// GetLatestVersions gets the latest version of several prompt templates in one query
+func (r *PromptTemplateRepository) GetLatestVersions(ctx context.Context, templateIDs []uuid.UUID) ([]*db.HookPromptTemplateVersion, error) {
+	// Convert []uuid.UUID to []pgtype.UUID
+	pgtypes := make([]pgtype.UUID, len(templateIDs))
+	for i, id := range templateIDs {
+		pgtypes[i] = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	versions, err := r.queries.GetLatestHookPromptTemplateVersions(ctx, pgtypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest prompt template versions: %w", err)
+	}
+	return versions, nil
+}
+
+// GetVersions gets every version of a prompt template, newest first
+func (r *PromptTemplateRepository) GetVersions(ctx context.Context, templateID uuid.UUID) ([]*db.HookPromptTemplateVersion, error) {
+	versions, err := r.queries.GetHookPromptTemplateVersions(ctx, pgtype.UUID{Bytes: templateID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt template versions: %w", err)
+	}
+	return versions, nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
@@ -0,0 +1,419 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrInsufficientCredits is returned when a user cannot cover the credit hold for a render
+var ErrInsufficientCredits = errors.New("insufficient credits")
+
+// RenderJobRepository handles render job queue operations
+type RenderJobRepository struct {
+	queries *db.Queries
+	pool    *pgxpool.Pool
+}
+
+// NewRenderJobRepository creates a new render job repository
+func NewRenderJobRepository(pool *pgxpool.Pool) *RenderJobRepository {
+	return &RenderJobRepository{
+		queries: db.New(pool),
+		pool:    pool,
+	}
+}
+
+// CreateUserGeneratedVideoWithJob creates a user-generated video record and queues its render job in a
+// single transaction
+func (r *RenderJobRepository) CreateUserGeneratedVideoWithJob(ctx context.Context, params *db.CreateUserGeneratedVideoParams) (*db.UserGeneratedVideo, *db.RenderJob, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Always rollback unless committed
+
+	txQueries := db.New(tx)
+
+	video, err := txQueries.CreateUserGeneratedVideo(ctx, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create user-generated video: %w", err)
+	}
+
+	job, err := txQueries.CreateRenderJob(ctx, pgtype.UUID{Bytes: video.ID, Valid: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create render job: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return video, job, nil
+}
+
+// CreateRenderBatchWithJobs debits the credits for every render in a batch up front, creates the batch,
+// and creates each user-generated video with its own creditCost hold and render job, all in a single
+// transaction. Holding per video keeps capture and refund per render, so a failed render in a batch is
+// refunded the same way as a single render. creditRequestIDs gives the hold key of each video.
+func (r *RenderJobRepository) CreateRenderBatchWithJobs(ctx context.Context, batchParams *db.CreateRenderBatchParams, videoParams []*db.CreateUserGeneratedVideoParams, creditRequestIDs []string, creditCost int32) (*db.RenderBatch, []*db.UserGeneratedVideo, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Always rollback unless committed
+
+	txQueries := db.New(tx)
+
+	// Charge the whole batch at once so it is either fully paid for or not created at all
+	_, err = txQueries.AtomicDebitCredits(ctx, &db.AtomicDebitCreditsParams{
+		ID:      batchParams.UserID.Bytes,
+		Credits: batchParams.CreditsCharged,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrInsufficientCredits
+		}
+		return nil, nil, fmt.Errorf("failed to debit credits: %w", err)
+	}
+
+	batch, err := txQueries.CreateRenderBatch(ctx, batchParams)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create render batch: %w", err)
+	}
+
+	videos := make([]*db.UserGeneratedVideo, 0, len(videoParams))
+	for i, params := range videoParams {
+		_, err = txQueries.ReserveCredits(ctx, &db.ReserveCreditsParams{
+			UserID:    params.UserID,
+			RequestID: creditRequestIDs[i],
+			Amount:    creditCost,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to reserve credits: %w", err)
+		}
+
+		video, err := txQueries.CreateUserGeneratedVideo(ctx, params)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create user-generated video: %w", err)
+		}
+
+		if _, err := txQueries.CreateRenderJob(ctx, pgtype.UUID{Bytes: video.ID, Valid: true}); err != nil {
+			return nil, nil, fmt.Errorf("failed to create render job: %w", err)
+		}
+
+		videos = append(videos, video)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return batch, videos, nil
+}
+
+// GetRenderBatchByID gets a render batch by ID
+func (r *RenderJobRepository) GetRenderBatchByID(ctx context.Context, id uuid.UUID) (*db.RenderBatch, error) {
+	batch, err := r.queries.GetRenderBatchByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get render batch: %w", err)
+	}
+	return batch, nil
+}
+
+// GetRenderBatchSummariesByUserID gets a user's render batches, newest first, with how many of their renders are in each status
+func (r *RenderJobRepository) GetRenderBatchSummariesByUserID(ctx context.Context, userID uuid.UUID, limit int32, offset int32) ([]*db.GetRenderBatchSummariesByUserIDRow, error) {
+	params := &db.GetRenderBatchSummariesByUserIDParams{
+		UserID: pgtype.UUID{Bytes: userID, Valid: true},
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	summaries, err := r.queries.GetRenderBatchSummariesByUserID(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get render batches: %w", err)
+	}
+	return summaries, nil
+}
+
+// GetUserGeneratedVideosByRenderBatchID gets the user-generated videos of a render batch
+func (r *RenderJobRepository) GetUserGeneratedVideosByRenderBatchID(ctx context.Context, batchID uuid.UUID) ([]*db.UserGeneratedVideo, error) {
+	videos, err := r.queries.GetUserGeneratedVideosByRenderBatchID(ctx, pgtype.UUID{Bytes: batchID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get render batch videos: %w", err)
+	}
+	return videos, nil
+}
+
+// ClaimNextRenderJob marks the oldest runnable job as running and returns it, or nil if the queue is empty
+func (r *RenderJobRepository) ClaimNextRenderJob(ctx context.Context) (*db.RenderJob, error) {
+	job, err := r.queries.ClaimNextRenderJob(ctx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim render job: %w", err)
+	}
+	return job, nil
+}
+
+// UpdateRenderJobProgress records how far a running render job has got, which also counts as a
+// heartbeat. It reports false if the job is no longer running (e.g. it was cancelled), in which case
+// nothing is updated.
+func (r *RenderJobRepository) UpdateRenderJobProgress(ctx context.Context, id uuid.UUID, progressPercent float64, etaSeconds *int32) (bool, error) {
+	params := &db.UpdateRenderJobProgressParams{
+		ID:              id,
+		ProgressPercent: progressPercent,
+		EtaSeconds:      etaSeconds,
+	}
+
+	count, err := r.queries.UpdateRenderJobProgress(ctx, params)
+	if err != nil {
+		return false, fmt.Errorf("failed to update render job progress: %w", err)
+	}
+	return count > 0, nil
+}
+
+// HeartbeatRenderJob records that a running render job's worker is still alive, so the job is not
+// requeued as stale. It reports false if the job is no longer running (e.g. it was cancelled).
+func (r *RenderJobRepository) HeartbeatRenderJob(ctx context.Context, id uuid.UUID) (bool, error) {
+	count, err := r.queries.HeartbeatRenderJob(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to heartbeat render job: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetLatestRenderJobByVideoID gets the most recent render job for a user-generated video
+func (r *RenderJobRepository) GetLatestRenderJobByVideoID(ctx context.Context, videoID uuid.UUID) (*db.RenderJob, error) {
+	job, err := r.queries.GetLatestRenderJobByVideoID(ctx, pgtype.UUID{Bytes: videoID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest render job: %w", err)
+	}
+	return job, nil
+}
+
+// CompleteRenderJob marks a running render job and its video completed with the rendered output and
+// captures the video's credit hold, or refunds it if the render was reused from an identical earlier one
+// rather than encoded, in a single transaction. It reports false, changing nothing, if the job is no longer
+// running, as whoever stopped it has already settled the video.
+func (r *RenderJobRepository) CompleteRenderJob(ctx context.Context, id uuid.UUID, output *db.UpdateUserGeneratedVideoFilenamesParams, creditRequestID string, reused bool) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Always rollback unless committed
+
+	txQueries := db.New(tx)
+
+	count, err := txQueries.CompleteRenderJob(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to complete render job: %w", err)
+	}
+	if count == 0 {
+		return false, nil
+	}
+
+	status := "completed"
+	output.Status = &status
+	if _, err := txQueries.UpdateUserGeneratedVideoFilenames(ctx, output); err != nil {
+		return false, fmt.Errorf("failed to mark user-generated video completed: %w", err)
+	}
+
+	if reused {
+		if err := releaseCreditHold(ctx, txQueries, creditRequestID); err != nil {
+			return false, err
+		}
+	} else if err := txQueries.CaptureCreditsByRequestID(ctx, creditRequestID); err != nil {
+		return false, fmt.Errorf("failed to capture credits: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// FailRenderJob marks a running render job as failed with the error that stopped it, marks its video
+// failed with a message for the user and refunds its credit hold in a single transaction. Nothing is
+// changed if the job is no longer running, as whoever stopped it has already settled the video.
+func (r *RenderJobRepository) FailRenderJob(ctx context.Context, id, videoID uuid.UUID, lastError, errorMessage string, creditRequestID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Always rollback unless committed
+
+	txQueries := db.New(tx)
+
+	params := &db.FailRenderJobParams{
+		ID:        id,
+		LastError: &lastError,
+	}
+
+	count, err := txQueries.FailRenderJob(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to fail render job: %w", err)
+	}
+
+	if count > 0 {
+		if err := failUserGeneratedVideo(ctx, txQueries, videoID, errorMessage, creditRequestID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RetryRenderJob puts a running render job that hit a transient error back on the queue, to be picked
+// up again no earlier than runAfter. It reports false if the job is no longer running.
+func (r *RenderJobRepository) RetryRenderJob(ctx context.Context, id uuid.UUID, lastError string, runAfter time.Time) (bool, error) {
+	count, err := r.queries.RetryRenderJob(ctx, &db.RetryRenderJobParams{
+		ID:        id,
+		LastError: &lastError,
+		RunAfter:  pgtype.Timestamptz{Time: runAfter, Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to retry render job: %w", err)
+	}
+	return count > 0, nil
+}
+
+// failUserGeneratedVideo marks a processing video failed and refunds its credit hold, doing nothing if the
+// video has already been settled
+func failUserGeneratedVideo(ctx context.Context, queries *db.Queries, videoID uuid.UUID, errorMessage string, creditRequestID string) error {
+	status := "failed"
+	_, err := queries.UpdateUserGeneratedVideoStatus(ctx, &db.UpdateUserGeneratedVideoStatusParams{
+		ID:           videoID,
+		Status:       &status,
+		ErrorMessage: &errorMessage,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark user-generated video failed: %w", err)
+	}
+
+	return releaseCreditHold(ctx, queries, creditRequestID)
+}
+
+// CancelRenderJobsForVideo cancels a video's queued or running render jobs, marks the video
+// cancelled and refunds its credit hold in a single transaction. It returns the cancelled jobs,
+// which is empty if there was nothing left to cancel.
+func (r *RenderJobRepository) CancelRenderJobsForVideo(ctx context.Context, videoID uuid.UUID, creditRequestID string) ([]*db.RenderJob, *db.UserGeneratedVideo, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Always rollback unless committed
+
+	txQueries := db.New(tx)
+
+	jobs, err := txQueries.CancelRenderJobsForVideo(ctx, pgtype.UUID{Bytes: videoID, Valid: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to cancel render jobs: %w", err)
+	}
+	if len(jobs) == 0 {
+		return jobs, nil, nil
+	}
+
+	status := "cancelled"
+	video, err := txQueries.UpdateUserGeneratedVideoStatus(ctx, &db.UpdateUserGeneratedVideoStatusParams{
+		ID:     videoID,
+		Status: &status,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The video has already been settled, so leave its jobs alone too
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to mark user-generated video cancelled: %w", err)
+	}
+
+	if err := releaseCreditHold(ctx, txQueries, creditRequestID); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return jobs, video, nil
+}
+
+// releaseCreditHold refunds a reserved credit transaction, doing nothing if it was never held or already settled
+func releaseCreditHold(ctx context.Context, queries *db.Queries, creditRequestID string) error {
+	txn, err := queries.MarkReservedTxnRefundedByRequestID(ctx, creditRequestID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to mark credit transaction refunded: %w", err)
+	}
+
+	err = queries.RefundCredits(ctx, &db.RefundCreditsParams{
+		ID:      txn.UserID.Bytes,
+		Credits: txn.Amount,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refund credits: %w", err)
+	}
+	return nil
+}
+
+// RequeueStaleRenderJobs puts jobs whose worker has not checked in since staleBefore back on the queue,
+// unless they have already been attempted maxAttempts times
+func (r *RenderJobRepository) RequeueStaleRenderJobs(ctx context.Context, staleBefore time.Time, maxAttempts int32) (int64, error) {
+	count, err := r.queries.RequeueStaleRenderJobs(ctx, &db.RequeueStaleRenderJobsParams{
+		StaleBefore: pgtype.Timestamptz{Time: staleBefore, Valid: true},
+		MaxAttempts: maxAttempts,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue stale render jobs: %w", err)
+	}
+	return count, nil
+}
+
+// FailStaleRenderJobs fails jobs whose worker has gone away on each of maxAttempts attempts, the last
+// having not checked in since staleBefore, such as renders that keep running the server out of memory,
+// marking their videos failed and refunding their credit holds in a single transaction. creditRequestID
+// gives the hold key of a video.
+func (r *RenderJobRepository) FailStaleRenderJobs(ctx context.Context, staleBefore time.Time, maxAttempts int32, lastError, errorMessage string, creditRequestID func(videoID uuid.UUID) string) ([]*db.RenderJob, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // Always rollback unless committed
+
+	txQueries := db.New(tx)
+
+	jobs, err := txQueries.FailStaleRenderJobs(ctx, &db.FailStaleRenderJobsParams{
+		StaleBefore: pgtype.Timestamptz{Time: staleBefore, Valid: true},
+		LastError:   lastError,
+		MaxAttempts: maxAttempts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fail stale render jobs: %w", err)
+	}
+
+	for _, job := range jobs {
+		videoID := uuid.UUID(job.UserGeneratedVideoID.Bytes)
+		if err := failUserGeneratedVideo(ctx, txQueries, videoID, errorMessage, creditRequestID(videoID)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return jobs, nil
+}
@@ -1,15 +1,17 @@
 package service
 
 import (
-	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"image"
+	"image/png"
 	"io"
 	"log"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,11 +21,22 @@ import (
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/ethanhosier/reel-farm/db"
+	"github.com/ethanhosier/reel-farm/internal/ffmpeg"
 	"github.com/ethanhosier/reel-farm/internal/repository"
+	"github.com/ethanhosier/reel-farm/internal/textlayout"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// RenderProgress is a snapshot of how far an ffmpeg encode has got
+type RenderProgress struct {
+	Percent float64
+	ETA     time.Duration
+}
+
+// ProgressFunc receives render progress updates as ffmpeg reports them
+type ProgressFunc func(RenderProgress)
+
 type AIAvatarService struct {
 	repo             *repository.AIAvatarRepository
 	s3Client         *s3.Client
@@ -32,6 +45,9 @@ type AIAvatarService struct {
 	tempDir          string
 	cloudfrontDomain string
 	cloudfrontSigner *sign.URLSigner
+	fonts            *FontLibrary
+	sources          *SourceCache
+	watermark        *watermark // Burned into free plan renders; nil if not configured
 }
 
 func NewAIAvatarService(repo *repository.AIAvatarRepository, bucketName string) (*AIAvatarService, error) {
@@ -69,6 +85,25 @@ func NewAIAvatarService(repo *repository.AIAvatarRepository, bucketName string)
 	// Create CloudFront signer
 	cloudfrontSigner := sign.NewURLSigner(cloudfrontKeyPairID, privKey)
 
+	// Keep recently used source videos on disk; the size limit is configurable in megabytes
+	sourceCacheMaxBytes := int64(defaultSourceCacheMaxBytes)
+	if maxMBStr := os.Getenv("SOURCE_CACHE_MAX_MB"); maxMBStr != "" {
+		maxMB, err := strconv.ParseInt(maxMBStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SOURCE_CACHE_MAX_MB: %w", err)
+		}
+		sourceCacheMaxBytes = maxMB << 20
+	}
+	sources, err := NewSourceCache(filepath.Join(tempDir, "source-cache"), sourceCacheMaxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	watermark, err := watermarkFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	return &AIAvatarService{
 		repo:             repo,
 		s3Client:         s3Client,
@@ -77,10 +112,13 @@ func NewAIAvatarService(repo *repository.AIAvatarRepository, bucketName string)
 		tempDir:          tempDir,
 		cloudfrontDomain: cloudfrontDomain,
 		cloudfrontSigner: cloudfrontSigner,
+		fonts:            NewFontLibrary(),
+		sources:          sources,
+		watermark:        watermark,
 	}, nil
 }
 
-// GetAllVideos retrieves all AI avatar videos
+// GetAllVideos retrieves all AI avatar videos the render pipeline can use
 func (s *AIAvatarService) GetAllVideos(ctx context.Context) ([]*db.AiAvatarVideo, error) {
 	return s.repo.GetAllVideos(ctx)
 }
@@ -90,6 +128,11 @@ func (s *AIAvatarService) GetVideoByID(ctx context.Context, id uuid.UUID) (*db.A
 	return s.repo.GetVideoByID(ctx, id)
 }
 
+// GetVideosMissingMediaMetadata retrieves the videos that have never been probed
+func (s *AIAvatarService) GetVideosMissingMediaMetadata(ctx context.Context) ([]*db.AiAvatarVideo, error) {
+	return s.repo.GetVideosMissingMediaMetadata(ctx)
+}
+
 // CreateVideo creates a new video record
 func (s *AIAvatarService) CreateVideo(ctx context.Context, params *db.CreateVideoParams) (*db.AiAvatarVideo, error) {
 	return s.repo.CreateVideo(ctx, params)
@@ -115,234 +158,632 @@ func (s *AIAvatarService) GetUserGeneratedVideosByUserID(ctx context.Context, us
 	return s.repo.GetUserGeneratedVideosByUserID(ctx, userID)
 }
 
-// ProcessVideoWithTextOverlay downloads a video, adds text overlay, and uploads the result
-func (s *AIAvatarService) ProcessVideoWithTextOverlay(ctx context.Context, userID, aiAvatarVideoID uuid.UUID, videoURL, overlayText string) (*db.UserGeneratedVideo, error) {
-	// Generate unique filenames
-	videoID := uuid.New()
+// GetUserGeneratedVideoByID retrieves a specific user-generated video by ID
+func (s *AIAvatarService) GetUserGeneratedVideoByID(ctx context.Context, id uuid.UUID) (*db.UserGeneratedVideo, error) {
+	return s.repo.GetUserGeneratedVideoByID(ctx, id)
+}
+
+// GetUserGeneratedVideoVariants retrieves the output profile variants of a user-generated video
+func (s *AIAvatarService) GetUserGeneratedVideoVariants(ctx context.Context, videoID uuid.UUID) ([]*db.UserGeneratedVideoVariant, error) {
+	return s.repo.GetVariantsByUserGeneratedVideoID(ctx, videoID)
+}
+
+// GetUserGeneratedVideoVariantsByVideoIDs retrieves the output profile variants of several user-generated videos, keyed by video ID
+func (s *AIAvatarService) GetUserGeneratedVideoVariantsByVideoIDs(ctx context.Context, videoIDs []uuid.UUID) (map[uuid.UUID][]*db.UserGeneratedVideoVariant, error) {
+	variants, err := s.repo.GetVariantsByUserGeneratedVideoIDs(ctx, videoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byVideoID := make(map[uuid.UUID][]*db.UserGeneratedVideoVariant)
+	for _, variant := range variants {
+		videoID := uuid.UUID(variant.UserGeneratedVideoID.Bytes)
+		byVideoID[videoID] = append(byVideoID[videoID], variant)
+	}
+	return byVideoID, nil
+}
+
+// SourceVideoURL returns the CloudFront URL of an AI avatar video's source file
+func (s *AIAvatarService) SourceVideoURL(video *db.AiAvatarVideo) string {
+	return fmt.Sprintf("https://%s/ai-avatar/videos/%s", s.cloudfrontDomain, video.Filename)
+}
+
+// ValidateRenderOptions checks that render options can be rendered by this server for the given overlay text
+func (s *AIAvatarService) ValidateRenderOptions(options *RenderOptions, overlayText string) error {
+	if _, err := s.resolveRenderStyle(options); err != nil {
+		return err
+	}
+	if _, err := resolveCaptionOptions(options.captions(), overlayText); err != nil {
+		return err
+	}
+	if _, err := resolveOutputProfiles(options.OutputProfiles); err != nil {
+		return err
+	}
+	if _, err := resolveSubtitleDelivery(options.SubtitleMode); err != nil {
+		return err
+	}
+	if _, err := resolveClipOptions(options.Clip); err != nil {
+		return err
+	}
+	if _, err := resolveEndCard(options.EndCard, options.BrandKit); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ProcessVideoWithTextOverlay renders an existing user-generated video record: it fetches the source
+// video (through the local source cache), cuts any clip, appends any end card, adds the text overlay,
+// uploads the result along with a variant for each requested output profile and returns the filenames
+// to record the render as completed with. onProgress (optional) is called as ffmpeg reports encoding
+// progress. Cancelling ctx kills any running ffmpeg process; the render's temp files are removed either way.
+func (s *AIAvatarService) ProcessVideoWithTextOverlay(ctx context.Context, userGeneratedVideo *db.UserGeneratedVideo, source *db.AiAvatarVideo, onProgress ProgressFunc) (*db.UpdateUserGeneratedVideoFilenamesParams, error) {
+	// Filenames are derived from the record ID
+	videoID := userGeneratedVideo.ID
 	videoFilename := fmt.Sprintf("%s.mp4", videoID.String())
 	thumbnailFilename := fmt.Sprintf("%s.jpg", videoID.String())
 
-	// Download the original video
-	originalVideoPath := filepath.Join(s.tempDir, fmt.Sprintf("original_%s.mp4", videoID.String()))
-	if err := s.downloadVideo(ctx, videoURL, originalVideoPath); err != nil {
+	options, err := renderOptionsFromVideo(userGeneratedVideo)
+	if err != nil {
+		return nil, err
+	}
+	style, err := s.resolveRenderStyle(options)
+	if err != nil {
+		return nil, err
+	}
+	captions, err := resolveCaptionOptions(options.captions(), userGeneratedVideo.OverlayText)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := resolveOutputProfiles(options.OutputProfiles)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := resolveSubtitleDelivery(options.SubtitleMode)
+	if err != nil {
+		return nil, err
+	}
+	clip, err := resolveClipOptions(options.Clip)
+	if err != nil {
+		return nil, err
+	}
+	card, err := resolveEndCard(options.EndCard, options.BrandKit)
+	if err != nil {
+		return nil, err
+	}
+	outputCount := 1 + len(profiles)
+	if clip != nil {
+		outputCount++
+	}
+	if card != nil {
+		outputCount++
+	}
+
+	// Each render works in its own directory so everything can be removed in one go
+	workDir := filepath.Join(s.tempDir, videoID.String())
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	// Sources the catalog has marked unsupported would only fail part way through the render
+	if source.UnsupportedReason != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSource, *source.UnsupportedReason)
+	}
+
+	// Fetch the original video, from the local cache if it is there
+	originalVideoPath := filepath.Join(workDir, "original.mp4")
+	if err := s.sources.Fetch(ctx, source, s.SourceVideoURL(source), originalVideoPath); err != nil {
 		return nil, fmt.Errorf("failed to download video: %w", err)
 	}
-	defer os.Remove(originalVideoPath)
+
+	// Use the metadata recorded when the source was uploaded, probing sources that predate it
+	metadata := sourceMetadataFromVideo(source)
+	if metadata == nil {
+		metadata, err = ProbeSourceMetadata(ctx, originalVideoPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to probe video: %w", err)
+		}
+	}
+	if err := metadata.Validate(); err != nil {
+		return nil, err
+	}
+	info := metadata.videoInfo()
+
+	// Cut the clip first, so the overlay and every output are drawn over it
+	if clip != nil {
+		clipPath := filepath.Join(workDir, "clip.mp4")
+		info, err = renderClip(ctx, originalVideoPath, info, clip, clipPath, outputProgress(onProgress, 0, outputCount))
+		if err != nil {
+			return nil, err
+		}
+		originalVideoPath = clipPath
+	}
+	outputStep := outputCount - 1 - len(profiles)
+
+	overlay := &textOverlay{
+		Text:     userGeneratedVideo.OverlayText,
+		Style:    style,
+		Captions: captions,
+		BurnIn:   delivery.BurnIn,
+	}
+	if userGeneratedVideo.Watermarked {
+		if s.watermark == nil {
+			log.Printf("⚠️ Video %s was queued with a watermark, but none is configured", videoID)
+		}
+		overlay.Watermark = s.watermark
+	}
+	if delivery.BurnIn || (card != nil && card.hasText()) {
+		if overlay.Font, err = s.overlayFont(ctx, style, workDir); err != nil {
+			return nil, fmt.Errorf("failed to load font: %w", err)
+		}
+	}
+
+	// Add the end card after the clip, so every output ends with it; the overlay text stops as the
+	// clip starts fading into the card
+	if card != nil {
+		overlay.TextDuration = info.Duration - card.transition(info.Duration)
+		endCardPath := filepath.Join(workDir, "end_card.mp4")
+		info, err = s.renderEndCard(ctx, originalVideoPath, info, card, overlay.Font, style, workDir, endCardPath, outputProgress(onProgress, outputStep-1, outputCount))
+		if err != nil {
+			return nil, err
+		}
+		originalVideoPath = endCardPath
+	}
+
+	// Soft subtitles are written once and muxed into every output as well as published as sidecars
+	var srtPath, vttPath string
+	if delivery.Soft {
+		cues := subtitleCues(userGeneratedVideo.OverlayText, captions, overlay.textDuration(info))
+		srtPath, vttPath, err = writeSubtitleSidecars(workDir, videoID.String(), cues)
+		if err != nil {
+			return nil, err
+		}
+		overlay.SubtitleTrackPath = srtPath
+	}
 
 	// Process video with text overlay
-	processedVideoPath := filepath.Join(s.tempDir, videoFilename)
-	if err := s.addTextOverlay(originalVideoPath, overlayText, processedVideoPath); err != nil {
+	processedVideoPath := filepath.Join(workDir, videoFilename)
+	if err := s.addTextOverlay(ctx, originalVideoPath, info, overlay, nil, processedVideoPath, outputProgress(onProgress, outputStep, outputCount)); err != nil {
 		return nil, fmt.Errorf("failed to add text overlay: %w", err)
 	}
-	defer os.Remove(processedVideoPath)
 
 	// Extract thumbnail
-	thumbnailPath := filepath.Join(s.tempDir, thumbnailFilename)
-	if err := s.extractThumbnail(processedVideoPath, thumbnailPath); err != nil {
+	thumbnailPath := filepath.Join(workDir, thumbnailFilename)
+	if err := s.extractThumbnail(ctx, processedVideoPath, thumbnailPath); err != nil {
 		return nil, fmt.Errorf("failed to extract thumbnail: %w", err)
 	}
-	defer os.Remove(thumbnailPath)
 
 	// Upload processed video to S3
 	videoKey := fmt.Sprintf("user-generated-videos/videos/%s", videoFilename)
-	if err := s.uploadFile(processedVideoPath, videoKey); err != nil {
+	if err := s.uploadFile(ctx, processedVideoPath, videoKey); err != nil {
 		return nil, fmt.Errorf("failed to upload processed video: %w", err)
 	}
 
 	// Upload thumbnail to S3
 	thumbnailKey := fmt.Sprintf("user-generated-videos/thumbnails/%s", thumbnailFilename)
-	if err := s.uploadFile(thumbnailPath, thumbnailKey); err != nil {
+	if err := s.uploadFile(ctx, thumbnailPath, thumbnailKey); err != nil {
 		return nil, fmt.Errorf("failed to upload thumbnail: %w", err)
 	}
 
-	// Create database record
-	status := "completed"
-	userGeneratedVideo, err := s.repo.CreateUserGeneratedVideo(ctx, &db.CreateUserGeneratedVideoParams{
-		ID:                     videoID,
-		UserID:                 pgtype.UUID{Bytes: userID, Valid: true},
-		AiAvatarVideoID:        pgtype.UUID{Bytes: aiAvatarVideoID, Valid: true},
-		OverlayText:            overlayText,
-		GeneratedVideoFilename: videoFilename,
-		ThumbnailFilename:      thumbnailFilename,
-		Status:                 &status,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create database record: %w", err)
+	// Publish the subtitle sidecars next to the video
+	var srtFilename, vttFilename *string
+	if delivery.Soft {
+		for _, path := range []string{srtPath, vttPath} {
+			key := fmt.Sprintf("user-generated-videos/videos/%s", filepath.Base(path))
+			if err := s.uploadFile(ctx, path, key); err != nil {
+				return nil, fmt.Errorf("failed to upload subtitles: %w", err)
+			}
+		}
+		srtBase, vttBase := filepath.Base(srtPath), filepath.Base(vttPath)
+		srtFilename, vttFilename = &srtBase, &vttBase
 	}
 
-	return userGeneratedVideo, nil
-}
+	// Render, upload and record a variant for each requested output profile
+	for i, profile := range profiles {
+		variantFilename := profile.filename(videoID.String())
+		variantPath := filepath.Join(workDir, variantFilename)
+		if err := s.addTextOverlay(ctx, originalVideoPath, info, overlay, &profile, variantPath, outputProgress(onProgress, outputStep+i+1, outputCount)); err != nil {
+			return nil, fmt.Errorf("failed to render %s variant: %w", profile.Name, err)
+		}
 
-// downloadVideo downloads a video from URL to local path using Go HTTP client
-func (s *AIAvatarService) downloadVideo(ctx context.Context, url, outputPath string) error {
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 5 * time.Minute,
+		variantKey := fmt.Sprintf("user-generated-videos/videos/%s", variantFilename)
+		if err := s.uploadFile(ctx, variantPath, variantKey); err != nil {
+			return nil, fmt.Errorf("failed to upload %s variant: %w", profile.Name, err)
+		}
+
+		_, err := s.repo.UpsertUserGeneratedVideoVariant(ctx, &db.UpsertUserGeneratedVideoVariantParams{
+			UserGeneratedVideoID: pgtype.UUID{Bytes: videoID, Valid: true},
+			Profile:              string(profile.Name),
+			VideoFilename:        variantFilename,
+			Width:                int32(profile.Width),
+			Height:               int32(profile.Height),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record %s variant: %w", profile.Name, err)
+		}
 	}
 
+	// The record is marked completed along with its job, so a cancel can't land in between
+	return &db.UpdateUserGeneratedVideoFilenamesParams{
+		ID:                     videoID,
+		GeneratedVideoFilename: videoFilename,
+		ThumbnailFilename:      thumbnailFilename,
+		SubtitleSrtFilename:    srtFilename,
+		SubtitleVttFilename:    vttFilename,
+	}, nil
+}
+
+// downloadVideo downloads a video from URL to local path using the given HTTP client, returning its ETag (if any)
+func downloadVideo(ctx context.Context, client *http.Client, url, outputPath string) (string, error) {
 	// Create the request
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Perform the request
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to download video: %w", err)
+		return "", fmt.Errorf("failed to download video: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to download video: HTTP %d", resp.StatusCode)
+		return "", fmt.Errorf("failed to download video: HTTP %d", resp.StatusCode)
 	}
 
 	// Create output file
 	out, err := os.Create(outputPath)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return "", fmt.Errorf("failed to create output file: %w", err)
 	}
 	defer out.Close()
 
 	// Copy response body to file
 	_, err = io.Copy(out, resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to write video file: %w", err)
+		return "", fmt.Errorf("failed to write video file: %w", err)
 	}
 
-	return nil
+	return resp.Header.Get("ETag"), nil
 }
 
-// addTextOverlay adds text overlay to video using FFmpeg
-func (s *AIAvatarService) addTextOverlay(inputPath, text, outputPath string) error {
-	// Wrap text if it's too long (approximately 35 characters per line for 36px font)
-	wrappedLines := s.wrapTextToLines(text, 35)
+// ReuseCompletedRender copies the variants of the user's most recent completed render with the same render
+// spec hash to a user-generated video and returns that render's filenames to record the video as completed
+// with, so an identical render needs no ffmpeg. It returns nil if the video has no render spec hash or there
+// is no such render.
+func (s *AIAvatarService) ReuseCompletedRender(ctx context.Context, userGeneratedVideo *db.UserGeneratedVideo) (*db.UpdateUserGeneratedVideoFilenamesParams, error) {
+	if userGeneratedVideo.RenderSpecHash == nil {
+		return nil, nil
+	}
 
-	// Create a temporary text file with the wrapped text
-	tempTextFile := filepath.Join(s.tempDir, fmt.Sprintf("text_%d.txt", time.Now().UnixNano()))
-	joinedText := strings.Join(wrappedLines, "\n")
-	err := os.WriteFile(tempTextFile, []byte(joinedText), 0644)
+	original, err := s.repo.GetCompletedUserGeneratedVideoByRenderSpecHash(ctx, uuid.UUID(userGeneratedVideo.UserID.Bytes), *userGeneratedVideo.RenderSpecHash)
 	if err != nil {
-		return fmt.Errorf("failed to create temporary text file: %w", err)
+		return nil, err
+	}
+	if original == nil {
+		return nil, nil
 	}
-	defer os.Remove(tempTextFile)
 
-	// Check if font file exists
-	fontPath := "./TikTokDisplay-Medium.ttf"
-	if _, err := os.Stat(fontPath); os.IsNotExist(err) {
-		return fmt.Errorf("font file not found at %s", fontPath)
+	variants, err := s.repo.GetVariantsByUserGeneratedVideoID(ctx, original.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants to reuse: %w", err)
+	}
+	for _, variant := range variants {
+		_, err := s.repo.UpsertUserGeneratedVideoVariant(ctx, &db.UpsertUserGeneratedVideoVariantParams{
+			UserGeneratedVideoID: pgtype.UUID{Bytes: userGeneratedVideo.ID, Valid: true},
+			Profile:              variant.Profile,
+			VideoFilename:        variant.VideoFilename,
+			Width:                variant.Width,
+			Height:               variant.Height,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record reused %s variant: %w", variant.Profile, err)
+		}
 	}
 
-	// FFmpeg command to add text overlay
-	videoFilter := fmt.Sprintf("drawtext=textfile=%s:fontfile=%s:fontsize=36:fontcolor=white:x=(w-text_w)/2:y=(h-text_h)/2:borderw=3:bordercolor=black:text_align=center:line_spacing=16", tempTextFile, fontPath)
+	log.Printf("♻️ Reusing render of video %s for video %s", original.ID, userGeneratedVideo.ID)
+	return &db.UpdateUserGeneratedVideoFilenamesParams{
+		ID:                     userGeneratedVideo.ID,
+		GeneratedVideoFilename: original.GeneratedVideoFilename,
+		ThumbnailFilename:      original.ThumbnailFilename,
+		SubtitleSrtFilename:    original.SubtitleSrtFilename,
+		SubtitleVttFilename:    original.SubtitleVttFilename,
+	}, nil
+}
 
-	cmd := exec.Command("ffmpeg",
-		"-i", inputPath,
-		"-vf", videoFilter,
-		"-c:v", "libx264",
-		"-preset", "veryfast", // Middle ground between ultrafast and fast
-		"-crf", "28", // Better than 35, but faster than 23
-		"-threads", "1", // Single thread for 0.5 vCPU
-		"-c:a", "copy", // Copy audio without re-encoding
-		"-y", // Overwrite output file if it exists
-		outputPath,
-	)
+// textOverlay is the overlay text of a render, resolved, and where it ends up
+type textOverlay struct {
+	Text              string
+	Style             *overlayStyle
+	Captions          *captionOptions
+	BurnIn            bool             // Draw the text onto the picture
+	SubtitleTrackPath string           // SRT file muxed in as a mov_text subtitle track; empty for none
+	Watermark         *watermark       // Drawn over the picture, text included; nil for none
+	Font              *textlayout.Font // The font the text and end card are drawn in; nil if neither needs one
+	TextDuration      time.Duration    // How long the text is shown for; 0 for the whole video
+}
 
-	// Stream FFmpeg output in real time
-	log.Printf("🎬 Starting FFmpeg processing...")
-	log.Printf("📝 Command: %s", cmd.String())
+// textDuration is how long the text is shown for in a video described by info
+func (o *textOverlay) textDuration(info *videoInfo) time.Duration {
+	if o.TextDuration > 0 {
+		return o.TextDuration
+	}
+	return info.Duration
+}
 
-	// Create pipes for stdout and stderr
-	stdoutPipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("failed to create stdout pipe: %w", err)
+// addTextOverlay adds text overlay to video in the given style using FFmpeg, reporting encode progress to onProgress.
+// The text is drawn in Go, so emoji come out in colour, and composited over the video: static text as one
+// image for the whole video, animated captions as one image per step. Soft subtitles are muxed in as a
+// mov_text track instead of, or as well as, being burned in. A watermark goes over everything else.
+// info describes the source, and is used to fit the text to the frame and to turn ffmpeg's out_time into a percentage.
+func (s *AIAvatarService) addTextOverlay(ctx context.Context, inputPath string, info *videoInfo, overlay *textOverlay, profile *outputProfile, outputPath string, onProgress ProgressFunc) error {
+	// The frame the overlay is drawn on: a profile reframes the video first, and otherwise odd
+	// source dimensions are rounded down to even ones so the output can be encoded as yuv420p
+	var filters ffmpeg.Chain
+	frame := *info
+	if profile != nil {
+		filters = profile.videoFilters()
+		frame.Width = profile.Width
+		frame.Height = profile.Height
+	} else if info.Width%2 != 0 || info.Height%2 != 0 {
+		filters = ffmpeg.Chain{ffmpeg.EvenDimensions()}
+		frame.Width = info.Width &^ 1
+		frame.Height = info.Height &^ 1
 	}
-	stderrPipe, err := cmd.StderrPipe()
-	if err != nil {
-		return fmt.Errorf("failed to create stderr pipe: %w", err)
+	info = &frame
+
+	cmd := ffmpeg.New().Input(inputPath)
+	video := "0:v:0"
+
+	// Images composited over the video in turn: the text, then the watermark
+	type imageOverlay struct {
+		input int
+		x, y  int
 	}
+	var overlays []imageOverlay
+
+	if overlay.BurnIn {
+		style := overlay.Style
+
+		// Wrap the text to the frame width using the font's real metrics, shrinking it if it runs to too many lines
+		layout, err := overlay.Font.Layout(overlay.Text, textlayout.Options{
+			FontSize:    style.FontSize,
+			MinFontSize: style.minFontSize(),
+			MaxWidth:    style.maxTextWidth(info.Width),
+			MaxLines:    style.MaxLines,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to lay out text: %w", err)
+		}
+		if layout.Overflow {
+			log.Printf("⚠️ Overlay text needs %d lines at the minimum font size of %dpx", len(layout.Lines), layout.FontSize)
+		}
+		wrappedLines := layout.Lines
+
+		fittedStyle := *style
+		fittedStyle.FontSize = layout.FontSize
+		style = &fittedStyle
 
-	// Start the command
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start ffmpeg: %w", err)
+		frames := staticOverlayFrames(wrappedLines)
+		if overlay.Captions.Animated {
+			frames = captionFrames(overlay.Text, wrappedLines, overlay.Captions, overlay.textDuration(info))
+		}
+		if overlay.TextDuration > 0 {
+			// A blank frame clears the text for the rest of the video
+			frames = append(frames, overlayFrame{Start: overlay.TextDuration})
+		}
+		framesPath, x, y, err := writeOverlayFrames(overlay.Font, frames, style, info, outputPath)
+		if err != nil {
+			return err
+		}
+		overlays = append(overlays, imageOverlay{input: cmd.InputCount(), x: x, y: y})
+		cmd.ConcatInput(framesPath)
 	}
 
-	// Stream stdout and stderr in real time
-	go func() {
-		scanner := bufio.NewScanner(stdoutPipe)
-		for scanner.Scan() {
-			// log.Printf("FFmpeg stdout: %s", scanner.Text())
+	if overlay.Watermark != nil {
+		watermarkPath, x, y, err := overlay.Watermark.write(info, outputPath)
+		if err != nil {
+			return err
 		}
-	}()
+		overlays = append(overlays, imageOverlay{input: cmd.InputCount(), x: x, y: y})
+		cmd.Input(watermarkPath)
+	}
 
-	go func() {
-		scanner := bufio.NewScanner(stderrPipe)
-		for scanner.Scan() {
-			// log.Printf("FFmpeg stderr: %s", scanner.Text())
+	if len(overlays) > 0 {
+		// Reframe the video, then draw the images over it
+		graph := &ffmpeg.Graph{}
+		base := "0:v"
+		if len(filters) > 0 {
+			graph.Add([]string{"0:v"}, filters, []string{"base"})
+			base = "base"
 		}
-	}()
+		for i, o := range overlays {
+			output := fmt.Sprintf("overlay%d", i)
+			if i == len(overlays)-1 {
+				output = "v"
+			}
+			graph.Add([]string{base, fmt.Sprintf("%d:v", o.input)}, ffmpeg.Chain{ffmpeg.Overlay(o.x, o.y)}, []string{output})
+			base = output
+		}
+		cmd.FilterComplex(graph)
+		video = "[v]"
+	} else if len(filters) > 0 {
+		cmd.VideoFilter(filters)
+	}
+
+	// Platforms expect an audio track, so profile variants of silent sources get silence as long as the video
+	silence := profile != nil && !info.HasAudio
+	silenceInput := cmd.InputCount()
+	if silence {
+		cmd.SourceInput(ffmpeg.AudioNullSource(profile.SampleRate, info.Duration))
+	}
+	subtitleInput := cmd.InputCount()
+	if overlay.SubtitleTrackPath != "" {
+		cmd.Input(overlay.SubtitleTrackPath)
+	}
 
-	// Wait for the command to complete
-	err = cmd.Wait()
+	// Extra inputs mean the streams have to be picked explicitly
+	if cmd.InputCount() > 1 {
+		cmd.Map(video)
+		if silence {
+			cmd.Map(fmt.Sprintf("%d:a", silenceInput))
+		} else {
+			cmd.Map("0:a?")
+		}
+		if overlay.SubtitleTrackPath != "" {
+			cmd.Map(fmt.Sprintf("%d:s", subtitleInput)).SubtitleCodec("mov_text")
+		}
+	}
+
+	if profile != nil {
+		profile.encode(cmd)
+	} else {
+		cmd.VideoCodec("libx264")
+		cmd.Preset("veryfast") // Middle ground between ultrafast and fast
+		cmd.CRF(28)            // Better than 35, but faster than 23
+		cmd.AudioCodec("copy") // Copy audio without re-encoding
+	}
+	cmd.Threads(1) // Single thread for 0.5 vCPU
+	cmd.Output(outputPath)
+
+	log.Printf("🎬 Starting FFmpeg processing...")
+	log.Printf("📝 Command: %s", cmd.String())
+
+	// Turn ffmpeg's encoded time into a percentage of the video
+	start := time.Now()
+	err := cmd.Run(ctx, func(p ffmpeg.Progress) {
+		if onProgress == nil || info.Duration <= 0 {
+			return
+		}
+		onProgress(calculateRenderProgress(p.OutTime, info.Duration, time.Since(start)))
+	})
 	if err != nil {
-		return fmt.Errorf("ffmpeg failed: %w", err)
+		return err
 	}
 
 	log.Printf("✅ FFmpeg processing completed successfully")
 	return nil
 }
 
-// extractThumbnail extracts thumbnail from video
-func (s *AIAvatarService) extractThumbnail(videoPath, thumbnailPath string) error {
-	cmd := exec.Command("ffmpeg",
-		"-i", videoPath,
-		"-ss", "00:00:01", // Extract frame at 1 second
-		"-vframes", "1",
-		"-q:v", "3", // Middle ground quality thumbnail
-		"-threads", "1", // Single thread for 0.5 vCPU
-		"-y", // Overwrite output file if it exists
-		thumbnailPath,
-	)
-
-	// Stream FFmpeg output in real time
-	log.Printf("🖼️ Starting thumbnail extraction...")
-	log.Printf("📝 Command: %s", cmd.String())
+// writeOverlayFrames draws each overlay frame as a PNG next to the output and writes an ffconcat
+// script that shows them in turn. It returns the script's path and where to place the images in the
+// frame; every image is the same size, so they all go in the same place.
+func writeOverlayFrames(font *textlayout.Font, frames []overlayFrame, style *overlayStyle, info *videoInfo, outputPath string) (scriptPath string, x, y int, err error) {
+	opts := style.renderOptions()
+	images := make([]*image.RGBA, len(frames))
+	var bounds image.Rectangle
+	for i, frame := range frames {
+		if len(frame.Lines) == 0 {
+			continue
+		}
+		if images[i], err = font.Render(frame.Lines, opts); err != nil {
+			return "", 0, 0, fmt.Errorf("failed to draw overlay text: %w", err)
+		}
+		bounds = images[i].Bounds()
+	}
 
-	// Create pipes for stdout and stderr
-	stdoutPipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	base := strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+	entries := make([]ffmpeg.ConcatEntry, len(frames))
+	for i, img := range images {
+		if img == nil {
+			img = image.NewRGBA(bounds)
+		}
+
+		path, err := filepath.Abs(fmt.Sprintf("%s_overlay_%d.png", base, i))
+		if err != nil {
+			return "", 0, 0, fmt.Errorf("failed to resolve overlay image path: %w", err)
+		}
+		if err := writePNG(path, img); err != nil {
+			return "", 0, 0, err
+		}
+
+		entries[i].Path = path
+		if i+1 < len(frames) {
+			entries[i].Duration = frames[i+1].Start - frames[i].Start
+		}
+	}
+
+	scriptPath = base + "_overlay.ffconcat"
+	if err := os.WriteFile(scriptPath, []byte(ffmpeg.ConcatScript(entries)), 0644); err != nil {
+		return "", 0, 0, fmt.Errorf("failed to write overlay script: %w", err)
 	}
-	stderrPipe, err := cmd.StderrPipe()
+
+	x, y = style.overlayPosition(info.Width, info.Height, bounds.Dx(), bounds.Dy(), opts.Margin())
+	return scriptPath, x, y, nil
+}
+
+// writePNG encodes img as a PNG file
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
 	if err != nil {
-		return fmt.Errorf("failed to create stderr pipe: %w", err)
+		return fmt.Errorf("failed to create image file: %w", err)
 	}
+	defer file.Close()
 
-	// Start the command
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start ffmpeg: %w", err)
+	if err := png.Encode(file, img); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
 	}
+	return file.Close()
+}
 
-	// Stream stdout and stderr in real time
-	go func() {
-		scanner := bufio.NewScanner(stdoutPipe)
-		for scanner.Scan() {
-			log.Printf("FFmpeg thumbnail stdout: %s", scanner.Text())
-		}
-	}()
+// videoInfo is the subset of a source's metadata the render pipeline needs, with the frame size as displayed
+type videoInfo struct {
+	Duration  time.Duration
+	Width     int
+	Height    int
+	HasAudio  bool
+	FrameRate float64 // 0 if unknown
+}
+
+// calculateRenderProgress turns encoded time into a percentage and extrapolates the time remaining
+func calculateRenderProgress(outTime, duration, elapsed time.Duration) RenderProgress {
+	percent := float64(outTime) / float64(duration) * 100
+	percent = max(0, min(100, percent))
+
+	var eta time.Duration
+	if percent > 0 {
+		eta = time.Duration(float64(elapsed) * (100 - percent) / percent)
+	}
+
+	return RenderProgress{Percent: percent, ETA: eta}
+}
 
-	go func() {
-		scanner := bufio.NewScanner(stderrPipe)
-		for scanner.Scan() {
-			log.Printf("FFmpeg thumbnail stderr: %s", scanner.Text())
+// outputProgress maps the progress of one of a render's outputs (encoded one after another) onto
+// the render as a whole, assuming each output takes about as long to encode as the current one
+func outputProgress(onProgress ProgressFunc, index, count int) ProgressFunc {
+	if onProgress == nil {
+		return nil
+	}
+	return func(p RenderProgress) {
+		remaining := 100 - p.Percent
+		eta := p.ETA
+		if remaining > 0 {
+			eta = time.Duration(float64(p.ETA) * (remaining + float64(count-index-1)*100) / remaining)
 		}
-	}()
+		onProgress(RenderProgress{
+			Percent: (float64(index)*100 + p.Percent) / float64(count),
+			ETA:     eta,
+		})
+	}
+}
 
-	// Wait for the command to complete
-	err = cmd.Wait()
-	if err != nil {
-		return fmt.Errorf("ffmpeg thumbnail extraction failed: %w", err)
+// extractThumbnail extracts thumbnail from video
+func (s *AIAvatarService) extractThumbnail(ctx context.Context, videoPath, thumbnailPath string) error {
+	cmd := ffmpeg.New().
+		Input(videoPath).
+		Seek(time.Second). // Extract frame at 1 second
+		Frames(1).
+		Quality(3). // Middle ground quality thumbnail
+		Threads(1). // Single thread for 0.5 vCPU
+		Output(thumbnailPath)
+
+	log.Printf("🖼️ Starting thumbnail extraction...")
+	log.Printf("📝 Command: %s", cmd.String())
+
+	if err := cmd.Run(ctx, nil); err != nil {
+		return fmt.Errorf("thumbnail extraction failed: %w", err)
 	}
 
 	log.Printf("✅ Thumbnail extraction completed successfully")
@@ -350,14 +791,14 @@ func (s *AIAvatarService) extractThumbnail(videoPath, thumbnailPath string) erro
 }
 
 // uploadFile uploads a file to S3
-func (s *AIAvatarService) uploadFile(filePath, key string) error {
+func (s *AIAvatarService) uploadFile(ctx context.Context, filePath, key string) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	_, err = s.uploader.Upload(context.TODO(), &s3.PutObjectInput{
+	_, err = s.uploader.Upload(ctx, &s3.PutObjectInput{
 		Bucket: aws.String(s.bucketName),
 		Key:    aws.String(key),
 		Body:   file,
@@ -370,35 +811,43 @@ func (s *AIAvatarService) uploadFile(filePath, key string) error {
 	return nil
 }
 
-// wrapTextToLines wraps text to fit within a specified number of characters per line
-func (s *AIAvatarService) wrapTextToLines(text string, maxCharsPerLine int) []string {
-	words := strings.Fields(text)
-	var lines []string
-	var currentLine string
+// uploadData uploads in-memory data to S3
+func (s *AIAvatarService) uploadData(ctx context.Context, data []byte, key string) error {
+	_, err := s.uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
 
-	for _, word := range words {
-		if len(currentLine)+len(word)+1 <= maxCharsPerLine {
-			if currentLine == "" {
-				currentLine = word
-			} else {
-				currentLine += " " + word // Single space between words
-			}
-		} else {
-			if currentLine != "" {
-				lines = append(lines, currentLine)
-				currentLine = word
-			} else {
-				// Word is longer than maxCharsPerLine, add it anyway
-				lines = append(lines, word)
-			}
-		}
+	if err != nil {
+		return fmt.Errorf("failed to upload data: %w", err)
+	}
+
+	return nil
+}
+
+// downloadFile downloads an object from S3 to a local file
+func (s *AIAvatarService) downloadFile(ctx context.Context, key, path string) error {
+	resp, err := s.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
 	}
+	defer out.Close()
 
-	if currentLine != "" {
-		lines = append(lines, currentLine)
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
 	}
 
-	return lines
+	return nil
 }
 
 // GenerateSignedURL creates a signed CloudFront URL for user-generated videos
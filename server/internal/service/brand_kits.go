@@ -0,0 +1,485 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Registers the JPEG decoder for background images
+	"image/png"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/ethanhosier/reel-farm/internal/repository"
+	"github.com/ethanhosier/reel-farm/internal/textlayout"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// MaxBrandKitFontBytes is the largest font file a brand kit accepts
+	MaxBrandKitFontBytes = 10 << 20
+	// MaxBrandKitLogoBytes is the largest logo file a brand kit accepts
+	MaxBrandKitLogoBytes = 5 << 20
+	// MaxBrandKitBackgroundBytes is the largest background image a brand kit accepts
+	MaxBrandKitBackgroundBytes = 10 << 20
+
+	maxBrandKitNameLength = 100
+	maxBrandKitLogoSize   = 4096
+
+	brandKitFontKind       = "font"
+	brandKitLogoKind       = "logo"
+	brandKitBackgroundKind = "background"
+)
+
+var (
+	// ErrBrandKitNotFound is returned when a brand kit does not exist or belongs to another user
+	ErrBrandKitNotFound = errors.New("brand kit not found")
+	// ErrBrandKitAssetNotFound is returned when removing a font, logo or background image a brand kit does not have
+	ErrBrandKitAssetNotFound = errors.New("brand kit has no such asset")
+	// ErrInvalidBrandKit is returned when a brand kit's name or colours fail validation
+	ErrInvalidBrandKit = errors.New("invalid brand kit")
+	// ErrInvalidBrandKitFont is returned when an uploaded font is too large or cannot be parsed
+	ErrInvalidBrandKitFont = errors.New("invalid brand kit font")
+	// ErrBrandKitFontNotLicensed is returned when the uploader has not confirmed a licence for a font,
+	// or the font's own embedding permissions forbid using it
+	ErrBrandKitFontNotLicensed = errors.New("brand kit font is not licensed for use")
+	// ErrInvalidBrandKitLogo is returned when an uploaded logo is too large or not a PNG
+	ErrInvalidBrandKitLogo = errors.New("invalid brand kit logo")
+	// ErrInvalidBrandKitBackground is returned when an uploaded background image is too large or not a PNG or JPEG
+	ErrInvalidBrandKitBackground = errors.New("invalid brand kit background image")
+)
+
+// BrandKitService manages users' brand kits and stores their fonts, logos and background images in S3
+type BrandKitService struct {
+	repo            *repository.BrandKitRepository
+	aiAvatarService *AIAvatarService
+}
+
+// NewBrandKitService creates a new brand kit service; files are stored through aiAvatarService
+func NewBrandKitService(repo *repository.BrandKitRepository, aiAvatarService *AIAvatarService) *BrandKitService {
+	return &BrandKitService{
+		repo:            repo,
+		aiAvatarService: aiAvatarService,
+	}
+}
+
+// BrandKit is a brand kit with its font, logo and background image, each nil if it has not been uploaded
+type BrandKit struct {
+	Kit        *db.BrandKit
+	Font       *db.BrandKitAsset
+	Logo       *db.BrandKitAsset
+	Background *db.BrandKitAsset
+}
+
+// brandKitFont is a brand kit font as a render uses it
+type brandKitFont struct {
+	Family   string `json:"family"`
+	Filename string `json:"filename"`
+	SHA256   string `json:"sha256"`
+}
+
+// brandKitImage is a brand kit logo or background image as a render uses it
+type brandKitImage struct {
+	Filename string `json:"filename"`
+	SHA256   string `json:"sha256"`
+}
+
+// brandKitStyle is the part of a brand kit renders use, copied into the render options when a render
+// is queued so later changes to the kit do not affect it
+type brandKitStyle struct {
+	FontColor       *string        `json:"font_color,omitempty"`
+	StrokeColor     *string        `json:"stroke_color,omitempty"`
+	BackgroundColor *string        `json:"background_color,omitempty"`
+	HighlightColor  *string        `json:"highlight_color,omitempty"`
+	Font            *brandKitFont  `json:"font,omitempty"`
+	Logo            *brandKitImage `json:"logo,omitempty"`
+	Background      *brandKitImage `json:"background,omitempty"`
+}
+
+// GetBrandKits gets a user's brand kits, newest first
+func (s *BrandKitService) GetBrandKits(ctx context.Context, userID uuid.UUID) ([]*BrandKit, error) {
+	kits, err := s.repo.GetBrandKitsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(kits) == 0 {
+		return []*BrandKit{}, nil
+	}
+
+	kitIDs := make([]uuid.UUID, len(kits))
+	for i, kit := range kits {
+		kitIDs[i] = kit.ID
+	}
+	assets, err := s.repo.GetBrandKitAssetsByBrandKitIDs(ctx, kitIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	brandKits := make([]*BrandKit, len(kits))
+	byID := make(map[uuid.UUID]*BrandKit, len(kits))
+	for i, kit := range kits {
+		brandKits[i] = &BrandKit{Kit: kit}
+		byID[kit.ID] = brandKits[i]
+	}
+	for _, asset := range assets {
+		byID[uuid.UUID(asset.BrandKitID.Bytes)].setAsset(asset)
+	}
+	return brandKits, nil
+}
+
+// GetBrandKit gets one of a user's brand kits
+func (s *BrandKitService) GetBrandKit(ctx context.Context, userID, brandKitID uuid.UUID) (*BrandKit, error) {
+	kit, err := s.repo.GetBrandKitByID(ctx, brandKitID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if kit == nil {
+		return nil, ErrBrandKitNotFound
+	}
+	return s.withAssets(ctx, kit)
+}
+
+// CreateBrandKit validates and creates a brand kit for a user
+func (s *BrandKitService) CreateBrandKit(ctx context.Context, userID uuid.UUID, req *api.BrandKitRequest) (*BrandKit, error) {
+	name, err := validateBrandKitRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	kit, err := s.repo.CreateBrandKit(ctx, &db.CreateBrandKitParams{
+		UserID:          pgtype.UUID{Bytes: userID, Valid: true},
+		Name:            name,
+		FontColor:       req.FontColor,
+		StrokeColor:     req.StrokeColor,
+		BackgroundColor: req.BackgroundColor,
+		HighlightColor:  req.HighlightColor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &BrandKit{Kit: kit}, nil
+}
+
+// UpdateBrandKit validates and replaces the name and colours of one of a user's brand kits
+func (s *BrandKitService) UpdateBrandKit(ctx context.Context, userID, brandKitID uuid.UUID, req *api.BrandKitRequest) (*BrandKit, error) {
+	name, err := validateBrandKitRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	kit, err := s.repo.UpdateBrandKit(ctx, &db.UpdateBrandKitParams{
+		ID:              brandKitID,
+		UserID:          pgtype.UUID{Bytes: userID, Valid: true},
+		Name:            name,
+		FontColor:       req.FontColor,
+		StrokeColor:     req.StrokeColor,
+		BackgroundColor: req.BackgroundColor,
+		HighlightColor:  req.HighlightColor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if kit == nil {
+		return nil, ErrBrandKitNotFound
+	}
+	return s.withAssets(ctx, kit)
+}
+
+// DeleteBrandKit deletes one of a user's brand kits. The files stay in S3, as they are shared by every
+// kit with the same font or logo and renders already queued with the kit still need them.
+func (s *BrandKitService) DeleteBrandKit(ctx context.Context, userID, brandKitID uuid.UUID) error {
+	deleted, err := s.repo.DeleteBrandKit(ctx, brandKitID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrBrandKitNotFound
+	}
+	return nil
+}
+
+// UploadFont checks that data is a TrueType or OpenType font the user is licensed to use, stores it
+// and makes it the brand kit's font
+func (s *BrandKitService) UploadFont(ctx context.Context, userID, brandKitID uuid.UUID, data []byte, licensed bool) (*BrandKit, error) {
+	kit, err := s.repo.GetBrandKitByID(ctx, brandKitID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if kit == nil {
+		return nil, ErrBrandKitNotFound
+	}
+
+	if len(data) > MaxBrandKitFontBytes {
+		return nil, fmt.Errorf("%w: font file must be at most %d MB", ErrInvalidBrandKitFont, MaxBrandKitFontBytes>>20)
+	}
+	info, err := textlayout.InspectFont(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBrandKitFont, err)
+	}
+	if !licensed {
+		return nil, fmt.Errorf("%w: confirm you hold a licence to use %s in videos", ErrBrandKitFontNotLicensed, info.Family)
+	}
+	if info.RestrictedLicense {
+		return nil, fmt.Errorf("%w: the licence embedded in %s does not allow it to be used without the foundry's permission", ErrBrandKitFontNotLicensed, info.Family)
+	}
+
+	ext := ".ttf"
+	if bytes.HasPrefix(data, []byte("OTTO")) {
+		ext = ".otf"
+	}
+	if err := s.saveAsset(ctx, kit.ID, brandKitFontKind, data, ext, &info.Family, true); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🔤 Uploaded font %q to brand kit %s", info.Family, kit.ID)
+	return s.withAssets(ctx, kit)
+}
+
+// UploadLogo checks that data is a PNG of a sensible size, stores it and makes it the brand kit's logo
+func (s *BrandKitService) UploadLogo(ctx context.Context, userID, brandKitID uuid.UUID, data []byte) (*BrandKit, error) {
+	kit, err := s.repo.GetBrandKitByID(ctx, brandKitID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if kit == nil {
+		return nil, ErrBrandKitNotFound
+	}
+
+	if len(data) > MaxBrandKitLogoBytes {
+		return nil, fmt.Errorf("%w: logo must be at most %d MB", ErrInvalidBrandKitLogo, MaxBrandKitLogoBytes>>20)
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: logo must be a PNG", ErrInvalidBrandKitLogo)
+	}
+	if config.Width > maxBrandKitLogoSize || config.Height > maxBrandKitLogoSize {
+		return nil, fmt.Errorf("%w: logo must be at most %dx%d pixels", ErrInvalidBrandKitLogo, maxBrandKitLogoSize, maxBrandKitLogoSize)
+	}
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("%w: logo is not a valid PNG", ErrInvalidBrandKitLogo)
+	}
+
+	if err := s.saveAsset(ctx, kit.ID, brandKitLogoKind, data, ".png", nil, false); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🖼️ Uploaded logo to brand kit %s", kit.ID)
+	return s.withAssets(ctx, kit)
+}
+
+// UploadBackground checks that data is a PNG or JPEG of a sensible size, stores it and makes it the
+// brand kit's background image
+func (s *BrandKitService) UploadBackground(ctx context.Context, userID, brandKitID uuid.UUID, data []byte) (*BrandKit, error) {
+	kit, err := s.repo.GetBrandKitByID(ctx, brandKitID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if kit == nil {
+		return nil, ErrBrandKitNotFound
+	}
+
+	if len(data) > MaxBrandKitBackgroundBytes {
+		return nil, fmt.Errorf("%w: background image must be at most %d MB", ErrInvalidBrandKitBackground, MaxBrandKitBackgroundBytes>>20)
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "png" && format != "jpeg") {
+		return nil, fmt.Errorf("%w: background image must be a PNG or JPEG", ErrInvalidBrandKitBackground)
+	}
+	if config.Width > maxBrandKitLogoSize || config.Height > maxBrandKitLogoSize {
+		return nil, fmt.Errorf("%w: background image must be at most %dx%d pixels", ErrInvalidBrandKitBackground, maxBrandKitLogoSize, maxBrandKitLogoSize)
+	}
+	if _, _, err := image.Decode(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("%w: background image is not a valid %s", ErrInvalidBrandKitBackground, strings.ToUpper(format))
+	}
+
+	ext := ".png"
+	if format == "jpeg" {
+		ext = ".jpg"
+	}
+	if err := s.saveAsset(ctx, kit.ID, brandKitBackgroundKind, data, ext, nil, false); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🖼️ Uploaded background image to brand kit %s", kit.ID)
+	return s.withAssets(ctx, kit)
+}
+
+// DeleteFont removes a brand kit's font, so renders with the kit use the default font again
+func (s *BrandKitService) DeleteFont(ctx context.Context, userID, brandKitID uuid.UUID) (*BrandKit, error) {
+	return s.deleteAsset(ctx, userID, brandKitID, brandKitFontKind)
+}
+
+// DeleteLogo removes a brand kit's logo
+func (s *BrandKitService) DeleteLogo(ctx context.Context, userID, brandKitID uuid.UUID) (*BrandKit, error) {
+	return s.deleteAsset(ctx, userID, brandKitID, brandKitLogoKind)
+}
+
+// DeleteBackground removes a brand kit's background image
+func (s *BrandKitService) DeleteBackground(ctx context.Context, userID, brandKitID uuid.UUID) (*BrandKit, error) {
+	return s.deleteAsset(ctx, userID, brandKitID, brandKitBackgroundKind)
+}
+
+// AssetURL returns a signed CloudFront URL for a brand kit's font, logo or background image, valid for 24 hours
+func (s *BrandKitService) AssetURL(asset *db.BrandKitAsset) (string, error) {
+	return s.aiAvatarService.GenerateSignedURL(brandKitAssetKey(asset.Kind, asset.Filename), 24*time.Hour)
+}
+
+// ApplyToRenderOptions copies the brand kit named in the render options into them, so the render uses
+// the kit as it is now. Options without a brand kit are left as they are.
+func (s *BrandKitService) ApplyToRenderOptions(ctx context.Context, userID uuid.UUID, options *RenderOptions) error {
+	options.BrandKit = nil
+	if options.BrandKitID == nil {
+		return nil
+	}
+
+	brandKit, err := s.GetBrandKit(ctx, userID, *options.BrandKitID)
+	if err != nil {
+		return err
+	}
+
+	kit := brandKit.Kit
+	style := &brandKitStyle{
+		FontColor:       kit.FontColor,
+		StrokeColor:     kit.StrokeColor,
+		BackgroundColor: kit.BackgroundColor,
+		HighlightColor:  kit.HighlightColor,
+	}
+	if font := brandKit.Font; font != nil {
+		style.Font = &brandKitFont{
+			Family:   *font.FontFamily,
+			Filename: font.Filename,
+			SHA256:   font.Sha256,
+		}
+	}
+	if logo := brandKit.Logo; logo != nil {
+		style.Logo = &brandKitImage{Filename: logo.Filename, SHA256: logo.Sha256}
+	}
+	if background := brandKit.Background; background != nil {
+		style.Background = &brandKitImage{Filename: background.Filename, SHA256: background.Sha256}
+	}
+	options.BrandKit = style
+	return nil
+}
+
+// saveAsset uploads a font, logo or background image under its content hash and records it as the kit's asset of that kind
+func (s *BrandKitService) saveAsset(ctx context.Context, brandKitID uuid.UUID, kind string, data []byte, ext string, fontFamily *string, licensed bool) error {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	filename := hash + ext
+
+	if err := s.aiAvatarService.uploadData(ctx, data, brandKitAssetKey(kind, filename)); err != nil {
+		return fmt.Errorf("failed to upload brand kit %s: %w", kind, err)
+	}
+
+	_, err := s.repo.UpsertBrandKitAsset(ctx, &db.UpsertBrandKitAssetParams{
+		BrandKitID: pgtype.UUID{Bytes: brandKitID, Valid: true},
+		Kind:       kind,
+		Filename:   filename,
+		Sha256:     hash,
+		SizeBytes:  int32(len(data)),
+		FontFamily: fontFamily,
+		Licensed:   licensed,
+	})
+	return err
+}
+
+// deleteAsset removes a brand kit's font, logo or background image record; the file stays in S3 for the same reasons as in DeleteBrandKit
+func (s *BrandKitService) deleteAsset(ctx context.Context, userID, brandKitID uuid.UUID, kind string) (*BrandKit, error) {
+	kit, err := s.repo.GetBrandKitByID(ctx, brandKitID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if kit == nil {
+		return nil, ErrBrandKitNotFound
+	}
+
+	deleted, err := s.repo.DeleteBrandKitAsset(ctx, kit.ID, kind)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, fmt.Errorf("%w: brand kit has no %s", ErrBrandKitAssetNotFound, kind)
+	}
+	return s.withAssets(ctx, kit)
+}
+
+// withAssets looks up a brand kit's font, logo and background image
+func (s *BrandKitService) withAssets(ctx context.Context, kit *db.BrandKit) (*BrandKit, error) {
+	assets, err := s.repo.GetBrandKitAssetsByBrandKitIDs(ctx, []uuid.UUID{kit.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	brandKit := &BrandKit{Kit: kit}
+	for _, asset := range assets {
+		brandKit.setAsset(asset)
+	}
+	return brandKit, nil
+}
+
+// setAsset attaches a font, logo or background image to the brand kit
+func (b *BrandKit) setAsset(asset *db.BrandKitAsset) {
+	switch asset.Kind {
+	case brandKitFontKind:
+		b.Font = asset
+	case brandKitLogoKind:
+		b.Logo = asset
+	case brandKitBackgroundKind:
+		b.Background = asset
+	}
+}
+
+// validateBrandKitRequest checks a brand kit's name and colours, returning the trimmed name
+func validateBrandKitRequest(req *api.BrandKitRequest) (string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidBrandKit)
+	}
+	if utf8.RuneCountInString(name) > maxBrandKitNameLength {
+		return "", fmt.Errorf("%w: name must be at most %d characters", ErrInvalidBrandKit, maxBrandKitNameLength)
+	}
+
+	colors := []struct {
+		field string
+		value *string
+	}{
+		{"font_color", req.FontColor},
+		{"stroke_color", req.StrokeColor},
+		{"background_color", req.BackgroundColor},
+		{"highlight_color", req.HighlightColor},
+	}
+	for _, color := range colors {
+		if color.value != nil && !hexColorPattern.MatchString(*color.value) {
+			return "", fmt.Errorf("%w: %s must be #RRGGBB or #RRGGBBAA", ErrInvalidBrandKit, color.field)
+		}
+	}
+	return name, nil
+}
+
+// brandKitAssetKey is the S3 key of a brand kit font, logo or background image
+func brandKitAssetKey(kind, filename string) string {
+	return fmt.Sprintf("brand-kits/%ss/%s", kind, filename)
+}
+
+// overlayFont loads the font overlay text is drawn in: a brand kit font, fetched from S3 into workDir,
+// or one from the font library
+func (s *AIAvatarService) overlayFont(ctx context.Context, style *overlayStyle, workDir string) (*textlayout.Font, error) {
+	if style.BrandFont == nil {
+		return s.fonts.Font(style.FontFamily)
+	}
+
+	path := filepath.Join(workDir, "brand-font"+filepath.Ext(style.BrandFont.Filename))
+	if err := s.downloadFile(ctx, brandKitAssetKey(brandKitFontKind, style.BrandFont.Filename), path); err != nil {
+		return nil, fmt.Errorf("failed to download brand kit font: %w", err)
+	}
+	return s.fonts.LoadFont(path)
+}
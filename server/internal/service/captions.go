@@ -0,0 +1,265 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/ethanhosier/reel-farm/internal/textlayout"
+)
+
+const (
+	defaultHighlightColor = "#FFE14D"
+	defaultWordsPerSecond = 2.5
+
+	// phrasePunctuation ends a phrase when it is the last character of a word
+	phrasePunctuation = ",.!?;:"
+)
+
+// ErrInvalidCaptionOptions is returned when requested caption options fail validation
+var ErrInvalidCaptionOptions = errors.New("invalid caption options")
+
+// captionOptions is a validated api.CaptionOptions with defaults applied
+type captionOptions struct {
+	Animated       bool
+	Unit           api.CaptionOptionsUnit
+	Effect         api.CaptionOptionsEffect
+	HighlightColor string
+	WordsPerSecond float64
+	Timings        []float64
+}
+
+// captionStep is one step of an animated caption: a word or phrase and when it is current
+type captionStep struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// resolveCaptionOptions validates requested caption options for the given overlay text and fills in defaults
+func resolveCaptionOptions(opts *api.CaptionOptions, text string) (*captionOptions, error) {
+	resolved := &captionOptions{
+		Unit:           api.Word,
+		Effect:         api.Highlight,
+		HighlightColor: defaultHighlightColor,
+		WordsPerSecond: defaultWordsPerSecond,
+	}
+	if opts == nil {
+		return resolved, nil
+	}
+
+	if opts.Mode != nil {
+		switch *opts.Mode {
+		case api.Static:
+		case api.Animated:
+			resolved.Animated = true
+		default:
+			return nil, fmt.Errorf("%w: mode must be static or animated", ErrInvalidCaptionOptions)
+		}
+	}
+
+	if opts.Unit != nil {
+		switch *opts.Unit {
+		case api.Word, api.Phrase:
+			resolved.Unit = *opts.Unit
+		default:
+			return nil, fmt.Errorf("%w: unit must be word or phrase", ErrInvalidCaptionOptions)
+		}
+	}
+
+	if opts.Effect != nil {
+		switch *opts.Effect {
+		case api.Highlight, api.Reveal:
+			resolved.Effect = *opts.Effect
+		default:
+			return nil, fmt.Errorf("%w: effect must be highlight or reveal", ErrInvalidCaptionOptions)
+		}
+	}
+
+	if opts.HighlightColor != nil {
+		if !hexColorPattern.MatchString(*opts.HighlightColor) {
+			return nil, fmt.Errorf("%w: highlight_color must be #RRGGBB or #RRGGBBAA", ErrInvalidCaptionOptions)
+		}
+		resolved.HighlightColor = *opts.HighlightColor
+	}
+
+	if opts.WordsPerSecond != nil {
+		if *opts.WordsPerSecond < 0.5 || *opts.WordsPerSecond > 10 {
+			return nil, fmt.Errorf("%w: words_per_second must be between 0.5 and 10", ErrInvalidCaptionOptions)
+		}
+		resolved.WordsPerSecond = *opts.WordsPerSecond
+	}
+
+	if opts.Timings != nil {
+		_, stepWords := captionSteps(text, resolved.Unit)
+		if len(*opts.Timings) != len(stepWords) {
+			return nil, fmt.Errorf("%w: timings must have %d entries, one per %s", ErrInvalidCaptionOptions, len(stepWords), resolved.Unit)
+		}
+		for i, start := range *opts.Timings {
+			if start < 0 || (i > 0 && start < (*opts.Timings)[i-1]) {
+				return nil, fmt.Errorf("%w: timings must be non-negative and in increasing order", ErrInvalidCaptionOptions)
+			}
+		}
+		resolved.Timings = *opts.Timings
+	}
+
+	return resolved, nil
+}
+
+// captionWords splits text into words the same way the text layout does: by line, then on whitespace
+func captionWords(text string) (words []string, paragraphEnds []bool) {
+	for _, paragraph := range strings.Split(text, "\n") {
+		fields := strings.Fields(paragraph)
+		for i, word := range fields {
+			words = append(words, word)
+			paragraphEnds = append(paragraphEnds, i == len(fields)-1)
+		}
+	}
+	return words, paragraphEnds
+}
+
+// captionSteps groups the words of text into animation steps. It returns the step index of each
+// word and the number of words in each step.
+func captionSteps(text string, unit api.CaptionOptionsUnit) (wordSteps []int, stepWords []int) {
+	words, paragraphEnds := captionWords(text)
+
+	step := 0
+	for i, word := range words {
+		wordSteps = append(wordSteps, step)
+		if len(stepWords) == step {
+			stepWords = append(stepWords, 0)
+		}
+		stepWords[step]++
+
+		endsStep := unit == api.Word || paragraphEnds[i] || strings.ContainsAny(word[len(word)-1:], phrasePunctuation)
+		if endsStep {
+			step++
+		}
+	}
+	return wordSteps, stepWords
+}
+
+// stepTimes works out when each step is current, from explicit timings or the words-per-second pace.
+// Steps starting after the end of the video are dropped.
+func (c *captionOptions) stepTimes(stepWords []int, duration time.Duration) []captionStep {
+	seconds := func(s float64) time.Duration {
+		return time.Duration(s * float64(time.Second))
+	}
+
+	steps := make([]captionStep, 0, len(stepWords))
+	var elapsed float64
+	for i, words := range stepWords {
+		var step captionStep
+		if c.Timings != nil {
+			step.Start = seconds(c.Timings[i])
+			step.End = duration
+			if i+1 < len(c.Timings) {
+				step.End = seconds(c.Timings[i+1])
+			}
+		} else {
+			step.Start = seconds(elapsed)
+			elapsed += float64(words) / c.WordsPerSecond
+			step.End = seconds(elapsed)
+		}
+
+		if step.Start >= duration {
+			break
+		}
+		step.End = min(step.End, duration)
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// overlayFrame is how the burned-in text looks from Start until the next frame starts. A frame
+// with no lines shows nothing.
+type overlayFrame struct {
+	Start time.Duration
+	Lines []textlayout.Line
+}
+
+// staticOverlayFrames shows the laid-out lines for the whole video
+func staticOverlayFrames(lines []string) []overlayFrame {
+	frame := overlayFrame{}
+	for _, line := range lines {
+		frame.Lines = append(frame.Lines, textlayout.Line{{Text: line}})
+	}
+	return []overlayFrame{frame}
+}
+
+// captionFrames animates the laid-out lines step by step, ending on the full text once every step
+// has been shown
+func captionFrames(text string, lines []string, captions *captionOptions, duration time.Duration) []overlayFrame {
+	wordSteps, stepWords := captionSteps(text, captions.Unit)
+	words, _ := captionWords(text)
+
+	// Map each piece of each laid-out line back to its word; the layout may split long words
+	type piece struct {
+		text string
+		step int
+	}
+	var laidOut [][]piece
+	wordIndex, offset := 0, 0
+	for _, line := range lines {
+		var pieces []piece
+		for _, field := range strings.Fields(line) {
+			if wordIndex >= len(words) {
+				break
+			}
+			pieces = append(pieces, piece{text: field, step: wordSteps[wordIndex]})
+			offset += len(field)
+			if offset >= len(words[wordIndex]) {
+				wordIndex++
+				offset = 0
+			}
+		}
+		laidOut = append(laidOut, pieces)
+	}
+
+	highlight := parseHexColor(captions.HighlightColor)
+
+	// render draws the caption with step current; -1 draws the full text with nothing highlighted
+	render := func(current int) []textlayout.Line {
+		rendered := make([]textlayout.Line, 0, len(laidOut))
+		for _, pieces := range laidOut {
+			var line textlayout.Line
+			for i, p := range pieces {
+				if i > 0 {
+					line = append(line, textlayout.Span{Text: " "})
+				}
+				span := textlayout.Span{Text: p.text}
+				switch {
+				case current < 0:
+				case captions.Effect == api.Highlight && p.step == current:
+					span.Color = highlight
+				case captions.Effect == api.Reveal && p.step > current:
+					span.Hidden = true
+				}
+				line = append(line, span)
+			}
+			rendered = append(rendered, line)
+		}
+		return rendered
+	}
+
+	var frames []overlayFrame
+	steps := captions.stepTimes(stepWords, duration)
+	var last time.Duration
+	for i, step := range steps {
+		if step.End <= step.Start {
+			continue
+		}
+		// Nothing is shown before the first step, which explicit timings may start late
+		if len(frames) == 0 && step.Start > 0 {
+			frames = append(frames, overlayFrame{})
+		}
+		frames = append(frames, overlayFrame{Start: step.Start, Lines: render(i)})
+		last = step.End
+	}
+	if last < duration {
+		frames = append(frames, overlayFrame{Start: last, Lines: render(-1)})
+	}
+
+	return frames
+}
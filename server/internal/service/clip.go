@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/ethanhosier/reel-farm/internal/ffmpeg"
+)
+
+const (
+	minClipLength   = time.Second
+	minClipDuration = time.Second
+	maxClipDuration = 3 * time.Minute
+	// maxClipLoops caps how many times a clip may repeat to reach the requested duration
+	maxClipLoops = 20
+	// clipCrossfade is how long each loop of a clip fades into the next; very short clips use a
+	// quarter of their length instead
+	clipCrossfade = 500 * time.Millisecond
+)
+
+// ErrInvalidClipOptions is returned when requested clip options fail validation
+var ErrInvalidClipOptions = errors.New("invalid clip options")
+
+// clipOptions is a validated api.ClipOptions: the part of the source to use and how long the render is
+type clipOptions struct {
+	Start    time.Duration `json:"start"`
+	End      time.Duration `json:"end"`      // 0 for the end of the source
+	Duration time.Duration `json:"duration"` // 0 for the length of the clip
+}
+
+// resolveClipOptions validates requested clip options. It returns nil when they leave the source as
+// it is, so the render uses the whole source without an extra encode.
+func resolveClipOptions(opts *api.ClipOptions) (*clipOptions, error) {
+	if opts == nil {
+		return nil, nil
+	}
+
+	seconds := func(s float64) time.Duration {
+		return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+	}
+
+	resolved := &clipOptions{}
+	if opts.StartSeconds != nil {
+		if *opts.StartSeconds < 0 {
+			return nil, fmt.Errorf("%w: start_seconds must not be negative", ErrInvalidClipOptions)
+		}
+		resolved.Start = seconds(*opts.StartSeconds)
+	}
+
+	if opts.EndSeconds != nil {
+		resolved.End = seconds(*opts.EndSeconds)
+		if resolved.End-resolved.Start < minClipLength {
+			return nil, fmt.Errorf("%w: end_seconds must be at least %s after start_seconds", ErrInvalidClipOptions, minClipLength)
+		}
+	}
+
+	if opts.DurationSeconds != nil {
+		resolved.Duration = seconds(*opts.DurationSeconds)
+		if resolved.Duration < minClipDuration || resolved.Duration > maxClipDuration {
+			return nil, fmt.Errorf("%w: duration_seconds must be between %.0f and %.0f", ErrInvalidClipOptions, minClipDuration.Seconds(), maxClipDuration.Seconds())
+		}
+	}
+
+	if *resolved == (clipOptions{}) {
+		return nil, nil
+	}
+	return resolved, nil
+}
+
+// bounds returns the clip's start and end in a source of the given duration, checking that the clip
+// fits in the source and can be looped to the requested duration
+func (c *clipOptions) bounds(sourceDuration time.Duration) (start, end time.Duration, err error) {
+	end = c.End
+	if end == 0 {
+		end = sourceDuration
+	}
+
+	switch {
+	case c.Start >= sourceDuration:
+		return 0, 0, fmt.Errorf("%w: start_seconds must be before the end of the %.1f second video", ErrInvalidClipOptions, sourceDuration.Seconds())
+	case end > sourceDuration:
+		return 0, 0, fmt.Errorf("%w: end_seconds must not be after the end of the %.1f second video", ErrInvalidClipOptions, sourceDuration.Seconds())
+	case end-c.Start < minClipLength:
+		return 0, 0, fmt.Errorf("%w: the clip must be at least %s long", ErrInvalidClipOptions, minClipLength)
+	case c.Duration > (end-c.Start)*maxClipLoops:
+		return 0, 0, fmt.Errorf("%w: duration_seconds may be at most %d times the length of the clip", ErrInvalidClipOptions, maxClipLoops)
+	}
+	return c.Start, end, nil
+}
+
+// ValidateClip checks that the clip requested in the render options fits the source video. Sources
+// that have not been probed yet are checked when they are rendered instead.
+func (s *AIAvatarService) ValidateClip(options *RenderOptions, source *db.AiAvatarVideo) error {
+	clip, err := resolveClipOptions(options.Clip)
+	if err != nil || clip == nil {
+		return err
+	}
+
+	metadata := sourceMetadataFromVideo(source)
+	if metadata == nil {
+		return nil
+	}
+	_, _, err = clip.bounds(metadata.Duration)
+	return err
+}
+
+// renderClip cuts the clip out of the source video and trims or loops it to the requested duration,
+// crossfading the picture and sound where each loop joins the next. It returns the clip's videoInfo.
+func renderClip(ctx context.Context, inputPath string, info *videoInfo, clip *clipOptions, outputPath string, onProgress ProgressFunc) (*videoInfo, error) {
+	start, end, err := clip.bounds(info.Duration)
+	if err != nil {
+		return nil, err
+	}
+	length := end - start
+	duration := clip.Duration
+	if duration == 0 {
+		duration = length
+	}
+
+	clipInfo := *info
+	clipInfo.Duration = duration
+
+	cmd := ffmpeg.New()
+	if duration <= length {
+		cmd.Input(inputPath, "-ss", ffmpeg.FormatTimestamp(start), "-t", ffmpeg.FormatTimestamp(duration))
+		if info.Width%2 != 0 || info.Height%2 != 0 {
+			cmd.VideoFilter(ffmpeg.Chain{ffmpeg.EvenDimensions()})
+		}
+	} else {
+		cmd.Input(inputPath, "-ss", ffmpeg.FormatTimestamp(start), "-t", ffmpeg.FormatTimestamp(length))
+
+		// Each loop after the first overlaps the one before it by the crossfade
+		crossfade := min(clipCrossfade, length/4)
+		loops := int(math.Ceil(float64(duration-crossfade) / float64(length-crossfade)))
+
+		graph := &ffmpeg.Graph{}
+		loopVideo := func(i int) string { return fmt.Sprintf("loop%d", i) }
+		loopAudio := func(i int) string { return fmt.Sprintf("loopa%d", i) }
+
+		videoChain := ffmpeg.Chain{}
+		if info.Width%2 != 0 || info.Height%2 != 0 {
+			videoChain = append(videoChain, ffmpeg.EvenDimensions())
+		}
+		videoChain = append(videoChain, ffmpeg.Split(loops))
+		graph.Add([]string{"0:v"}, videoChain, labels(loops, loopVideo))
+		if info.HasAudio {
+			graph.Add([]string{"0:a"}, ffmpeg.Chain{ffmpeg.ASplit(loops)}, labels(loops, loopAudio))
+		}
+
+		video, audio := loopVideo(0), loopAudio(0)
+		for i := 1; i < loops; i++ {
+			offset := time.Duration(i) * (length - crossfade)
+			graph.Add([]string{video, loopVideo(i)}, ffmpeg.Chain{ffmpeg.XFade(crossfade, offset)}, []string{fmt.Sprintf("joined%d", i)})
+			video = fmt.Sprintf("joined%d", i)
+			if info.HasAudio {
+				graph.Add([]string{audio, loopAudio(i)}, ffmpeg.Chain{ffmpeg.ACrossfade(crossfade)}, []string{fmt.Sprintf("joineda%d", i)})
+				audio = fmt.Sprintf("joineda%d", i)
+			}
+		}
+
+		graph.Add([]string{video}, ffmpeg.Chain{ffmpeg.Trim(duration)}, []string{"v"})
+		cmd.Map("[v]")
+		if info.HasAudio {
+			graph.Add([]string{audio}, ffmpeg.Chain{ffmpeg.ATrim(duration)}, []string{"a"})
+			cmd.Map("[a]")
+		}
+		cmd.FilterComplex(graph)
+	}
+
+	// The clip is encoded again with the overlay, so keep it close to lossless
+	cmd.VideoCodec("libx264")
+	cmd.Preset("veryfast")
+	cmd.CRF(18)
+	if info.HasAudio {
+		cmd.AudioCodec("aac").AudioBitrate("192k")
+	}
+	cmd.Threads(1)
+	cmd.Output(outputPath)
+
+	log.Printf("✂️ Cutting %.1fs clip from %.1fs to %.1fs", duration.Seconds(), start.Seconds(), end.Seconds())
+	startedAt := time.Now()
+	err = cmd.Run(ctx, func(p ffmpeg.Progress) {
+		if onProgress == nil {
+			return
+		}
+		onProgress(calculateRenderProgress(p.OutTime, duration, time.Since(startedAt)))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cut clip: %w", err)
+	}
+
+	clipInfo.Width = info.Width &^ 1
+	clipInfo.Height = info.Height &^ 1
+	return &clipInfo, nil
+}
+
+// labels returns the pad labels name(0) to name(count-1)
+func labels(count int, name func(int) string) []string {
+	labels := make([]string, count)
+	for i := range labels {
+		labels[i] = name(i)
+	}
+	return labels
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/ethanhosier/reel-farm/internal/ffmpeg"
+	"github.com/ethanhosier/reel-farm/internal/textlayout"
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	defaultEndCardDuration   = 3 * time.Second
+	minEndCardDuration       = time.Second
+	maxEndCardDuration       = 10 * time.Second
+	defaultEndCardBackground = "#000000"
+	maxEndCardHeadlineLength = 80
+	maxEndCardSubtitleLength = 120
+
+	// endCardTransition is how long the end of the clip crossfades into the card; very short clips
+	// use a quarter of their length instead
+	endCardTransition = 500 * time.Millisecond
+	// endCardFrameRate is the frame rate of the card when the source's is not known
+	endCardFrameRate = 30
+
+	// The logo fits in this share of the frame, and is never scaled up
+	endCardLogoWidthRatio  = 0.5
+	endCardLogoHeightRatio = 0.2
+	// endCardGapRatio is the share of the frame height between the logo, headline and subtitle
+	endCardGapRatio = 0.03
+)
+
+// ErrInvalidEndCard is returned when a requested end card fails validation
+var ErrInvalidEndCard = errors.New("invalid end card")
+
+// solidHexColorPattern matches an opaque #RRGGBB colour; the card has nothing behind it to show through
+var solidHexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// endCard is a validated api.EndCard, with the brand kit images it uses
+type endCard struct {
+	Duration        time.Duration  `json:"duration"`
+	BackgroundColor string         `json:"background_color"`
+	BackgroundImage *brandKitImage `json:"background_image,omitempty"`
+	Headline        string         `json:"headline,omitempty"`
+	Subtitle        string         `json:"subtitle,omitempty"`
+	Logo            *brandKitImage `json:"logo,omitempty"`
+}
+
+// resolveEndCard validates a requested end card, taking its logo and background image from the
+// render's brand kit. It returns nil if no end card was requested.
+func resolveEndCard(opts *api.EndCard, kit *brandKitStyle) (*endCard, error) {
+	if opts == nil {
+		return nil, nil
+	}
+
+	card := &endCard{
+		Duration:        defaultEndCardDuration,
+		BackgroundColor: defaultEndCardBackground,
+	}
+
+	if opts.DurationSeconds != nil {
+		card.Duration = time.Duration(*opts.DurationSeconds * float64(time.Second)).Round(time.Millisecond)
+		if card.Duration < minEndCardDuration || card.Duration > maxEndCardDuration {
+			return nil, fmt.Errorf("%w: duration_seconds must be between %.0f and %.0f", ErrInvalidEndCard, minEndCardDuration.Seconds(), maxEndCardDuration.Seconds())
+		}
+	}
+
+	if opts.BackgroundColor != nil {
+		if !solidHexColorPattern.MatchString(*opts.BackgroundColor) {
+			return nil, fmt.Errorf("%w: background_color must be #RRGGBB", ErrInvalidEndCard)
+		}
+		card.BackgroundColor = strings.ToUpper(*opts.BackgroundColor)
+	}
+
+	if opts.ShowBackgroundImage != nil && *opts.ShowBackgroundImage {
+		if kit == nil || kit.Background == nil {
+			return nil, fmt.Errorf("%w: show_background_image needs a brand kit with a background image", ErrInvalidEndCard)
+		}
+		card.BackgroundImage = kit.Background
+	}
+
+	if opts.ShowLogo != nil && *opts.ShowLogo {
+		if kit == nil || kit.Logo == nil {
+			return nil, fmt.Errorf("%w: show_logo needs a brand kit with a logo", ErrInvalidEndCard)
+		}
+		card.Logo = kit.Logo
+	}
+
+	if opts.Headline != nil {
+		card.Headline = strings.TrimSpace(*opts.Headline)
+		if utf8.RuneCountInString(card.Headline) > maxEndCardHeadlineLength {
+			return nil, fmt.Errorf("%w: headline must be at most %d characters", ErrInvalidEndCard, maxEndCardHeadlineLength)
+		}
+	}
+	if opts.Subtitle != nil {
+		card.Subtitle = strings.TrimSpace(*opts.Subtitle)
+		if utf8.RuneCountInString(card.Subtitle) > maxEndCardSubtitleLength {
+			return nil, fmt.Errorf("%w: subtitle must be at most %d characters", ErrInvalidEndCard, maxEndCardSubtitleLength)
+		}
+	}
+
+	if !card.hasText() && card.Logo == nil && card.BackgroundImage == nil {
+		return nil, fmt.Errorf("%w: an end card needs a headline, subtitle, logo or background image", ErrInvalidEndCard)
+	}
+	return card, nil
+}
+
+// hasText reports whether the card has a headline or subtitle to draw
+func (c *endCard) hasText() bool {
+	return c.Headline != "" || c.Subtitle != ""
+}
+
+// transition is how long a clip of the given duration crossfades into the card
+func (c *endCard) transition(clipDuration time.Duration) time.Duration {
+	return min(endCardTransition, clipDuration/4)
+}
+
+// draw composes the card as a width x height image: the background colour or image, then the logo,
+// headline and subtitle stacked in the middle. The text is drawn in font with the overlay style's
+// colours and outline; font may be nil if the card has no text.
+func (c *endCard) draw(font *textlayout.Font, style *overlayStyle, background, logo image.Image, width, height int) (*image.RGBA, error) {
+	card := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(card, card.Bounds(), image.NewUniform(parseHexColor(c.BackgroundColor)), image.Point{}, draw.Src)
+
+	// The background image covers the card, cropped to its aspect ratio around the centre
+	if background != nil {
+		bounds := background.Bounds()
+		scale := max(float64(width)/float64(bounds.Dx()), float64(height)/float64(bounds.Dy()))
+		scaledWidth := int(math.Ceil(float64(bounds.Dx()) * scale))
+		scaledHeight := int(math.Ceil(float64(bounds.Dy()) * scale))
+		x, y := (width-scaledWidth)/2, (height-scaledHeight)/2
+		xdraw.CatmullRom.Scale(card, image.Rect(x, y, x+scaledWidth, y+scaledHeight), background, bounds, xdraw.Over, nil)
+	}
+
+	var elements []*image.RGBA
+	if logo != nil {
+		elements = append(elements, scaleToFit(logo, int(float64(width)*endCardLogoWidthRatio), int(float64(height)*endCardLogoHeightRatio)))
+	}
+
+	// The headline and subtitle are sized to the frame rather than the overlay's font size, and
+	// never have the overlay's background box
+	texts := []struct {
+		text     string
+		fontSize int
+	}{
+		{c.Headline, width / 12},
+		{c.Subtitle, width / 20},
+	}
+	for _, t := range texts {
+		if t.text == "" {
+			continue
+		}
+
+		textStyle := *style
+		textStyle.FontSize = max(t.fontSize, 12)
+		textStyle.BackgroundColor = ""
+		layout, err := font.Layout(t.text, textlayout.Options{
+			FontSize:    textStyle.FontSize,
+			MinFontSize: textStyle.minFontSize(),
+			MaxWidth:    textStyle.maxTextWidth(width),
+			MaxLines:    3,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to lay out end card text: %w", err)
+		}
+		textStyle.FontSize = layout.FontSize
+
+		lines := make([]textlayout.Line, len(layout.Lines))
+		for i, line := range layout.Lines {
+			lines[i] = textlayout.Line{{Text: line}}
+		}
+		opts := textStyle.renderOptions()
+		opts.Align = textlayout.AlignCenter
+		img, err := font.Render(lines, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to draw end card text: %w", err)
+		}
+		elements = append(elements, img)
+	}
+
+	// Stack the elements in the middle of the card
+	gap := int(float64(height) * endCardGapRatio)
+	total := gap * max(len(elements)-1, 0)
+	for _, element := range elements {
+		total += element.Bounds().Dy()
+	}
+	y := (height - total) / 2
+	for _, element := range elements {
+		bounds := element.Bounds()
+		x := (width - bounds.Dx()) / 2
+		draw.Draw(card, image.Rect(x, y, x+bounds.Dx(), y+bounds.Dy()), element, bounds.Min, draw.Over)
+		y += bounds.Dy() + gap
+	}
+
+	return card, nil
+}
+
+// scaleToFit scales img down to fit in maxWidth x maxHeight, keeping its aspect ratio; smaller images are left at their size
+func scaleToFit(img image.Image, maxWidth, maxHeight int) *image.RGBA {
+	bounds := img.Bounds()
+	scale := min(1, float64(maxWidth)/float64(bounds.Dx()), float64(maxHeight)/float64(bounds.Dy()))
+	width := max(int(math.Round(float64(bounds.Dx())*scale)), 1)
+	height := max(int(math.Round(float64(bounds.Dy())*scale)), 1)
+
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, xdraw.Src, nil)
+	return scaled
+}
+
+// loadEndCardImage fetches a brand kit logo or background image from S3 into workDir and decodes it
+func (s *AIAvatarService) loadEndCardImage(ctx context.Context, kind string, asset *brandKitImage, workDir string) (image.Image, error) {
+	path := filepath.Join(workDir, "end-card-"+kind+filepath.Ext(asset.Filename))
+	if err := s.downloadFile(ctx, brandKitAssetKey(kind, asset.Filename), path); err != nil {
+		return nil, fmt.Errorf("failed to download brand kit %s: %w", kind, err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open brand kit %s: %w", kind, err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode brand kit %s: %w", kind, err)
+	}
+	return img, nil
+}
+
+// renderEndCard draws the end card and appends it to the video, crossfading into it from the end of
+// the clip. The card is encoded at the video's frame size and frame rate, and the video's audio fades
+// out under the transition and is padded with silence to the new length, keeping its channel layout.
+// It returns the videoInfo of the video with the card.
+func (s *AIAvatarService) renderEndCard(ctx context.Context, inputPath string, info *videoInfo, card *endCard, font *textlayout.Font, style *overlayStyle, workDir, outputPath string, onProgress ProgressFunc) (*videoInfo, error) {
+	var background, logo image.Image
+	var err error
+	if card.BackgroundImage != nil {
+		if background, err = s.loadEndCardImage(ctx, brandKitBackgroundKind, card.BackgroundImage, workDir); err != nil {
+			return nil, err
+		}
+	}
+	if card.Logo != nil {
+		if logo, err = s.loadEndCardImage(ctx, brandKitLogoKind, card.Logo, workDir); err != nil {
+			return nil, err
+		}
+	}
+
+	// Odd source dimensions are rounded down to even ones so the output can be encoded as yuv420p
+	width, height := info.Width&^1, info.Height&^1
+	img, err := card.draw(font, style, background, logo, width, height)
+	if err != nil {
+		return nil, err
+	}
+	cardPath := filepath.Join(workDir, "end_card.png")
+	if err := writePNG(cardPath, img); err != nil {
+		return nil, err
+	}
+
+	frameRate := int(math.Round(info.FrameRate))
+	if frameRate <= 0 {
+		frameRate = endCardFrameRate
+	}
+	transition := card.transition(info.Duration)
+	duration := info.Duration + card.Duration
+
+	// The card starts as the clip begins fading into it, so it is shown for the transition as well
+	cmd := ffmpeg.New().
+		Input(inputPath).
+		Input(cardPath, "-loop", "1", "-framerate", fmt.Sprint(frameRate), "-t", ffmpeg.FormatTimestamp(card.Duration+transition))
+
+	// xfade needs both inputs at the same size, frame rate and pixel format
+	graph := &ffmpeg.Graph{}
+	clipChain := ffmpeg.Chain{}
+	if info.Width%2 != 0 || info.Height%2 != 0 {
+		clipChain = append(clipChain, ffmpeg.EvenDimensions())
+	}
+	clipChain = append(clipChain, ffmpeg.SetSAR(1), ffmpeg.FPS(frameRate), ffmpeg.ToPixelFormat("yuv420p"))
+	graph.Add([]string{"0:v"}, clipChain, []string{"clip"})
+	graph.Add([]string{"1:v"}, ffmpeg.Chain{ffmpeg.SetSAR(1), ffmpeg.FPS(frameRate), ffmpeg.ToPixelFormat("yuv420p")}, []string{"card"})
+	graph.Add([]string{"clip", "card"}, ffmpeg.Chain{ffmpeg.XFade(transition, info.Duration-transition)}, []string{"v"})
+	cmd.Map("[v]")
+	if info.HasAudio {
+		graph.Add([]string{"0:a"}, ffmpeg.Chain{
+			ffmpeg.AFadeOut(info.Duration-transition, transition),
+			ffmpeg.APad(duration),
+			ffmpeg.ATrim(duration),
+		}, []string{"a"})
+		cmd.Map("[a]")
+	}
+	cmd.FilterComplex(graph)
+
+	// The video is encoded again with the overlay, so keep it close to lossless
+	cmd.VideoCodec("libx264")
+	cmd.Preset("veryfast")
+	cmd.CRF(18)
+	if info.HasAudio {
+		cmd.AudioCodec("aac").AudioBitrate("192k")
+	}
+	cmd.Threads(1)
+	cmd.Output(outputPath)
+
+	log.Printf("🪧 Adding %.1fs end card", card.Duration.Seconds())
+	startedAt := time.Now()
+	err = cmd.Run(ctx, func(p ffmpeg.Progress) {
+		if onProgress == nil {
+			return
+		}
+		onProgress(calculateRenderProgress(p.OutTime, duration, time.Since(startedAt)))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add end card: %w", err)
+	}
+
+	cardInfo := *info
+	cardInfo.Duration = duration
+	cardInfo.Width = width
+	cardInfo.Height = height
+	return &cardInfo, nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethanhosier/reel-farm/internal/textlayout"
+	"github.com/go-text/typesetting/language"
+)
+
+const defaultFontFamily = "tiktok-display"
+
+// fontCatalog maps font family names to candidate font files; the first one that exists is used
+var fontCatalog = map[string][]string{
+	"tiktok-display": {"./TikTokDisplay-Medium.ttf"},
+	"dejavu-sans": {
+		"/usr/share/fonts/dejavu/DejaVuSans-Bold.ttf",
+		"/usr/share/fonts/truetype/dejavu/DejaVuSans-Bold.ttf",
+	},
+	"liberation-sans": {
+		"/usr/share/fonts/liberation/LiberationSans-Bold.ttf",
+		"/usr/share/fonts/truetype/liberation/LiberationSans-Bold.ttf",
+	},
+	"liberation-serif": {
+		"/usr/share/fonts/liberation/LiberationSerif-Bold.ttf",
+		"/usr/share/fonts/truetype/liberation/LiberationSerif-Bold.ttf",
+	},
+}
+
+// emojiFontCandidates are colour emoji fonts for the characters overlay fonts have no glyph for;
+// the first one that exists is used
+var emojiFontCandidates = []string{
+	"/usr/share/fonts/noto/NotoColorEmoji.ttf",
+	"/usr/share/fonts/truetype/noto/NotoColorEmoji.ttf",
+}
+
+// scriptFont is a font overlay text falls back to for scripts the overlay font has no glyphs for
+type scriptFont struct {
+	name       string
+	scripts    []language.Script
+	candidates []string // The first one that exists is used
+	path       string   // The candidate in use, once found installed
+}
+
+// scriptFontCatalog lists the Noto fonts used for scripts the overlay fonts do not cover
+var scriptFontCatalog = []scriptFont{
+	{
+		name:    "Noto Sans Arabic",
+		scripts: []language.Script{language.Arabic},
+		candidates: []string{
+			"/usr/share/fonts/noto/NotoSansArabic-Bold.ttf",
+			"/usr/share/fonts/truetype/noto/NotoSansArabic-Bold.ttf",
+		},
+	},
+	{
+		name:    "Noto Sans Hebrew",
+		scripts: []language.Script{language.Hebrew},
+		candidates: []string{
+			"/usr/share/fonts/noto/NotoSansHebrew-Bold.ttf",
+			"/usr/share/fonts/truetype/noto/NotoSansHebrew-Bold.ttf",
+		},
+	},
+	{
+		name:    "Noto Sans Devanagari",
+		scripts: []language.Script{language.Devanagari},
+		candidates: []string{
+			"/usr/share/fonts/noto/NotoSansDevanagari-Bold.ttf",
+			"/usr/share/fonts/truetype/noto/NotoSansDevanagari-Bold.ttf",
+		},
+	},
+	{
+		name:    "Noto Sans CJK",
+		scripts: []language.Script{language.Han, language.Hiragana, language.Katakana, language.Hangul, language.Bopomofo},
+		candidates: []string{
+			"/usr/share/fonts/noto/NotoSansCJK-Bold.ttc",
+			"/usr/share/fonts/opentype/noto/NotoSansCJK-Bold.ttc",
+		},
+	},
+}
+
+// FontLibrary resolves overlay font families to font files installed on this server
+type FontLibrary struct {
+	fonts       map[string]string
+	emojiPath   string       // Empty if no emoji font is installed
+	scriptFonts []scriptFont // The installed script fonts
+
+	mu        sync.Mutex
+	parsed    map[string]*textlayout.Font
+	fallbacks map[string]*textlayout.Font // Emoji and script fonts by path, shared by every family
+}
+
+// NewFontLibrary creates a font library containing the catalog fonts that are present on disk
+func NewFontLibrary() *FontLibrary {
+	fonts := make(map[string]string)
+	for family, candidates := range fontCatalog {
+		if path := firstExisting(candidates); path != "" {
+			fonts[family] = path
+		}
+	}
+
+	emojiPath := firstExisting(emojiFontCandidates)
+	if emojiPath == "" {
+		log.Printf("⚠️ No colour emoji font installed; emoji in overlays will not be drawn")
+	}
+
+	var scriptFonts []scriptFont
+	var missing []string
+	for _, font := range scriptFontCatalog {
+		if font.path = firstExisting(font.candidates); font.path == "" {
+			missing = append(missing, font.name)
+			continue
+		}
+		scriptFonts = append(scriptFonts, font)
+	}
+	if len(missing) > 0 {
+		log.Printf("⚠️ Script fonts not installed, overlays may not draw these scripts: %s", strings.Join(missing, ", "))
+	}
+
+	return &FontLibrary{
+		fonts:       fonts,
+		emojiPath:   emojiPath,
+		scriptFonts: scriptFonts,
+		parsed:      make(map[string]*textlayout.Font),
+		fallbacks:   make(map[string]*textlayout.Font),
+	}
+}
+
+// firstExisting returns the first of paths that exists, or "" if none do
+func firstExisting(paths []string) string {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// Path returns the font file for a family, if it is installed
+func (l *FontLibrary) Path(family string) (string, bool) {
+	path, ok := l.fonts[family]
+	return path, ok
+}
+
+// Families returns the installed font families in alphabetical order
+func (l *FontLibrary) Families() []string {
+	families := make([]string, 0, len(l.fonts))
+	for family := range l.fonts {
+		families = append(families, family)
+	}
+	sort.Strings(families)
+	return families
+}
+
+// Font returns the parsed font for a family so text can be measured and drawn, loading it on first
+// use. Characters the font has no glyph for fall back to the Noto font for their script, or to the
+// colour emoji font.
+func (l *FontLibrary) Font(family string) (*textlayout.Font, error) {
+	path, ok := l.fonts[family]
+	if !ok {
+		return nil, fmt.Errorf("font family %q is not installed", family)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if font, ok := l.parsed[family]; ok {
+		return font, nil
+	}
+
+	font, err := textlayout.LoadFont(path)
+	if err != nil {
+		return nil, err
+	}
+	if font, err = l.withFallbacks(font); err != nil {
+		return nil, err
+	}
+
+	l.parsed[family] = font
+	return font, nil
+}
+
+// LoadFont parses a font file that is not in the library, such as a brand kit font, with the same
+// fallbacks as library fonts. It is not cached.
+func (l *FontLibrary) LoadFont(path string) (*textlayout.Font, error) {
+	font, err := textlayout.LoadFont(path)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.withFallbacks(font)
+}
+
+// withFallbacks adds the script and emoji fallback fonts to font; the caller must hold l.mu
+func (l *FontLibrary) withFallbacks(font *textlayout.Font) (*textlayout.Font, error) {
+	for _, scriptFont := range l.scriptFonts {
+		fallback, err := l.fallback(scriptFont.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", scriptFont.name, err)
+		}
+		font = font.WithScriptFallback(fallback, scriptFont.scripts...)
+	}
+
+	if l.emojiPath != "" {
+		emoji, err := l.fallback(l.emojiPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load emoji font: %w", err)
+		}
+		font = font.WithFallback(emoji)
+	}
+	return font, nil
+}
+
+// fallback loads a fallback font on first use; the caller must hold l.mu
+func (l *FontLibrary) fallback(path string) (*textlayout.Font, error) {
+	if font, ok := l.fallbacks[path]; ok {
+		return font, nil
+	}
+	font, err := textlayout.LoadFont(path)
+	if err != nil {
+		return nil, err
+	}
+	l.fallbacks[path] = font
+	return font, nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxHookGenerationAttempts is how many times the model is asked for hooks before giving up,
+	// the attempts after the first being told what was wrong with its last reply
+	maxHookGenerationAttempts = 3
+	// maxHookLength is the most characters a hook may have; hooks are read on screen in a few seconds
+	maxHookLength = 200
+)
+
+// ErrInvalidLLMOutput is returned when the model's reply can't be turned into the requested hooks
+var ErrInvalidLLMOutput = errors.New("invalid LLM output")
+
+// hookOutputSchema is the JSON schema of the reply hook generation asks providers with structured
+// output for. Strict schemas can't express the count or length limits, so those are only described
+// and then checked by validateHooks.
+func hookOutputSchema(numHooks int) *LLMSchema {
+	return &LLMSchema{
+		Name: "hooks",
+		Schema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"hooks": map[string]any{
+					"type":        "array",
+					"description": fmt.Sprintf("Exactly %d distinct hooks, each at most %d characters", numHooks, maxHookLength),
+					"items":       map[string]any{"type": "string"},
+				},
+			},
+			"required":             []string{"hooks"},
+			"additionalProperties": false,
+		},
+	}
+}
+
+// extractHooks finds the hooks in a model's reply. It accepts a JSON array of strings or an object
+// with a hooks array, wrapped in markdown code fences or surrounded by prose.
+func extractHooks(reply string) ([]string, error) {
+	text := strings.TrimSpace(reply)
+
+	// Try every place a JSON value could start, so a stray bracket in leading prose is skipped; the
+	// decoder stops at the end of the value, ignoring whatever follows it
+	for i := 0; i < len(text); i++ {
+		if text[i] != '[' && text[i] != '{' {
+			continue
+		}
+
+		var value any
+		if err := json.NewDecoder(strings.NewReader(text[i:])).Decode(&value); err != nil {
+			continue
+		}
+		if hooks, ok := hooksFromJSON(value); ok {
+			return hooks, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: reply does not contain a JSON array of hooks", ErrInvalidLLMOutput)
+}
+
+// hooksFromJSON returns the hooks in a decoded JSON array of strings or object with a hooks array
+func hooksFromJSON(value any) ([]string, bool) {
+	if object, ok := value.(map[string]any); ok {
+		value, ok = object["hooks"]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	items, ok := value.([]any)
+	if !ok {
+		return nil, false
+	}
+	hooks := make([]string, len(items))
+	for i, item := range items {
+		if hooks[i], ok = item.(string); !ok {
+			return nil, false
+		}
+	}
+	return hooks, true
+}
+
+// validateHooks trims the hooks and checks there are exactly numHooks of them, each usable: not empty,
+// no longer than maxHookLength and not repeating an earlier hook. Anything else is an error listing what
+// was wrong, for the repair prompt.
+func validateHooks(hooks []string, numHooks int) ([]string, error) {
+	var valid, problems []string
+	seen := make(map[string]bool, len(hooks))
+	for i, hook := range hooks {
+		hook, problem := checkHook(hook, seen)
+		if problem != "" {
+			problems = append(problems, fmt.Sprintf("hook %d %s", i+1, problem))
+			continue
+		}
+		valid = append(valid, hook)
+	}
+
+	if len(hooks) != numHooks {
+		problems = append(problems, fmt.Sprintf("%d hooks were returned but exactly %d are needed", len(hooks), numHooks))
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLLMOutput, strings.Join(problems, "; "))
+	}
+	return valid, nil
+}
+
+// checkHook trims a hook and returns it, or describes why it can't be used. Usable hooks are added
+// to seen, the lowercased hooks kept so far.
+func checkHook(hook string, seen map[string]bool) (string, string) {
+	hook = strings.TrimSpace(hook)
+	key := strings.ToLower(hook)
+
+	switch {
+	case hook == "":
+		return "", "is empty"
+	case utf8.RuneCountInString(hook) > maxHookLength:
+		return "", fmt.Sprintf("is longer than %d characters", maxHookLength)
+	case seen[key]:
+		return "", "repeats an earlier hook"
+	}
+	seen[key] = true
+	return hook, ""
+}
+
+// hookStreamParser picks hooks out of a reply as it streams in. It tracks just enough JSON structure
+// to notice each string that finishes inside an array, so a hook can be used before the reply ends;
+// extractHooks still checks the complete reply.
+type hookStreamParser struct {
+	// containers holds the open brackets and braces, innermost last
+	containers []byte
+	inString   bool
+	escaped    bool
+	current    strings.Builder
+}
+
+// write feeds the next chunk of the reply and returns the hooks completed by it
+func (p *hookStreamParser) write(chunk string) []string {
+	var hooks []string
+	for i := 0; i < len(chunk); i++ {
+		c := chunk[i]
+
+		if p.inString {
+			p.current.WriteByte(c)
+			switch {
+			case p.escaped:
+				p.escaped = false
+			case c == '\\':
+				p.escaped = true
+			case c == '"':
+				p.inString = false
+				if hook, ok := p.finishString(); ok {
+					hooks = append(hooks, hook)
+				}
+			}
+			continue
+		}
+
+		switch c {
+		case '[', '{':
+			p.containers = append(p.containers, c)
+		case ']', '}':
+			if len(p.containers) > 0 {
+				p.containers = p.containers[:len(p.containers)-1]
+			}
+		case '"':
+			// Strings outside any container are prose, not JSON
+			if len(p.containers) > 0 {
+				p.inString = true
+				p.current.Reset()
+				p.current.WriteByte(c)
+			}
+		}
+	}
+	return hooks
+}
+
+// finishString decodes the string just read, returning it if it is an array item rather than an
+// object key or value
+func (p *hookStreamParser) finishString() (string, bool) {
+	if p.containers[len(p.containers)-1] != '[' {
+		return "", false
+	}
+
+	var hook string
+	if err := json.Unmarshal([]byte(p.current.String()), &hook); err != nil {
+		return "", false
+	}
+	return hook, true
+}
+
+// hookRepairPrompt asks the model to fix its last reply, which failed with err
+func hookRepairPrompt(prompt, reply string, err error, numHooks int) string {
+	return fmt.Sprintf(`%s
+
+Your previous reply was:
+%s
+
+It could not be used: %s.
+Reply with only the JSON for exactly %d distinct, non-empty hooks, each at most %d characters, and nothing else.`,
+		prompt, reply, strings.TrimPrefix(err.Error(), ErrInvalidLLMOutput.Error()+": "), numHooks, maxHookLength)
+}
+
+// hookContinuationPrompt asks the model for more hooks after a streamed reply fell short, listing the
+// ones already kept so they aren't repeated
+func hookContinuationPrompt(prompt string, kept []string, err error, numHooks int) string {
+	keptJSON, _ := json.Marshal(kept)
+	return fmt.Sprintf(`%s
+
+These hooks have already been written:
+%s
+
+The previous reply could not be used in full: %s.
+Reply with only a JSON array of %d more distinct, non-empty hooks, each at most %d characters and different from the ones above, and nothing else.`,
+		prompt, keptJSON, strings.TrimPrefix(err.Error(), ErrInvalidLLMOutput.Error()+": "), numHooks, maxHookLength)
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExtractHooks(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		want  []string
+	}{
+		{
+			name:  "array",
+			reply: `["one", "two"]`,
+			want:  []string{"one", "two"},
+		},
+		{
+			name:  "object with hooks",
+			reply: `{"hooks": ["one", "two"]}`,
+			want:  []string{"one", "two"},
+		},
+		{
+			name:  "markdown fence",
+			reply: "```json\n[\"one\", \"two\"]\n```",
+			want:  []string{"one", "two"},
+		},
+		{
+			name:  "surrounding prose",
+			reply: "Here are your hooks:\n[\"one\", \"two\"]\nLet me know if you want more!",
+			want:  []string{"one", "two"},
+		},
+		{
+			name:  "stray bracket in prose",
+			reply: "Hooks [as requested]: [\"one\", \"two\"]",
+			want:  []string{"one", "two"},
+		},
+		{
+			name:  "escaped quotes",
+			reply: `["she said \"stop\"", "two"]`,
+			want:  []string{`she said "stop"`, "two"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractHooks(tt.reply)
+			if err != nil {
+				t.Fatalf("extractHooks returned error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("extractHooks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractHooksRejectsReplyWithoutHooks(t *testing.T) {
+	for _, reply := range []string{
+		"",
+		"I can't help with that.",
+		`[1, 2, 3]`,
+		`{"hooks": "one"}`,
+		`["unterminated`,
+	} {
+		if _, err := extractHooks(reply); !errors.Is(err, ErrInvalidLLMOutput) {
+			t.Errorf("extractHooks(%q) error = %v, want ErrInvalidLLMOutput", reply, err)
+		}
+	}
+}
+
+func TestValidateHooks(t *testing.T) {
+	got, err := validateHooks([]string{"  one ", "two"}, 2)
+	if err != nil {
+		t.Fatalf("validateHooks returned error: %v", err)
+	}
+	if want := []string{"one", "two"}; !slices.Equal(got, want) {
+		t.Errorf("validateHooks = %q, want %q", got, want)
+	}
+}
+
+func TestValidateHooksRejectsUnusableHooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   []string
+		problem string
+	}{
+		{
+			name:    "too few",
+			hooks:   []string{"one"},
+			problem: "1 hooks were returned but exactly 2 are needed",
+		},
+		{
+			name:    "too many",
+			hooks:   []string{"one", "two", "three"},
+			problem: "3 hooks were returned but exactly 2 are needed",
+		},
+		{
+			name:    "empty",
+			hooks:   []string{"one", "   "},
+			problem: "hook 2 is empty",
+		},
+		{
+			name:    "too long",
+			hooks:   []string{"one", strings.Repeat("é", maxHookLength+1)},
+			problem: "hook 2 is longer than 200 characters",
+		},
+		{
+			name:    "duplicate",
+			hooks:   []string{"Same hook", " same HOOK"},
+			problem: "hook 2 repeats an earlier hook",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateHooks(tt.hooks, 2)
+			if !errors.Is(err, ErrInvalidLLMOutput) {
+				t.Fatalf("validateHooks error = %v, want ErrInvalidLLMOutput", err)
+			}
+			if !strings.Contains(err.Error(), tt.problem) {
+				t.Errorf("validateHooks error = %q, want it to mention %q", err, tt.problem)
+			}
+		})
+	}
+}
+
+func TestValidateHooksAllowsLongestHook(t *testing.T) {
+	hook := strings.Repeat("é", maxHookLength)
+	if _, err := validateHooks([]string{hook}, 1); err != nil {
+		t.Errorf("validateHooks rejected a hook of exactly %d characters: %v", maxHookLength, err)
+	}
+}
+
+// streamHooks streams reply through the fake provider a few characters at a time and returns the
+// hooks the parser picks out of it
+func streamHooks(t *testing.T, reply string) []string {
+	t.Helper()
+
+	var parser hookStreamParser
+	var hooks []string
+	_, err := NewFakeLLMProvider(reply).StreamText(context.Background(), "prompt", func(chunk string) {
+		hooks = append(hooks, parser.write(chunk)...)
+	})
+	if err != nil {
+		t.Fatalf("StreamText returned error: %v", err)
+	}
+	return hooks
+}
+
+func TestHookStreamParser(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		want  []string
+	}{
+		{
+			name:  "array",
+			reply: `["the first hook", "the second hook"]`,
+			want:  []string{"the first hook", "the second hook"},
+		},
+		{
+			name:  "object keys are skipped",
+			reply: `{"hooks": ["the first hook", "the second hook"]}`,
+			want:  []string{"the first hook", "the second hook"},
+		},
+		{
+			name:  "prose strings are skipped",
+			reply: "Here are \"your\" hooks:\n```json\n[\"the first hook\"]\n```",
+			want:  []string{"the first hook"},
+		},
+		{
+			name:  "escapes split across chunks",
+			reply: `["a \"quoted\" hook \\ with éscapes"]`,
+			want:  []string{`a "quoted" hook \ with éscapes`},
+		},
+		{
+			name:  "multibyte characters",
+			reply: `["café ☕ crème brûlée"]`,
+			want:  []string{"café ☕ crème brûlée"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := streamHooks(t, tt.reply); !slices.Equal(got, tt.want) {
+				t.Errorf("parsed hooks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
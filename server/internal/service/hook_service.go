@@ -3,17 +3,21 @@ package service
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"text/template"
 
+	"github.com/ethanhosier/reel-farm/db"
 	"github.com/ethanhosier/reel-farm/internal/api"
 	"github.com/ethanhosier/reel-farm/internal/repository"
 	"github.com/google/uuid"
 )
 
 const (
-	promptTemplate = `
+	// defaultHookInstructions are the instructions hooks are generated with when no prompt template is chosen
+	defaultHookInstructions = `
 You are a helpful assistant that generates short hooks for a tiktok slideshow.
 You will be given a prompt and you will need to generate a list of hooks for the prompt.
 
@@ -22,9 +26,11 @@ Prompt: "Plants dying in my house"
 Hooks:
 - "5 things I wish I knew before killing my plants"
 - "um so why did it take a plant expert explaining to me that traditional planters are so expensive just to constantly water plants..."
-- "fun fact you're probably spending way too much time and money on watering your plants when you don't need to"
+- "fun fact you're probably spending way too much time and money on watering your plants when you don't need to"`
 
-The hooks should be returned in a json array of strings.
+	// promptTemplate follows the instructions in every hook generation prompt, so prompt templates only
+	// need to describe a style
+	promptTemplate = `The hooks should be returned in a json array of strings.
 For example:
 [
   "hook1",
@@ -36,13 +42,27 @@ The prompt is: {{.Prompt}}
 The number of hooks to generate is: {{.NumHooks}}
 `
 
+	// hookRefinementTemplate is the instructions for refining a hook, in place of a prompt template's
+	hookRefinementTemplate = `
+You are a helpful assistant that rewrites hooks for a tiktok slideshow.
+You will be given a hook that was written for a prompt, and an instruction for how to change it.
+Write new versions of the hook that follow the instruction but keep its topic and what makes it work.
+Every new version must be different from the original hook and from each other.
+
+The original hook was written following these instructions, and the new versions must follow them too:
+{{.Instructions}}
+
+The original hook is: {{.Hook}}
+The instruction is: {{.Instruction}}`
+
 	creditCost = 10
 )
 
 type HookService struct {
-	userRepo   *repository.UserRepository
-	hookRepo   *repository.HookRepository
-	llmService *LLMService
+	userRepo              *repository.UserRepository
+	hookRepo              *repository.HookRepository
+	promptTemplateService *PromptTemplateService
+	llm                   LLMProvider
 }
 
 type HookTemplateData struct {
@@ -50,46 +70,82 @@ type HookTemplateData struct {
 	NumHooks int
 }
 
+// HookRefinementData is what the hook refinement template is filled in with
+type HookRefinementData struct {
+	Instructions string
+	Hook         string
+	Instruction  string
+}
+
 type HookResponse struct {
 	Hooks []string `json:"hooks"`
 }
 
-func NewHookService(userRepo *repository.UserRepository, hookRepo *repository.HookRepository, llmService *LLMService) *HookService {
+func NewHookService(userRepo *repository.UserRepository, hookRepo *repository.HookRepository, promptTemplateService *PromptTemplateService, llm LLMProvider) *HookService {
 	return &HookService{
-		userRepo:   userRepo,
-		hookRepo:   hookRepo,
-		llmService: llmService,
+		userRepo:              userRepo,
+		hookRepo:              hookRepo,
+		promptTemplateService: promptTemplateService,
+		llm:                   llm,
 	}
 }
 
-// TODO: Add idempotency and race condition protection
-func (s *HookService) GenerateHooks(ctx context.Context, userID uuid.UUID, prompt string, numHooks int) ([]api.Hook, error) {
-	// Use transaction to atomically check and deduct credits
-	err := s.userRepo.WithTransaction(ctx, func(txRepo *repository.UserRepository) error {
-		// Check if user has enough credits
-		userAccount, err := txRepo.GetUserAccount(ctx, userID)
-		if err != nil {
-			return fmt.Errorf("failed to get user account: %w", err)
-		}
+// hookPromptSource is what a generation's prompt is built from: the instructions to follow and the
+// prompt template version they come from, nil for the built-in instructions
+type hookPromptSource struct {
+	Instructions            string
+	PromptTemplateVersionID *uuid.UUID
+}
 
-		if userAccount.Credits < creditCost {
-			return fmt.Errorf("insufficient credits: have %d, need %d", userAccount.Credits, creditCost)
-		}
+// promptSource gets the latest version of an active prompt template, or the built-in instructions if
+// templateID is nil
+func (s *HookService) promptSource(ctx context.Context, templateID *uuid.UUID) (*hookPromptSource, error) {
+	if templateID == nil {
+		return &hookPromptSource{Instructions: defaultHookInstructions}, nil
+	}
 
-		// Remove credits
-		err = txRepo.RemoveCreditsFromUser(ctx, userID, creditCost)
-		if err != nil {
-			return fmt.Errorf("failed to remove credits: %w", err)
-		}
+	version, err := s.promptTemplateService.activeVersion(ctx, *templateID)
+	if err != nil {
+		return nil, err
+	}
+	return &hookPromptSource{
+		Instructions:            version.Instructions,
+		PromptTemplateVersionID: &version.ID,
+	}, nil
+}
 
-		return nil
-	})
+// parentPromptSource gets the prompt template version a hook was generated with, even if its template has
+// since been deactivated, or the built-in instructions if it was generated without one
+func (s *HookService) parentPromptSource(ctx context.Context, parent *db.Hook) (*hookPromptSource, error) {
+	if !parent.PromptTemplateVersionID.Valid {
+		return &hookPromptSource{Instructions: defaultHookInstructions}, nil
+	}
+
+	version, err := s.promptTemplateService.version(ctx, uuid.UUID(parent.PromptTemplateVersionID.Bytes))
+	if err != nil {
+		return nil, err
+	}
+	return &hookPromptSource{
+		Instructions:            version.Instructions,
+		PromptTemplateVersionID: &version.ID,
+	}, nil
+}
+
+// TODO: Add idempotency and race condition protection
+func (s *HookService) GenerateHooks(ctx context.Context, userID uuid.UUID, prompt string, numHooks int, templateID *uuid.UUID) ([]api.Hook, error) {
+	// Look up the template before charging, so a missing one costs nothing
+	source, err := s.promptSource(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.deductCredits(ctx, userID)
 	if err != nil {
 		_ = s.userRepo.AddCreditsToUser(ctx, userID, creditCost)
 		return nil, fmt.Errorf("failed to deduct credits: %w", err)
 	}
 
-	hooks, err := s.doGenerateHooks(ctx, prompt, numHooks)
+	hooks, err := s.doGenerateHooks(ctx, source.Instructions, prompt, numHooks)
 	if err != nil {
 		_ = s.userRepo.AddCreditsToUser(ctx, userID, creditCost)
 		return nil, fmt.Errorf("failed to generate hooks: %w", err)
@@ -97,7 +153,7 @@ func (s *HookService) GenerateHooks(ctx context.Context, userID uuid.UUID, promp
 
 	// Store hooks in database and collect results
 	generationID := uuid.New()
-	createdHooks, err := s.hookRepo.CreateHooksBatch(ctx, userID, generationID, prompt, hooks, creditCost)
+	createdHooks, err := s.hookRepo.CreateHooksBatch(ctx, userID, generationID, prompt, hooks, creditCost, source.PromptTemplateVersionID)
 	if err != nil {
 		// If storing fails, refund credits and return error
 		_ = s.userRepo.AddCreditsToUser(ctx, userID, creditCost)
@@ -107,15 +163,164 @@ func (s *HookService) GenerateHooks(ctx context.Context, userID uuid.UUID, promp
 	// Convert database hooks to API hooks
 	var hookResults []api.Hook
 	for _, dbHook := range createdHooks {
-		hookResults = append(hookResults, api.Hook{
-			Id:   dbHook.ID,
-			Text: dbHook.HookText,
-		})
+		hookResults = append(hookResults, toAPIHook(dbHook))
 	}
 
 	return hookResults, nil
 }
 
+// ErrHookNotFound is returned when a hook does not exist or belongs to another user
+var ErrHookNotFound = errors.New("hook not found")
+
+// RefineHook generates numVariants variants of one of a user's hooks following instruction, written for
+// the hook's prompt with the prompt template version it was generated with. The variants are saved as
+// children of the hook, forming a refinement tree.
+func (s *HookService) RefineHook(ctx context.Context, userID, hookID uuid.UUID, instruction string, numVariants int) ([]api.Hook, error) {
+	// Look up the hook before charging, so a missing one costs nothing
+	parent, err := s.hookRepo.GetUserHookByID(ctx, hookID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, ErrHookNotFound
+	}
+
+	// Keep to the style the hook was written in
+	source, err := s.parentPromptSource(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	instructions, err := hookRefinementInstructions(source.Instructions, parent.HookText, instruction)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deductCredits(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to deduct credits: %w", err)
+	}
+
+	hooks, err := s.doGenerateHooks(ctx, instructions, parent.Prompt, numVariants)
+	if err != nil {
+		_ = s.userRepo.AddCreditsToUser(ctx, userID, creditCost)
+		return nil, fmt.Errorf("failed to refine hook: %w", err)
+	}
+
+	// Store the variants as children of the hook
+	createdHooks, err := s.hookRepo.CreateHookRefinements(ctx, userID, uuid.New(), parent, instruction, hooks, creditCost)
+	if err != nil {
+		// If storing fails, refund credits and return error
+		_ = s.userRepo.AddCreditsToUser(ctx, userID, creditCost)
+		return nil, fmt.Errorf("failed to store hooks: %w", err)
+	}
+
+	// Convert database hooks to API hooks
+	var hookResults []api.Hook
+	for _, dbHook := range createdHooks {
+		hookResults = append(hookResults, toAPIHook(dbHook))
+	}
+
+	return hookResults, nil
+}
+
+// HookStreamError is returned by StreamHooks when generation fails after it has started sending hooks.
+// The hooks already sent are deleted along with the credits being refunded, and DeletedHookIDs lists
+// them so the client can drop them.
+type HookStreamError struct {
+	DeletedHookIDs []uuid.UUID
+	Err            error
+}
+
+// Error returns the underlying error's message
+func (e *HookStreamError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *HookStreamError) Unwrap() error {
+	return e.Err
+}
+
+// StreamHooks generates hooks like GenerateHooks, but saves each hook and passes it to onHook as soon
+// as the model writes it. If generation fails, even after the last hook has been sent, the credits are
+// refunded and the hooks already saved are deleted, and the error is a *HookStreamError listing them.
+func (s *HookService) StreamHooks(ctx context.Context, userID uuid.UUID, prompt string, numHooks int, templateID *uuid.UUID, onHook func(api.GeneratedHookEvent)) (*api.HookGenerationSummary, error) {
+	// Look up the template before charging, so a missing one costs nothing
+	source, err := s.promptSource(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deductCredits(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to deduct credits: %w", err)
+	}
+
+	// Save each hook as it arrives so the event sent for it carries its ID
+	generationID := uuid.New()
+	var hooks []api.Hook
+	save := func(hookText string) error {
+		dbHook, err := s.hookRepo.CreateHook(ctx, userID, generationID, prompt, hookText, int32(len(hooks)), creditCost, source.PromptTemplateVersionID)
+		if err != nil {
+			return err
+		}
+
+		hook := toAPIHook(dbHook)
+		onHook(api.GeneratedHookEvent{
+			Id:    hook.Id,
+			Text:  hook.Text,
+			Index: len(hooks),
+		})
+		hooks = append(hooks, hook)
+		return nil
+	}
+
+	if err := s.doStreamHooks(ctx, source.Instructions, prompt, numHooks, save); err != nil {
+		return nil, s.abandonHookStream(ctx, userID, generationID, hooks, fmt.Errorf("failed to generate hooks: %w", err))
+	}
+
+	// The summary can't be sent without the remaining credits, so the generation is undone the same way
+	userAccount, err := s.userRepo.GetUserAccount(ctx, userID)
+	if err != nil {
+		return nil, s.abandonHookStream(ctx, userID, generationID, hooks, fmt.Errorf("failed to get user account: %w", err))
+	}
+
+	return &api.HookGenerationSummary{
+		GenerationId:     generationID,
+		Hooks:            hooks,
+		CreditsUsed:      creditCost,
+		RemainingCredits: int(userAccount.Credits),
+	}, nil
+}
+
+// abandonHookStream refunds a streamed generation that failed and deletes the hooks already sent,
+// returning err as a *HookStreamError listing the hooks the client should drop
+func (s *HookService) abandonHookStream(ctx context.Context, userID, generationID uuid.UUID, hooks []api.Hook, err error) *HookStreamError {
+	// Clean up even if the client went away and cancelled the request
+	cleanupCtx := context.WithoutCancel(ctx)
+	_ = s.userRepo.AddCreditsToUser(cleanupCtx, userID, creditCost)
+
+	streamErr := &HookStreamError{
+		DeletedHookIDs: []uuid.UUID{},
+		Err:            err,
+	}
+	if len(hooks) == 0 {
+		return streamErr
+	}
+
+	hookIDs := make([]uuid.UUID, len(hooks))
+	for i, hook := range hooks {
+		hookIDs[i] = hook.Id
+	}
+	deletedHooks, deleteErr := s.hookRepo.DeleteHooks(cleanupCtx, hookIDs, userID)
+	if deleteErr != nil {
+		log.Printf("❌ Failed to delete hooks of failed generation %s: %v", generationID, deleteErr)
+	}
+	for _, dbHook := range deletedHooks {
+		streamErr.DeletedHookIDs = append(streamErr.DeletedHookIDs, dbHook.ID)
+	}
+	return streamErr
+}
+
 // GetHooks retrieves hooks for a user with pagination
 func (s *HookService) GetHooks(ctx context.Context, userID uuid.UUID, limit int32, offset int32) ([]api.Hook, int64, error) {
 	// Get hooks from repository
@@ -133,19 +338,163 @@ func (s *HookService) GetHooks(ctx context.Context, userID uuid.UUID, limit int3
 	// Convert database hooks to API hooks
 	hookResults := []api.Hook{}
 	for _, dbHook := range dbHooks {
-		hookResults = append(hookResults, api.Hook{
-			Id:   dbHook.ID,
-			Text: dbHook.HookText,
-		})
+		hookResults = append(hookResults, toAPIHook(dbHook))
 	}
 
 	return hookResults, totalCount, nil
 }
 
-func (s *HookService) doGenerateHooks(ctx context.Context, prompt string, numHooks int) ([]string, error) {
+func (s *HookService) doGenerateHooks(ctx context.Context, instructions, prompt string, numHooks int) ([]string, error) {
+	generatedPrompt, err := hookPrompt(instructions, prompt, numHooks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ask for hooks until a reply passes validation, telling the model what was wrong with the last one
+	structured, isStructured := s.llm.(StructuredLLMProvider)
+	attemptPrompt := generatedPrompt
+	var lastErr error
+	for attempt := 1; attempt <= maxHookGenerationAttempts; attempt++ {
+		// Call the LLM provider, constraining the reply to the schema where it supports that
+		var response string
+		if isStructured {
+			response, err = structured.GenerateJSON(ctx, attemptPrompt, hookOutputSchema(numHooks))
+		} else {
+			response, err = s.llm.GenerateText(ctx, attemptPrompt)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate text: %w", err)
+		}
+
+		hooks, err := extractHooks(response)
+		if err == nil {
+			hooks, err = validateHooks(hooks, numHooks)
+		}
+		if err == nil {
+			return hooks, nil
+		}
+
+		log.Printf("⚠️ Hook generation attempt %d/%d with %s was rejected: %v", attempt, maxHookGenerationAttempts, s.llm.Name(), err)
+		lastErr = err
+		attemptPrompt = hookRepairPrompt(generatedPrompt, response, err, numHooks)
+	}
+
+	return nil, lastErr
+}
+
+// doStreamHooks passes each usable hook to save as the model writes it, until numHooks have been
+// saved. A reply that falls short is followed by a request for the hooks still missing, but one with
+// more hooks than were asked for fails the generation, as the hooks before them have already been saved.
+// Providers that can't stream fall back to generating every hook before any is saved.
+func (s *HookService) doStreamHooks(ctx context.Context, instructions, prompt string, numHooks int, save func(hookText string) error) error {
+	streaming, isStreaming := s.llm.(StreamingLLMProvider)
+	if !isStreaming {
+		hooks, err := s.doGenerateHooks(ctx, instructions, prompt, numHooks)
+		if err != nil {
+			return err
+		}
+		for _, hook := range hooks {
+			if err := save(hook); err != nil {
+				return fmt.Errorf("failed to store hook: %w", err)
+			}
+		}
+		return nil
+	}
+
+	generatedPrompt, err := hookPrompt(instructions, prompt, numHooks)
+	if err != nil {
+		return err
+	}
+
+	var kept []string
+	seen := make(map[string]bool, numHooks)
+	attemptPrompt := generatedPrompt
+	var lastErr error
+	for attempt := 1; attempt <= maxHookGenerationAttempts; attempt++ {
+		// Stop the stream if a hook can't be saved or there are too many, as the generation will fail anyway
+		streamCtx, cancel := context.WithCancel(ctx)
+		needed := numHooks - len(kept)
+		var parser hookStreamParser
+		var problems []string
+		var saveErr error
+		returned := 0
+
+		_, err := streaming.StreamText(streamCtx, attemptPrompt, func(chunk string) {
+			for _, hook := range parser.write(chunk) {
+				returned++
+				if returned > needed {
+					cancel()
+				}
+				if saveErr != nil || returned > needed {
+					continue
+				}
+
+				hook, problem := checkHook(hook, seen)
+				if problem != "" {
+					problems = append(problems, fmt.Sprintf("hook %d %s", returned, problem))
+					continue
+				}
+				if saveErr = save(hook); saveErr != nil {
+					cancel()
+					continue
+				}
+				kept = append(kept, hook)
+			}
+		})
+		cancel()
+		if saveErr != nil {
+			return fmt.Errorf("failed to store hook: %w", saveErr)
+		}
+		if returned > needed {
+			return fmt.Errorf("%w: more than the %d hooks asked for were returned", ErrInvalidLLMOutput, needed)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to generate text: %w", err)
+		}
+		if len(kept) == numHooks {
+			return nil
+		}
+
+		usable := needed - (numHooks - len(kept))
+		problems = append(problems, fmt.Sprintf("%d usable hooks were returned but %d are needed", usable, needed))
+		lastErr = fmt.Errorf("%w: %s", ErrInvalidLLMOutput, strings.Join(problems, "; "))
+		log.Printf("⚠️ Hook stream attempt %d/%d with %s fell short: %v", attempt, maxHookGenerationAttempts, s.llm.Name(), lastErr)
+		attemptPrompt = hookContinuationPrompt(generatedPrompt, kept, lastErr, numHooks-len(kept))
+	}
+
+	return lastErr
+}
+
+// deductCredits checks the user can afford a generation and takes its cost from their credits
+func (s *HookService) deductCredits(ctx context.Context, userID uuid.UUID) error {
+	// Use transaction to atomically check and deduct credits
+	return s.userRepo.WithTransaction(ctx, func(txRepo *repository.UserRepository) error {
+		// Check if user has enough credits
+		userAccount, err := txRepo.GetUserAccount(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get user account: %w", err)
+		}
+
+		if userAccount.Credits < creditCost {
+			return fmt.Errorf("insufficient credits: have %d, need %d", userAccount.Credits, creditCost)
+		}
+
+		// Remove credits
+		err = txRepo.RemoveCreditsFromUser(ctx, userID, creditCost)
+		if err != nil {
+			return fmt.Errorf("failed to remove credits: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// hookPrompt builds the hook generation prompt from instructions, followed by the output format, prompt
+// and number of hooks filled into the prompt template
+func hookPrompt(instructions, prompt string, numHooks int) (string, error) {
 	tmpl, err := template.New("hookPrompt").Parse(promptTemplate)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse template: %w", err)
+		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
 
 	// Execute the template with the provided data
@@ -156,25 +505,47 @@ func (s *HookService) doGenerateHooks(ctx context.Context, prompt string, numHoo
 	}
 
 	if err := tmpl.Execute(&buf, data); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
+		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
 	// Get the generated prompt
-	generatedPrompt := buf.String()
+	return strings.TrimRight(instructions, "\n") + "\n\n" + buf.String(), nil
+}
 
-	// Call the LLM service
-	response, err := s.llmService.GenerateText(ctx, generatedPrompt)
+// hookRefinementInstructions fills in the hook refinement template with the instructions the hook was
+// generated with
+func hookRefinementInstructions(instructions, hook, instruction string) (string, error) {
+	tmpl, err := template.New("hookRefinement").Parse(hookRefinementTemplate)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate text: %w", err)
+		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	// Parse the JSON response
-	var hooks []string
-	if err := json.Unmarshal([]byte(response), &hooks); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	var buf bytes.Buffer
+	data := HookRefinementData{
+		Instructions: strings.TrimSpace(instructions),
+		Hook:         hook,
+		Instruction:  instruction,
+	}
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	return hooks, nil
+	return buf.String(), nil
+}
+
+// toAPIHook converts a database hook to an API hook
+func toAPIHook(dbHook *db.Hook) api.Hook {
+	hook := api.Hook{
+		Id:                    dbHook.ID,
+		Text:                  dbHook.HookText,
+		RefinementInstruction: dbHook.RefinementInstruction,
+	}
+	if dbHook.ParentHookID.Valid {
+		parentHookID := uuid.UUID(dbHook.ParentHookID.Bytes)
+		hook.ParentHookId = &parentHookID
+	}
+	return hook
 }
 
 // DeleteHook deletes a hook (only if it belongs to the user)
@@ -196,10 +567,7 @@ func (s *HookService) DeleteHooks(ctx context.Context, hookIDs []uuid.UUID, user
 	// Convert database hooks to API hooks
 	var hookResults []api.Hook
 	for _, dbHook := range deletedHooks {
-		hookResults = append(hookResults, api.Hook{
-			Id:   dbHook.ID,
-			Text: dbHook.HookText,
-		})
+		hookResults = append(hookResults, toAPIHook(dbHook))
 	}
 
 	return hookResults, nil
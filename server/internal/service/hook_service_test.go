@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDoGenerateHooks(t *testing.T) {
+	llm := NewUnscriptedFakeLLMProvider()
+	s := &HookService{llm: llm}
+
+	hooks, err := s.doGenerateHooks(context.Background(), defaultHookInstructions, "plants dying in my house", 3)
+	if err != nil {
+		t.Fatalf("doGenerateHooks returned error: %v", err)
+	}
+	if want := defaultFakeLLMHooks[:3]; !slices.Equal(hooks, want) {
+		t.Errorf("hooks = %q, want %q", hooks, want)
+	}
+
+	prompts := llm.Prompts()
+	if len(prompts) != 1 {
+		t.Fatalf("provider was prompted %d times, want 1", len(prompts))
+	}
+	for _, want := range []string{
+		strings.TrimSpace(defaultHookInstructions),
+		"The prompt is: plants dying in my house",
+		"The number of hooks to generate is: 3",
+	} {
+		if !strings.Contains(prompts[0], want) {
+			t.Errorf("prompt does not contain %q:\n%s", want, prompts[0])
+		}
+	}
+}
+
+func TestDoGenerateHooksRepairsBadReply(t *testing.T) {
+	badReply := `Sure! ["one", "one"]`
+	llm := NewFakeLLMProvider(badReply, `["one", "two"]`)
+	s := &HookService{llm: llm}
+
+	hooks, err := s.doGenerateHooks(context.Background(), defaultHookInstructions, "prompt", 2)
+	if err != nil {
+		t.Fatalf("doGenerateHooks returned error: %v", err)
+	}
+	if want := []string{"one", "two"}; !slices.Equal(hooks, want) {
+		t.Errorf("hooks = %q, want %q", hooks, want)
+	}
+
+	// The second attempt repeats the original prompt and says what was wrong with the first reply
+	prompts := llm.Prompts()
+	if len(prompts) != 2 {
+		t.Fatalf("provider was prompted %d times, want 2", len(prompts))
+	}
+	for _, want := range []string{
+		prompts[0],
+		"Your previous reply was:\n" + badReply,
+		"It could not be used: hook 2 repeats an earlier hook.",
+		"exactly 2 distinct, non-empty hooks",
+	} {
+		if !strings.Contains(prompts[1], want) {
+			t.Errorf("repair prompt does not contain %q:\n%s", want, prompts[1])
+		}
+	}
+}
+
+func TestDoGenerateHooksGivesUp(t *testing.T) {
+	llm := NewFakeLLMProvider("I can't help with that.")
+	s := &HookService{llm: llm}
+
+	_, err := s.doGenerateHooks(context.Background(), defaultHookInstructions, "prompt", 2)
+	if !errors.Is(err, ErrInvalidLLMOutput) {
+		t.Fatalf("doGenerateHooks error = %v, want ErrInvalidLLMOutput", err)
+	}
+	if got := len(llm.Prompts()); got != maxHookGenerationAttempts {
+		t.Errorf("provider was prompted %d times, want %d", got, maxHookGenerationAttempts)
+	}
+}
+
+// streamHooksTo runs doStreamHooks against llm, returning the hooks it saved
+func streamHooksTo(llm *FakeLLMProvider, numHooks int) ([]string, error) {
+	s := &HookService{llm: llm}
+	var saved []string
+	err := s.doStreamHooks(context.Background(), defaultHookInstructions, "prompt", numHooks, func(hook string) error {
+		saved = append(saved, hook)
+		return nil
+	})
+	return saved, err
+}
+
+func TestDoStreamHooks(t *testing.T) {
+	llm := NewUnscriptedFakeLLMProvider()
+
+	saved, err := streamHooksTo(llm, 5)
+	if err != nil {
+		t.Fatalf("doStreamHooks returned error: %v", err)
+	}
+	if want := defaultFakeLLMHooks[:5]; !slices.Equal(saved, want) {
+		t.Errorf("saved hooks = %q, want %q", saved, want)
+	}
+	if got := len(llm.Prompts()); got != 1 {
+		t.Errorf("provider was prompted %d times, want 1", got)
+	}
+}
+
+func TestDoStreamHooksContinuesShortReply(t *testing.T) {
+	llm := NewFakeLLMProvider(`["one", "", "two"]`, `["three"]`)
+
+	saved, err := streamHooksTo(llm, 3)
+	if err != nil {
+		t.Fatalf("doStreamHooks returned error: %v", err)
+	}
+	if want := []string{"one", "two", "three"}; !slices.Equal(saved, want) {
+		t.Errorf("saved hooks = %q, want %q", saved, want)
+	}
+
+	// The continuation lists the hooks already kept and asks only for the one still missing
+	prompts := llm.Prompts()
+	if len(prompts) != 2 {
+		t.Fatalf("provider was prompted %d times, want 2", len(prompts))
+	}
+	for _, want := range []string{
+		"These hooks have already been written:\n[\"one\",\"two\"]",
+		"hook 2 is empty; 2 usable hooks were returned but 3 are needed",
+		"a JSON array of 1 more distinct, non-empty hooks",
+	} {
+		if !strings.Contains(prompts[1], want) {
+			t.Errorf("continuation prompt does not contain %q:\n%s", want, prompts[1])
+		}
+	}
+}
+
+func TestDoStreamHooksRejectsExtraHooks(t *testing.T) {
+	llm := NewFakeLLMProvider(`["one", "two", "three"]`)
+
+	saved, err := streamHooksTo(llm, 2)
+	if !errors.Is(err, ErrInvalidLLMOutput) {
+		t.Fatalf("doStreamHooks error = %v, want ErrInvalidLLMOutput", err)
+	}
+	if want := []string{"one", "two"}; !slices.Equal(saved, want) {
+		t.Errorf("saved hooks = %q, want %q", saved, want)
+	}
+	if got := len(llm.Prompts()); got != 1 {
+		t.Errorf("provider was prompted %d times, want 1", got)
+	}
+}
+
+func TestDoStreamHooksStopsWhenSaveFails(t *testing.T) {
+	s := &HookService{llm: NewUnscriptedFakeLLMProvider()}
+	saveErr := errors.New("database is down")
+
+	saves := 0
+	err := s.doStreamHooks(context.Background(), defaultHookInstructions, "prompt", 3, func(hook string) error {
+		saves++
+		return saveErr
+	})
+	if !errors.Is(err, saveErr) {
+		t.Fatalf("doStreamHooks error = %v, want %v", err, saveErr)
+	}
+	if saves != 1 {
+		t.Errorf("save was called %d times, want 1", saves)
+	}
+}
+
+func TestHookRefinementInstructions(t *testing.T) {
+	instructions, err := hookRefinementInstructions("Write in the voice of a tired plant parent.\n", "my plants keep dying", "shorter")
+	if err != nil {
+		t.Fatalf("hookRefinementInstructions returned error: %v", err)
+	}
+	for _, want := range []string{
+		"these instructions, and the new versions must follow them too:\nWrite in the voice of a tired plant parent.\n",
+		"The original hook is: my plants keep dying",
+		"The instruction is: shorter",
+	} {
+		if !strings.Contains(instructions, want) {
+			t.Errorf("refinement instructions do not contain %q:\n%s", want, instructions)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+// anthropicMaxTokens caps the length of a response, comfortably more than a batch of hooks needs
+const anthropicMaxTokens = 2048
+
+// AnthropicProvider generates text with the Anthropic messages API
+type AnthropicProvider struct {
+	client anthropic.Client
+	model  anthropic.Model
+}
+
+// NewAnthropicProvider creates a provider for the Anthropic API; an empty model uses Claude Haiku 4.5
+func NewAnthropicProvider(apiKey, model string) *AnthropicProvider {
+	if model == "" {
+		model = string(anthropic.ModelClaudeHaiku4_5)
+	}
+
+	return &AnthropicProvider{
+		client: anthropic.NewClient(option.WithAPIKey(apiKey)),
+		model:  anthropic.Model(model),
+	}
+}
+
+// Name identifies the provider and model
+func (p *AnthropicProvider) Name() string {
+	return llmProviderAnthropic + "/" + string(p.model)
+}
+
+// GenerateText takes a prompt and returns the generated text response
+func (p *AnthropicProvider) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return p.generate(ctx, anthropic.MessageNewParams{
+		Model:     p.model,
+		MaxTokens: anthropicMaxTokens,
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+		},
+	})
+}
+
+// GenerateJSON takes a prompt and returns a response that follows schema, using structured outputs
+func (p *AnthropicProvider) GenerateJSON(ctx context.Context, prompt string, schema *LLMSchema) (string, error) {
+	return p.generate(ctx, anthropic.MessageNewParams{
+		Model:     p.model,
+		MaxTokens: anthropicMaxTokens,
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+		},
+		OutputConfig: anthropic.OutputConfigParam{
+			Format: anthropic.JSONOutputFormatParam{Schema: schema.Schema},
+		},
+	})
+}
+
+// StreamText takes a prompt, calls onText with each chunk of the response as it arrives and returns
+// the whole response
+func (p *AnthropicProvider) StreamText(ctx context.Context, prompt string, onText func(chunk string)) (string, error) {
+	stream := p.client.Messages.NewStreaming(ctx, anthropic.MessageNewParams{
+		Model:     p.model,
+		MaxTokens: anthropicMaxTokens,
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+		},
+	})
+	defer stream.Close()
+
+	var text strings.Builder
+	for stream.Next() {
+		event := stream.Current()
+		if event.Type == "content_block_delta" && event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+			text.WriteString(event.Delta.Text)
+			onText(event.Delta.Text)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return "", fmt.Errorf("failed to stream text: %w", err)
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no content in response message")
+	}
+
+	return text.String(), nil
+}
+
+// generate sends a message request and returns the text of the reply
+func (p *AnthropicProvider) generate(ctx context.Context, params anthropic.MessageNewParams) (string, error) {
+	message, err := p.client.Messages.New(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate text: %w", err)
+	}
+
+	// The response can be split over several text blocks
+	var text strings.Builder
+	for _, block := range message.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no content in response message")
+	}
+
+	return text.String(), nil
+}
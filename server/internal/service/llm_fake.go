@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// defaultFakeLLMHooks are the hooks the fake provider replies with when it is not given a script, as
+// many as a request can ask for
+var defaultFakeLLMHooks = []string{
+	"the one thing nobody tells you about this",
+	"I tried this for 30 days so you don't have to",
+	"stop scrolling if you've ever struggled with this",
+	"3 mistakes I made before I figured this out",
+	"the honest truth about this that experts won't say",
+	"POV: you finally found the fix for this",
+	"why is nobody talking about this yet",
+	"this changed everything for me in a week",
+	"what I wish I knew before I started",
+	"the cheapest way to sort this out, ranked",
+}
+
+// fakeLLMHookCountPattern finds how many hooks a hook generation, repair or continuation prompt asks for
+var fakeLLMHookCountPattern = regexp.MustCompile(`(?:number of hooks to generate is: |exactly |array of )(\d+)`)
+
+// fakeLLMStreamChunkSize is how many characters the fake provider streams at a time
+const fakeLLMStreamChunkSize = 8
+
+// FakeLLMProvider is a deterministic LLMProvider for tests and for running without network access. It
+// replies with its scripted responses in turn, repeating the last once they run out, or with default
+// hooks when unscripted, and records the prompts it was given.
+type FakeLLMProvider struct {
+	mu        sync.Mutex
+	responses []string
+	prompts   []string
+	// unscripted replies to each prompt with as many of defaultFakeLLMHooks as it asks for
+	unscripted bool
+}
+
+// NewFakeLLMProvider creates a fake provider that replies with responses in order
+func NewFakeLLMProvider(responses ...string) *FakeLLMProvider {
+	return &FakeLLMProvider{responses: responses}
+}
+
+// NewUnscriptedFakeLLMProvider creates a fake provider that replies to each hook prompt with a JSON
+// array of as many hooks as it asks for
+func NewUnscriptedFakeLLMProvider() *FakeLLMProvider {
+	return &FakeLLMProvider{unscripted: true}
+}
+
+// Name identifies the provider
+func (p *FakeLLMProvider) Name() string {
+	return llmProviderFake
+}
+
+// GenerateText records the prompt and returns the next scripted response
+func (p *FakeLLMProvider) GenerateText(ctx context.Context, prompt string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prompts = append(p.prompts, prompt)
+	if p.unscripted {
+		return fakeHooksReply(prompt), nil
+	}
+	if len(p.responses) == 0 {
+		return "", fmt.Errorf("fake LLM provider has no scripted responses")
+	}
+	return p.responses[min(len(p.prompts), len(p.responses))-1], nil
+}
+
+// fakeHooksReply returns a JSON array of as many default hooks as prompt asks for, going by the last
+// count it mentions
+func fakeHooksReply(prompt string) string {
+	numHooks := len(defaultFakeLLMHooks)
+	if matches := fakeLLMHookCountPattern.FindAllStringSubmatch(prompt, -1); len(matches) > 0 {
+		if n, err := strconv.Atoi(matches[len(matches)-1][1]); err == nil && n < numHooks {
+			numHooks = n
+		}
+	}
+
+	reply, _ := json.MarshalIndent(defaultFakeLLMHooks[:numHooks], "", "  ")
+	return string(reply)
+}
+
+// StreamText records the prompt and returns the next scripted response, passing it to onText a few
+// characters at a time the way a real model streams
+func (p *FakeLLMProvider) StreamText(ctx context.Context, prompt string, onText func(chunk string)) (string, error) {
+	response, err := p.GenerateText(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	for chunk := range slices.Chunk([]rune(response), fakeLLMStreamChunkSize) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		onText(string(chunk))
+	}
+	return response, nil
+}
+
+// Prompts returns the prompts the provider has been given, oldest first
+func (p *FakeLLMProvider) Prompts() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.prompts)
+}
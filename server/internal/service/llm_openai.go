@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"github.com/openai/openai-go/v3/shared"
+)
+
+// OpenAIProvider generates text with the OpenAI chat completions API, constraining it to a JSON
+// schema when asked
+type OpenAIProvider struct {
+	chat openAIChat
+}
+
+// LocalLLMProvider generates text with an OpenAI-compatible server such as Ollama. Local servers
+// support structured output unevenly, so it only generates plain text.
+type LocalLLMProvider struct {
+	chat openAIChat
+}
+
+// openAIChat sends prompts to a chat completions API
+type openAIChat struct {
+	client openai.Client
+	model  shared.ChatModel
+	name   string
+}
+
+// NewOpenAIProvider creates a provider for the OpenAI API; an empty model uses GPT-5 mini
+func NewOpenAIProvider(apiKey, model string) *OpenAIProvider {
+	if model == "" {
+		model = shared.ChatModelGPT5Mini
+	}
+
+	return &OpenAIProvider{chat: openAIChat{
+		client: openai.NewClient(option.WithAPIKey(apiKey)),
+		model:  model,
+		name:   llmProviderOpenAI + "/" + model,
+	}}
+}
+
+// NewLocalLLMProvider creates a provider for an OpenAI-compatible server at baseURL. Most local
+// servers ignore the API key, so it may be empty.
+func NewLocalLLMProvider(baseURL, apiKey, model string) *LocalLLMProvider {
+	if apiKey == "" {
+		apiKey = llmProviderLocal
+	}
+
+	return &LocalLLMProvider{chat: openAIChat{
+		client: openai.NewClient(option.WithBaseURL(baseURL), option.WithAPIKey(apiKey)),
+		model:  model,
+		name:   llmProviderLocal + "/" + model,
+	}}
+}
+
+// Name identifies the provider and model
+func (p *OpenAIProvider) Name() string {
+	return p.chat.name
+}
+
+// GenerateText takes a prompt and returns the generated text response
+func (p *OpenAIProvider) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return p.chat.complete(ctx, prompt, openai.ChatCompletionNewParamsResponseFormatUnion{})
+}
+
+// GenerateJSON takes a prompt and returns a response that follows schema, using strict structured outputs
+func (p *OpenAIProvider) GenerateJSON(ctx context.Context, prompt string, schema *LLMSchema) (string, error) {
+	return p.chat.complete(ctx, prompt, openai.ChatCompletionNewParamsResponseFormatUnion{
+		OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
+			JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
+				Name:   schema.Name,
+				Schema: schema.Schema,
+				Strict: openai.Bool(true),
+			},
+		},
+	})
+}
+
+// StreamText takes a prompt, calls onText with each chunk of the response as it arrives and returns
+// the whole response
+func (p *OpenAIProvider) StreamText(ctx context.Context, prompt string, onText func(chunk string)) (string, error) {
+	return p.chat.stream(ctx, prompt, onText)
+}
+
+// Name identifies the provider and model
+func (p *LocalLLMProvider) Name() string {
+	return p.chat.name
+}
+
+// GenerateText takes a prompt and returns the generated text response
+func (p *LocalLLMProvider) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return p.chat.complete(ctx, prompt, openai.ChatCompletionNewParamsResponseFormatUnion{})
+}
+
+// StreamText takes a prompt, calls onText with each chunk of the response as it arrives and returns
+// the whole response
+func (p *LocalLLMProvider) StreamText(ctx context.Context, prompt string, onText func(chunk string)) (string, error) {
+	return p.chat.stream(ctx, prompt, onText)
+}
+
+// complete sends prompt as a user message, with responseFormat if it is set, and returns the reply
+func (c *openAIChat) complete(ctx context.Context, prompt string, responseFormat openai.ChatCompletionNewParamsResponseFormatUnion) (string, error) {
+	// Create chat completion request
+	chatCompletion, err := c.client.Chat.Completions.New(
+		ctx,
+		openai.ChatCompletionNewParams{
+			Messages:       c.messages(prompt),
+			Model:          c.model,
+			ResponseFormat: responseFormat,
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate text: %w", err)
+	}
+
+	// Extract the response content
+	if len(chatCompletion.Choices) == 0 {
+		return "", fmt.Errorf("no response choices returned from %s", c.name)
+	}
+
+	choice := chatCompletion.Choices[0]
+	if choice.Message.Refusal != "" {
+		return "", fmt.Errorf("model refused to respond: %s", choice.Message.Refusal)
+	}
+	if choice.Message.Content == "" {
+		return "", fmt.Errorf("no content in response message")
+	}
+
+	return choice.Message.Content, nil
+}
+
+// stream sends prompt as a user message and reads the reply as it is generated, passing each chunk
+// of content to onText
+func (c *openAIChat) stream(ctx context.Context, prompt string, onText func(chunk string)) (string, error) {
+	stream := c.client.Chat.Completions.NewStreaming(
+		ctx,
+		openai.ChatCompletionNewParams{
+			Messages: c.messages(prompt),
+			Model:    c.model,
+		},
+	)
+	defer stream.Close()
+
+	var content, refusal strings.Builder
+	for stream.Next() {
+		chunk := stream.Current()
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta
+		refusal.WriteString(delta.Refusal)
+		if delta.Content != "" {
+			content.WriteString(delta.Content)
+			onText(delta.Content)
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return "", fmt.Errorf("failed to stream text: %w", err)
+	}
+
+	if refusal.Len() > 0 {
+		return "", fmt.Errorf("model refused to respond: %s", refusal.String())
+	}
+	if content.Len() == 0 {
+		return "", fmt.Errorf("no content in response message")
+	}
+
+	return content.String(), nil
+}
+
+// messages returns the conversation for a single prompt
+func (c *openAIChat) messages(prompt string) []openai.ChatCompletionMessageParamUnion {
+	return []openai.ChatCompletionMessageParamUnion{
+		{
+			OfUser: &openai.ChatCompletionUserMessageParam{
+				Content: openai.ChatCompletionUserMessageParamContentUnion{
+					OfString: openai.String(prompt),
+				},
+			},
+		},
+	}
+}
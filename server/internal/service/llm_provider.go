@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	llmProviderOpenAI    = "openai"
+	llmProviderAnthropic = "anthropic"
+	llmProviderLocal     = "local"
+	llmProviderFake      = "fake"
+
+	// defaultLocalLLMBaseURL is where Ollama serves its OpenAI-compatible API
+	defaultLocalLLMBaseURL = "http://localhost:11434/v1"
+	defaultLocalLLMModel   = "llama3.1"
+)
+
+// ErrLLMNotConfigured is returned when the configured LLM provider is unknown or missing its API key
+var ErrLLMNotConfigured = errors.New("LLM provider is not configured")
+
+// LLMProvider generates text with a large language model. HookService depends on this rather than a
+// particular API, so the provider and model can be switched in config.
+type LLMProvider interface {
+	// GenerateText takes a prompt and returns the generated text response
+	GenerateText(ctx context.Context, prompt string) (string, error)
+	// Name identifies the provider and model, e.g. "openai/gpt-5-mini"
+	Name() string
+}
+
+// StructuredLLMProvider is an LLMProvider that can constrain its response to a JSON schema
+type StructuredLLMProvider interface {
+	LLMProvider
+	// GenerateJSON takes a prompt and returns a JSON response that follows schema
+	GenerateJSON(ctx context.Context, prompt string, schema *LLMSchema) (string, error)
+}
+
+// StreamingLLMProvider is an LLMProvider that can return its response as it is generated
+type StreamingLLMProvider interface {
+	LLMProvider
+	// StreamText takes a prompt, calls onText with each chunk of the response as it arrives and
+	// returns the whole response
+	StreamText(ctx context.Context, prompt string, onText func(chunk string)) (string, error)
+}
+
+// LLMSchema is a JSON schema for structured output. Providers enforce it strictly, so every object
+// in it must list all its properties as required and set additionalProperties to false.
+type LLMSchema struct {
+	Name   string
+	Schema map[string]any
+}
+
+// LLMConfig selects the LLM provider and model
+type LLMConfig struct {
+	Provider string // openai, anthropic, local or fake
+	Model    string // Empty for the provider's default
+	APIKey   string
+	BaseURL  string // API base URL for the local provider
+	// FakeResponsesPath is a JSON array of strings the fake provider replies with in turn; empty for its default replies
+	FakeResponsesPath string
+}
+
+// LLMConfigFromEnv reads the LLM config from LLM_PROVIDER (default openai), LLM_MODEL and LLM_BASE_URL.
+// The API key comes from OPENAI_API_KEY or ANTHROPIC_API_KEY for those providers and from the optional
+// LLM_API_KEY for local servers. LLM_FAKE_RESPONSES_PATH scripts the fake provider.
+func LLMConfigFromEnv() *LLMConfig {
+	config := &LLMConfig{
+		Provider:          os.Getenv("LLM_PROVIDER"),
+		Model:             os.Getenv("LLM_MODEL"),
+		BaseURL:           os.Getenv("LLM_BASE_URL"),
+		FakeResponsesPath: os.Getenv("LLM_FAKE_RESPONSES_PATH"),
+	}
+	if config.Provider == "" {
+		config.Provider = llmProviderOpenAI
+	}
+
+	switch config.Provider {
+	case llmProviderOpenAI:
+		config.APIKey = os.Getenv("OPENAI_API_KEY")
+	case llmProviderAnthropic:
+		config.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+	case llmProviderLocal:
+		config.APIKey = os.Getenv("LLM_API_KEY")
+	}
+	return config
+}
+
+// NewLLMProvider creates the LLM provider selected by config, returning ErrLLMNotConfigured if it
+// needs an API key that is not set
+func NewLLMProvider(config *LLMConfig) (LLMProvider, error) {
+	switch config.Provider {
+	case llmProviderOpenAI:
+		if config.APIKey == "" {
+			return nil, fmt.Errorf("%w: OPENAI_API_KEY environment variable is required for the openai provider", ErrLLMNotConfigured)
+		}
+		return NewOpenAIProvider(config.APIKey, config.Model), nil
+	case llmProviderAnthropic:
+		if config.APIKey == "" {
+			return nil, fmt.Errorf("%w: ANTHROPIC_API_KEY environment variable is required for the anthropic provider", ErrLLMNotConfigured)
+		}
+		return NewAnthropicProvider(config.APIKey, config.Model), nil
+	case llmProviderLocal:
+		baseURL := config.BaseURL
+		if baseURL == "" {
+			baseURL = defaultLocalLLMBaseURL
+		}
+		model := config.Model
+		if model == "" {
+			model = defaultLocalLLMModel
+		}
+		return NewLocalLLMProvider(baseURL, config.APIKey, model), nil
+	case llmProviderFake:
+		if config.FakeResponsesPath == "" {
+			return NewUnscriptedFakeLLMProvider(), nil
+		}
+		data, err := os.ReadFile(config.FakeResponsesPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fake LLM responses: %w", err)
+		}
+		var responses []string
+		if err := json.Unmarshal(data, &responses); err != nil {
+			return nil, fmt.Errorf("failed to parse fake LLM responses: %w", err)
+		}
+		return NewFakeLLMProvider(responses...), nil
+	default:
+		return nil, fmt.Errorf("%w: LLM_PROVIDER must be openai, anthropic, local or fake", ErrLLMNotConfigured)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/ethanhosier/reel-farm/internal/ffmpeg"
+)
+
+// maxOutputProfiles caps how many variants a single render request can ask for
+const maxOutputProfiles = 4
+
+// ErrInvalidOutputProfiles is returned when a render request asks for unknown or repeated output profiles
+var ErrInvalidOutputProfiles = errors.New("invalid output profiles")
+
+// aspectMode is how a source frame is fitted into a profile's frame
+type aspectMode string
+
+const (
+	aspectScale aspectMode = "scale" // Stretch to the frame
+	aspectPad   aspectMode = "pad"   // Fit inside the frame with black bars
+	aspectCrop  aspectMode = "crop"  // Fill the frame, cropping the overflow
+)
+
+// outputProfile describes how a platform variant is encoded
+type outputProfile struct {
+	Name         api.OutputProfile
+	Width        int
+	Height       int
+	Aspect       aspectMode
+	FrameRate    int
+	MaxBitrate   string
+	BufferSize   string
+	AudioBitrate string
+	SampleRate   int
+}
+
+// outputProfiles are the platform variants a render can produce
+var outputProfiles = map[api.OutputProfile]outputProfile{
+	api.Tiktok: {
+		Name:         api.Tiktok,
+		Width:        1080,
+		Height:       1920,
+		Aspect:       aspectCrop,
+		FrameRate:    30,
+		MaxBitrate:   "6M",
+		BufferSize:   "12M",
+		AudioBitrate: "128k",
+		SampleRate:   44100,
+	},
+	api.Reels: {
+		Name:         api.Reels,
+		Width:        1080,
+		Height:       1920,
+		Aspect:       aspectCrop,
+		FrameRate:    30,
+		MaxBitrate:   "5M",
+		BufferSize:   "10M",
+		AudioBitrate: "128k",
+		SampleRate:   48000,
+	},
+	api.Shorts: {
+		Name:         api.Shorts,
+		Width:        1080,
+		Height:       1920,
+		Aspect:       aspectPad,
+		FrameRate:    30,
+		MaxBitrate:   "8M",
+		BufferSize:   "16M",
+		AudioBitrate: "192k",
+		SampleRate:   48000,
+	},
+	api.Square: {
+		Name:         api.Square,
+		Width:        1080,
+		Height:       1080,
+		Aspect:       aspectPad,
+		FrameRate:    30,
+		MaxBitrate:   "5M",
+		BufferSize:   "10M",
+		AudioBitrate: "128k",
+		SampleRate:   44100,
+	},
+}
+
+// resolveOutputProfiles looks up the requested output profiles, rejecting unknown or repeated names
+func resolveOutputProfiles(names []api.OutputProfile) ([]outputProfile, error) {
+	if len(names) > maxOutputProfiles {
+		return nil, fmt.Errorf("%w: at most %d output profiles can be requested", ErrInvalidOutputProfiles, maxOutputProfiles)
+	}
+
+	profiles := make([]outputProfile, 0, len(names))
+	seen := make(map[api.OutputProfile]bool, len(names))
+	for _, name := range names {
+		profile, ok := outputProfiles[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown output profile %q", ErrInvalidOutputProfiles, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: output profile %q requested more than once", ErrInvalidOutputProfiles, name)
+		}
+		seen[name] = true
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// filename is the S3 object name of this profile's variant of a video
+func (p outputProfile) filename(videoID string) string {
+	return fmt.Sprintf("%s_%s.mp4", videoID, p.Name)
+}
+
+// videoFilters fit the source frame into the profile's frame and set its frame rate
+func (p outputProfile) videoFilters() ffmpeg.Chain {
+	var fit ffmpeg.Chain
+	switch p.Aspect {
+	case aspectPad:
+		fit = ffmpeg.Chain{ffmpeg.ScaleToFit(p.Width, p.Height), ffmpeg.PadCentered(p.Width, p.Height, "black")}
+	case aspectCrop:
+		fit = ffmpeg.Chain{ffmpeg.ScaleToFill(p.Width, p.Height), ffmpeg.CropCentered(p.Width, p.Height)}
+	default:
+		fit = ffmpeg.Chain{ffmpeg.Scale(p.Width, p.Height)}
+	}
+	return append(fit, ffmpeg.SetSAR(1), ffmpeg.FPS(p.FrameRate))
+}
+
+// encode sets the profile's video and audio encoding options on cmd
+func (p outputProfile) encode(cmd *ffmpeg.Command) {
+	cmd.VideoCodec("libx264").
+		Preset("veryfast").
+		CRF(23).
+		MaxRate(p.MaxBitrate, p.BufferSize).
+		PixelFormat("yuv420p").
+		FastStart().
+		AudioCodec("aac").
+		AudioBitrate(p.AudioBitrate).
+		AudioSampleRate(p.SampleRate).
+		AudioChannels(2)
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/ethanhosier/reel-farm/internal/textlayout"
+)
+
+const (
+	defaultFontSize          = 36
+	defaultFontColor         = "#FFFFFF"
+	defaultStrokeColor       = "#000000"
+	defaultStrokeWidth       = 3
+	defaultBackgroundPadding = 12
+	defaultMaxLines          = 6
+
+	// overlayMarginRatio is the share of the frame width kept clear on each side of the text, and
+	// overlayMarginYRatio the share of the frame height kept clear above or below it
+	overlayMarginRatio  = 0.08
+	overlayMarginYRatio = 0.1
+)
+
+// ErrInvalidOverlayStyle is returned when a requested overlay style fails validation
+var ErrInvalidOverlayStyle = errors.New("invalid overlay style")
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// overlayStyle is a validated api.OverlayStyle with defaults applied
+type overlayStyle struct {
+	FontFamily        string
+	FontPath          string
+	FontSize          int
+	MaxLines          int
+	FontColor         string
+	StrokeColor       string
+	StrokeWidth       int
+	BackgroundColor   string // empty for no background box
+	BackgroundPadding int
+	VerticalAnchor    api.OverlayStyleVerticalAnchor
+	Y                 *int
+	Alignment         api.OverlayStyleAlignment
+	BrandFont         *brandKitFont `json:",omitempty"` // Set when the font comes from a brand kit rather than the font library
+}
+
+// resolveOverlayStyle validates a requested style against the font library and fills in defaults
+func resolveOverlayStyle(style *api.OverlayStyle, fonts *FontLibrary) (*overlayStyle, error) {
+	if style == nil {
+		style = &api.OverlayStyle{}
+	}
+
+	fontFamily := defaultFontFamily
+	if style.FontFamily != nil {
+		fontFamily = *style.FontFamily
+	}
+	fontPath, ok := fonts.Path(fontFamily)
+	if !ok {
+		return nil, fmt.Errorf("%w: font_family must be one of %s", ErrInvalidOverlayStyle, strings.Join(fonts.Families(), ", "))
+	}
+
+	resolved := &overlayStyle{
+		FontFamily:        fontFamily,
+		FontPath:          fontPath,
+		FontSize:          defaultFontSize,
+		MaxLines:          defaultMaxLines,
+		FontColor:         defaultFontColor,
+		StrokeColor:       defaultStrokeColor,
+		StrokeWidth:       defaultStrokeWidth,
+		BackgroundPadding: defaultBackgroundPadding,
+		VerticalAnchor:    api.OverlayStyleVerticalAnchorCenter,
+		Y:                 style.Y,
+		Alignment:         api.OverlayStyleAlignmentCenter,
+	}
+
+	if style.FontSize != nil {
+		if *style.FontSize < 12 || *style.FontSize > 200 {
+			return nil, fmt.Errorf("%w: font_size must be between 12 and 200", ErrInvalidOverlayStyle)
+		}
+		resolved.FontSize = *style.FontSize
+	}
+
+	if style.MaxLines != nil {
+		if *style.MaxLines < 1 || *style.MaxLines > 20 {
+			return nil, fmt.Errorf("%w: max_lines must be between 1 and 20", ErrInvalidOverlayStyle)
+		}
+		resolved.MaxLines = *style.MaxLines
+	}
+
+	if style.FontColor != nil {
+		if !hexColorPattern.MatchString(*style.FontColor) {
+			return nil, fmt.Errorf("%w: font_color must be #RRGGBB or #RRGGBBAA", ErrInvalidOverlayStyle)
+		}
+		resolved.FontColor = *style.FontColor
+	}
+
+	if style.StrokeColor != nil {
+		if !hexColorPattern.MatchString(*style.StrokeColor) {
+			return nil, fmt.Errorf("%w: stroke_color must be #RRGGBB or #RRGGBBAA", ErrInvalidOverlayStyle)
+		}
+		resolved.StrokeColor = *style.StrokeColor
+	}
+
+	if style.StrokeWidth != nil {
+		if *style.StrokeWidth < 0 || *style.StrokeWidth > 20 {
+			return nil, fmt.Errorf("%w: stroke_width must be between 0 and 20", ErrInvalidOverlayStyle)
+		}
+		resolved.StrokeWidth = *style.StrokeWidth
+	}
+
+	if style.Background != nil {
+		if !hexColorPattern.MatchString(style.Background.Color) {
+			return nil, fmt.Errorf("%w: background.color must be #RRGGBB or #RRGGBBAA", ErrInvalidOverlayStyle)
+		}
+		resolved.BackgroundColor = style.Background.Color
+
+		if style.Background.Padding != nil {
+			if *style.Background.Padding < 0 || *style.Background.Padding > 100 {
+				return nil, fmt.Errorf("%w: background.padding must be between 0 and 100", ErrInvalidOverlayStyle)
+			}
+			resolved.BackgroundPadding = *style.Background.Padding
+		}
+	}
+
+	if style.VerticalAnchor != nil {
+		switch *style.VerticalAnchor {
+		case api.OverlayStyleVerticalAnchorTop, api.OverlayStyleVerticalAnchorCenter, api.OverlayStyleVerticalAnchorBottom:
+			resolved.VerticalAnchor = *style.VerticalAnchor
+		default:
+			return nil, fmt.Errorf("%w: vertical_anchor must be top, center or bottom", ErrInvalidOverlayStyle)
+		}
+	}
+
+	if style.Y != nil && *style.Y < 0 {
+		return nil, fmt.Errorf("%w: y must not be negative", ErrInvalidOverlayStyle)
+	}
+
+	if style.Alignment != nil {
+		switch *style.Alignment {
+		case api.OverlayStyleAlignmentLeft, api.OverlayStyleAlignmentCenter, api.OverlayStyleAlignmentRight:
+			resolved.Alignment = *style.Alignment
+		default:
+			return nil, fmt.Errorf("%w: alignment must be left, center or right", ErrInvalidOverlayStyle)
+		}
+	}
+
+	return resolved, nil
+}
+
+// resolveRenderStyle resolves the overlay style of a render, in the brand kit's font and colours where
+// the requested style leaves them unset
+func (s *AIAvatarService) resolveRenderStyle(options *RenderOptions) (*overlayStyle, error) {
+	style, err := resolveOverlayStyle(options.style(), s.fonts)
+	if err != nil {
+		return nil, err
+	}
+
+	kit := options.BrandKit
+	if kit != nil && kit.Font != nil && (options.Style == nil || options.Style.FontFamily == nil) {
+		style.FontFamily = kit.Font.Family
+		style.FontPath = ""
+		style.BrandFont = kit.Font
+	}
+	return style, nil
+}
+
+// renderOptions returns the options for drawing text in this style
+func (st *overlayStyle) renderOptions() *textlayout.RenderOptions {
+	opts := &textlayout.RenderOptions{
+		FontSize:    st.FontSize,
+		LineSpacing: st.lineSpacing(),
+		Align:       textlayout.AlignCenter,
+		Color:       parseHexColor(st.FontColor),
+		StrokeColor: parseHexColor(st.StrokeColor),
+		StrokeWidth: st.StrokeWidth,
+	}
+
+	switch st.Alignment {
+	case api.OverlayStyleAlignmentLeft:
+		opts.Align = textlayout.AlignLeft
+	case api.OverlayStyleAlignmentRight:
+		opts.Align = textlayout.AlignRight
+	}
+
+	if st.BackgroundColor != "" {
+		opts.BackgroundColor = parseHexColor(st.BackgroundColor)
+		opts.BackgroundPadding = st.BackgroundPadding
+	}
+
+	return opts
+}
+
+// minFontSize is the smallest size the text may shrink to when it runs past MaxLines
+func (st *overlayStyle) minFontSize() int {
+	return max(12, st.FontSize/2)
+}
+
+// maxTextWidth is the width in pixels a line may take in a frame, leaving room for the margins,
+// the outline and any background box
+func (st *overlayStyle) maxTextWidth(frameWidth int) float64 {
+	width := float64(frameWidth) * (1 - 2*overlayMarginRatio)
+	width -= 2 * float64(st.StrokeWidth)
+	if st.BackgroundColor != "" {
+		width -= 2 * float64(st.BackgroundPadding)
+	}
+	return width
+}
+
+// lineSpacing scales the original 16px spacing at 36px text to the chosen font size
+func (st *overlayStyle) lineSpacing() int {
+	return st.FontSize * 16 / defaultFontSize
+}
+
+// overlayPosition places a rendered text image of the given size in the frame from the alignment
+// and the custom offset or anchor. The margins and offset apply to the text itself, not to the
+// margin the image leaves around it for the stroke and background box.
+func (st *overlayStyle) overlayPosition(frameWidth, frameHeight, width, height, margin int) (x, y int) {
+	marginX := int(math.Round(float64(frameWidth) * overlayMarginRatio))
+	switch st.Alignment {
+	case api.OverlayStyleAlignmentLeft:
+		x = marginX - margin
+	case api.OverlayStyleAlignmentRight:
+		x = frameWidth - marginX - width + margin
+	default:
+		x = (frameWidth - width) / 2
+	}
+
+	marginY := int(math.Round(float64(frameHeight) * overlayMarginYRatio))
+	switch {
+	case st.Y != nil:
+		y = *st.Y - margin
+	case st.VerticalAnchor == api.OverlayStyleVerticalAnchorTop:
+		y = marginY - margin
+	case st.VerticalAnchor == api.OverlayStyleVerticalAnchorBottom:
+		y = frameHeight - marginY - height + margin
+	default:
+		y = (frameHeight - height) / 2
+	}
+
+	return x, y
+}
+
+// parseHexColor converts a validated #RRGGBB or #RRGGBBAA colour to a color.NRGBA
+func parseHexColor(hex string) color.NRGBA {
+	value, _ := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if len(hex) == 7 {
+		return color.NRGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 0xFF}
+	}
+	return color.NRGBA{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/ethanhosier/reel-farm/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	maxPromptTemplateNameLength         = 100
+	maxPromptTemplateInstructionsLength = 10000
+)
+
+var (
+	// ErrPromptTemplateNotFound is returned when a prompt template does not exist, or is inactive when
+	// generating hooks with it
+	ErrPromptTemplateNotFound = errors.New("prompt template not found")
+	// ErrInvalidPromptTemplate is returned when a prompt template's name, style or instructions fail validation
+	ErrInvalidPromptTemplate = errors.New("invalid prompt template")
+)
+
+// PromptTemplateService manages the prompt templates admins write for hook generation
+type PromptTemplateService struct {
+	repo *repository.PromptTemplateRepository
+}
+
+// NewPromptTemplateService creates a new prompt template service
+func NewPromptTemplateService(repo *repository.PromptTemplateRepository) *PromptTemplateService {
+	return &PromptTemplateService{
+		repo: repo,
+	}
+}
+
+// PromptTemplate is a prompt template with its latest version
+type PromptTemplate struct {
+	Template *db.HookPromptTemplate
+	Version  *db.HookPromptTemplateVersion
+}
+
+// GetPromptTemplates gets prompt templates by name with their latest versions, only the active ones if
+// activeOnly is set
+func (s *PromptTemplateService) GetPromptTemplates(ctx context.Context, activeOnly bool) ([]*PromptTemplate, error) {
+	templates, err := s.repo.GetPromptTemplates(ctx, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return []*PromptTemplate{}, nil
+	}
+
+	templateIDs := make([]uuid.UUID, len(templates))
+	for i, template := range templates {
+		templateIDs[i] = template.ID
+	}
+	versions, err := s.repo.GetLatestVersions(ctx, templateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	versionsByTemplate := make(map[uuid.UUID]*db.HookPromptTemplateVersion, len(versions))
+	for _, version := range versions {
+		versionsByTemplate[uuid.UUID(version.TemplateID.Bytes)] = version
+	}
+
+	promptTemplates := make([]*PromptTemplate, len(templates))
+	for i, template := range templates {
+		promptTemplates[i] = &PromptTemplate{
+			Template: template,
+			Version:  versionsByTemplate[template.ID],
+		}
+	}
+	return promptTemplates, nil
+}
+
+// GetPromptTemplate gets a prompt template with its latest version
+func (s *PromptTemplateService) GetPromptTemplate(ctx context.Context, templateID uuid.UUID) (*PromptTemplate, error) {
+	template, err := s.repo.GetPromptTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, ErrPromptTemplateNotFound
+	}
+
+	version, err := s.repo.GetLatestVersion(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return &PromptTemplate{Template: template, Version: version}, nil
+}
+
+// GetPromptTemplateVersions gets every version of a prompt template, newest first
+func (s *PromptTemplateService) GetPromptTemplateVersions(ctx context.Context, templateID uuid.UUID) ([]*db.HookPromptTemplateVersion, error) {
+	template, err := s.repo.GetPromptTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, ErrPromptTemplateNotFound
+	}
+	return s.repo.GetVersions(ctx, templateID)
+}
+
+// CreatePromptTemplate validates and creates a prompt template at version 1, written by adminID
+func (s *PromptTemplateService) CreatePromptTemplate(ctx context.Context, adminID uuid.UUID, req *api.PromptTemplateRequest) (*PromptTemplate, error) {
+	name, active, err := validatePromptTemplateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	template, version, err := s.repo.CreatePromptTemplate(ctx, &db.CreateHookPromptTemplateParams{
+		Name:   name,
+		Style:  string(req.Style),
+		Active: active,
+	}, req.Instructions, adminID)
+	if err != nil {
+		return nil, err
+	}
+	return &PromptTemplate{Template: template, Version: version}, nil
+}
+
+// UpdatePromptTemplate validates and replaces a prompt template's name, style, instructions and whether
+// it is active. New instructions become a new version written by adminID; the earlier versions are kept
+// for the hooks generated with them.
+func (s *PromptTemplateService) UpdatePromptTemplate(ctx context.Context, adminID, templateID uuid.UUID, req *api.PromptTemplateRequest) (*PromptTemplate, error) {
+	name, active, err := validatePromptTemplateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	template, version, err := s.repo.UpdatePromptTemplate(ctx, &db.UpdateHookPromptTemplateParams{
+		ID:     templateID,
+		Name:   name,
+		Style:  string(req.Style),
+		Active: active,
+	}, req.Instructions, adminID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, ErrPromptTemplateNotFound
+	}
+	return &PromptTemplate{Template: template, Version: version}, nil
+}
+
+// activeVersion gets the latest version of a prompt template for hook generation, which may only use
+// active templates
+func (s *PromptTemplateService) activeVersion(ctx context.Context, templateID uuid.UUID) (*db.HookPromptTemplateVersion, error) {
+	template, err := s.repo.GetPromptTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil || !template.Active {
+		return nil, ErrPromptTemplateNotFound
+	}
+	return s.repo.GetLatestVersion(ctx, templateID)
+}
+
+// version gets a prompt template version whether or not its template is still active, for refining the
+// hooks generated with it
+func (s *PromptTemplateService) version(ctx context.Context, versionID uuid.UUID) (*db.HookPromptTemplateVersion, error) {
+	version, err := s.repo.GetVersionByID(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil {
+		return nil, fmt.Errorf("prompt template version %s not found", versionID)
+	}
+	return version, nil
+}
+
+// validatePromptTemplateRequest checks a prompt template request, returning the trimmed name and whether
+// the template is active, which defaults to true
+func validatePromptTemplateRequest(req *api.PromptTemplateRequest) (string, bool, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", false, fmt.Errorf("%w: name is required", ErrInvalidPromptTemplate)
+	}
+	if utf8.RuneCountInString(name) > maxPromptTemplateNameLength {
+		return "", false, fmt.Errorf("%w: name must be at most %d characters", ErrInvalidPromptTemplate, maxPromptTemplateNameLength)
+	}
+
+	switch req.Style {
+	case api.Listicle, api.Pov, api.Question, api.Controversial, api.Storytime:
+	default:
+		return "", false, fmt.Errorf("%w: style must be listicle, pov, question, controversial or storytime", ErrInvalidPromptTemplate)
+	}
+
+	if strings.TrimSpace(req.Instructions) == "" {
+		return "", false, fmt.Errorf("%w: instructions are required", ErrInvalidPromptTemplate)
+	}
+	if utf8.RuneCountInString(req.Instructions) > maxPromptTemplateInstructionsLength {
+		return "", false, fmt.Errorf("%w: instructions must be at most %d characters", ErrInvalidPromptTemplate, maxPromptTemplateInstructionsLength)
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	return name, active, nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// maxRenderBatchSize caps how many renders one batch can fan out into
+	maxRenderBatchSize = 100
+	// maxOverlayTextLength is the longest overlay text, in characters, a render accepts
+	maxOverlayTextLength = 500
+)
+
+var (
+	// ErrInvalidRenderBatch is returned when a render batch request fails validation
+	ErrInvalidRenderBatch = errors.New("invalid render batch")
+	// ErrRenderBatchInputNotFound is returned when a batch names a hook or AI avatar video that does not exist or belongs to another user
+	ErrRenderBatchInputNotFound = errors.New("hook or AI avatar video not found")
+	// ErrRenderBatchNotFound is returned when a render batch does not exist or belongs to another user
+	ErrRenderBatchNotFound = errors.New("render batch not found")
+)
+
+// RenderBatchRequest asks for every overlay text (hooks first, then raw texts) to be rendered over
+// every AI avatar video with the same render options
+type RenderBatchRequest struct {
+	AIAvatarVideoIDs []uuid.UUID
+	HookIDs          []uuid.UUID
+	Texts            []string
+	Options          *RenderOptions
+}
+
+// RenderBatchSummary is a render batch with how many of its renders are in each status
+type RenderBatchSummary struct {
+	Batch           *db.RenderBatch
+	ProcessingCount int
+	CompletedCount  int
+	FailedCount     int
+	CancelledCount  int
+}
+
+// Status is processing while any render is unfinished, then completed, failed or partially completed
+// depending on how many renders succeeded
+func (b *RenderBatchSummary) Status() api.RenderBatchStatus {
+	switch {
+	case b.ProcessingCount > 0:
+		return api.RenderBatchStatusProcessing
+	case b.CompletedCount == int(b.Batch.RenderCount):
+		return api.RenderBatchStatusCompleted
+	case b.CompletedCount == 0:
+		return api.RenderBatchStatusFailed
+	default:
+		return api.RenderBatchStatusPartiallyCompleted
+	}
+}
+
+// newRenderBatchSummary counts the statuses of a batch's videos
+func newRenderBatchSummary(batch *db.RenderBatch, videos []*db.UserGeneratedVideo) *RenderBatchSummary {
+	summary := &RenderBatchSummary{Batch: batch}
+	for _, video := range videos {
+		status := "processing"
+		if video.Status != nil {
+			status = *video.Status
+		}
+		switch status {
+		case "completed":
+			summary.CompletedCount++
+		case "failed":
+			summary.FailedCount++
+		case "cancelled":
+			summary.CancelledCount++
+		default:
+			summary.ProcessingCount++
+		}
+	}
+	return summary
+}
+
+// EnqueueRenderBatch validates a batch request, charges credits for every render in it up front and
+// queues one user-generated video per overlay text and AI avatar video pair. Either the whole batch is
+// queued or nothing is.
+func (s *RenderJobService) EnqueueRenderBatch(ctx context.Context, userID uuid.UUID, req *RenderBatchRequest) (*RenderBatchSummary, []*db.UserGeneratedVideo, error) {
+	if len(req.AIAvatarVideoIDs) == 0 {
+		return nil, nil, fmt.Errorf("%w: at least one AI avatar video is required", ErrInvalidRenderBatch)
+	}
+	if len(req.HookIDs)+len(req.Texts) == 0 {
+		return nil, nil, fmt.Errorf("%w: at least one hook or text is required", ErrInvalidRenderBatch)
+	}
+	renderCount := (len(req.HookIDs) + len(req.Texts)) * len(req.AIAvatarVideoIDs)
+	if renderCount > maxRenderBatchSize {
+		return nil, nil, fmt.Errorf("%w: batch would create %d renders, the limit is %d", ErrInvalidRenderBatch, renderCount, maxRenderBatchSize)
+	}
+
+	texts, err := s.renderBatchTexts(ctx, userID, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.brandKitService.ApplyToRenderOptions(ctx, userID, req.Options); err != nil {
+		return nil, nil, err
+	}
+	for _, text := range texts {
+		if err := s.aiAvatarService.ValidateRenderOptions(req.Options, text); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sources, err := s.renderBatchSources(ctx, req.AIAvatarVideoIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	renderOptions, err := json.Marshal(req.Options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode render options: %w", err)
+	}
+
+	watermarked, err := s.watermarksUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Fan out text-major, so the batch lists each hook over every avatar video in turn
+	batchID := uuid.New()
+	videoParams := make([]*db.CreateUserGeneratedVideoParams, 0, renderCount)
+	creditRequestIDs := make([]string, 0, renderCount)
+	for _, text := range texts {
+		for _, source := range sources {
+			params, err := s.newRenderParams(userID, source, text, req.Options, renderOptions, watermarked)
+			if err != nil {
+				return nil, nil, err
+			}
+			params.RenderBatchID = pgtype.UUID{Bytes: batchID, Valid: true}
+			videoParams = append(videoParams, params)
+			creditRequestIDs = append(creditRequestIDs, renderCreditRequestID(params.ID))
+		}
+	}
+
+	batch, videos, err := s.renderJobRepo.CreateRenderBatchWithJobs(ctx, &db.CreateRenderBatchParams{
+		ID:             batchID,
+		UserID:         pgtype.UUID{Bytes: userID, Valid: true},
+		RenderCount:    int32(renderCount),
+		CreditsCharged: int32(renderCount * renderCreditCost),
+		RenderOptions:  renderOptions,
+	}, videoParams, creditRequestIDs, renderCreditCost)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to enqueue render batch: %w", err)
+	}
+
+	return newRenderBatchSummary(batch, videos), videos, nil
+}
+
+// renderBatchTexts resolves a batch's overlay texts: the text of each hook, in the order given,
+// followed by the raw texts
+func (s *RenderJobService) renderBatchTexts(ctx context.Context, userID uuid.UUID, req *RenderBatchRequest) ([]string, error) {
+	texts := make([]string, 0, len(req.HookIDs)+len(req.Texts))
+
+	if len(req.HookIDs) > 0 {
+		hooks, err := s.hookRepo.GetHooksByIDs(ctx, req.HookIDs, userID)
+		if err != nil {
+			return nil, err
+		}
+		hookTexts := make(map[uuid.UUID]string, len(hooks))
+		for _, hook := range hooks {
+			hookTexts[hook.ID] = hook.HookText
+		}
+		for _, hookID := range req.HookIDs {
+			text, ok := hookTexts[hookID]
+			if !ok {
+				return nil, fmt.Errorf("%w: hook %s", ErrRenderBatchInputNotFound, hookID)
+			}
+			texts = append(texts, text)
+		}
+	}
+
+	for i, text := range req.Texts {
+		if strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("%w: texts[%d] is empty", ErrInvalidRenderBatch, i)
+		}
+		if utf8.RuneCountInString(text) > maxOverlayTextLength {
+			return nil, fmt.Errorf("%w: texts[%d] must be at most %d characters", ErrInvalidRenderBatch, i, maxOverlayTextLength)
+		}
+		texts = append(texts, text)
+	}
+
+	return texts, nil
+}
+
+// renderBatchSources looks up a batch's AI avatar videos, rejecting repeats and sources the render pipeline cannot use
+func (s *RenderJobService) renderBatchSources(ctx context.Context, ids []uuid.UUID) ([]*db.AiAvatarVideo, error) {
+	sources := make([]*db.AiAvatarVideo, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: AI avatar video %s requested more than once", ErrInvalidRenderBatch, id)
+		}
+		seen[id] = true
+
+		source, err := s.aiAvatarService.GetVideoByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: AI avatar video %s", ErrRenderBatchInputNotFound, id)
+		}
+		if source.UnsupportedReason != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrUnsupportedSource, source.Title, *source.UnsupportedReason)
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// GetRenderBatch gets one of a user's render batches with every video in it
+func (s *RenderJobService) GetRenderBatch(ctx context.Context, userID, batchID uuid.UUID) (*RenderBatchSummary, []*db.UserGeneratedVideo, error) {
+	batch, err := s.renderJobRepo.GetRenderBatchByID(ctx, batchID)
+	if err != nil || uuid.UUID(batch.UserID.Bytes) != userID {
+		return nil, nil, ErrRenderBatchNotFound
+	}
+
+	videos, err := s.renderJobRepo.GetUserGeneratedVideosByRenderBatchID(ctx, batchID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return newRenderBatchSummary(batch, videos), videos, nil
+}
+
+// GetRenderBatches gets a page of a user's render batches, newest first
+func (s *RenderJobService) GetRenderBatches(ctx context.Context, userID uuid.UUID, limit int32, offset int32) ([]*RenderBatchSummary, error) {
+	rows, err := s.renderJobRepo.GetRenderBatchSummariesByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*RenderBatchSummary, len(rows))
+	for i, row := range rows {
+		summaries[i] = &RenderBatchSummary{
+			Batch:           &row.RenderBatch,
+			ProcessingCount: int(row.ProcessingCount),
+			CompletedCount:  int(row.CompletedCount),
+			FailedCount:     int(row.FailedCount),
+			CancelledCount:  int(row.CancelledCount),
+		}
+	}
+	return summaries, nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/ethanhosier/reel-farm/internal/ffmpeg"
+)
+
+const (
+	// maxRenderAttempts is how many times a render is tried before a transient failure is given up on
+	maxRenderAttempts = 4
+	// renderRetryBaseDelay is the wait before the first retry, doubled for each retry after it
+	renderRetryBaseDelay = 30 * time.Second
+	renderRetryMaxDelay  = 10 * time.Minute
+
+	// ffmpegInterruptedExitCode is what ffmpeg exits with when it stops on SIGTERM or SIGINT
+	ffmpegInterruptedExitCode = 255
+
+	renderRetriesExhaustedMessage = "Rendering kept failing because of a temporary problem on our side. Any credits it used have been refunded, please try again later."
+	renderEncodeFailedMessage     = "The video could not be encoded with these settings. Any credits it used have been refunded, try a different avatar video or different render options."
+)
+
+// renderFailure is a classified render error
+type renderFailure struct {
+	// Transient is set for errors a later attempt may not hit, such as S3 and network errors and
+	// ffmpeg being killed for running out of memory. Anything else fails the render straight away.
+	Transient bool
+	// Message explains the failure to the user, and is only set for permanent failures
+	Message string
+}
+
+// classifyRenderError decides whether a failed render is worth retrying. Bad input and render options
+// that no longer validate are permanent; errors it does not recognise are treated as transient, so they
+// get a few more attempts before the render is failed.
+func classifyRenderError(err error) *renderFailure {
+	switch {
+	case errors.Is(err, ErrUnsupportedSource):
+		reason := err.Error()
+		if i := strings.Index(reason, ErrUnsupportedSource.Error()+": "); i >= 0 {
+			reason = reason[i+len(ErrUnsupportedSource.Error())+2:]
+		}
+		return &renderFailure{Message: fmt.Sprintf("The avatar video can't be rendered (%s). Any credits it used have been refunded.", reason)}
+	case errors.Is(err, ErrInvalidOverlayStyle),
+		errors.Is(err, ErrInvalidCaptionOptions),
+		errors.Is(err, ErrInvalidOutputProfiles),
+		errors.Is(err, ErrInvalidSubtitleMode),
+		errors.Is(err, ErrInvalidClipOptions),
+		errors.Is(err, ErrInvalidEndCard):
+		return &renderFailure{Message: fmt.Sprintf("The render options are no longer valid (%v). Any credits it used have been refunded.", err)}
+	}
+
+	var opErr *smithy.OperationError
+	var netErr net.Error
+	if errors.As(err, &opErr) || errors.As(err, &netErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ENOMEM) ||
+		errors.Is(err, syscall.ENOSPC) {
+		return &renderFailure{Transient: true}
+	}
+
+	// ffmpeg killed by a signal was most likely taken out by the OOM killer; any other exit means it
+	// could not decode the input or encode it with the requested settings, which will not change
+	var ffmpegErr *ffmpeg.Error
+	if errors.As(err, &ffmpegErr) {
+		exitCode := ffmpegErr.ExitCode()
+		if exitCode == -1 || exitCode == ffmpegInterruptedExitCode {
+			return &renderFailure{Transient: true}
+		}
+		return &renderFailure{Message: renderEncodeFailedMessage}
+	}
+
+	return &renderFailure{Transient: true}
+}
+
+// renderRetryDelay is how long to wait before trying a render again after its attempts-th attempt failed
+func renderRetryDelay(attempts int32) time.Duration {
+	delay := renderRetryBaseDelay
+	for i := int32(1); i < attempts && delay < renderRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, renderRetryMaxDelay)
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/ethanhosier/reel-farm/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	renderJobPollInterval           = 2 * time.Second
+	renderJobRequeueInterval        = time.Minute
+	renderJobProgressUpdateInterval = 2 * time.Second
+	// renderJobHeartbeatInterval is how often a worker checks in on the job it is running, however long
+	// the render takes; a job that has not checked in for renderJobStaleAfter is treated as abandoned
+	renderJobHeartbeatInterval = time.Minute
+	renderJobStaleAfter        = 30 * time.Minute
+
+	renderCreditCost = 10
+)
+
+var (
+	// ErrRenderNotFound is returned when a user-generated video does not exist or belongs to another user
+	ErrRenderNotFound = errors.New("user-generated video not found")
+	// ErrRenderNotCancellable is returned when a render has already finished
+	ErrRenderNotCancellable = errors.New("render is not queued or running")
+)
+
+// RenderJobService queues user-generated video renders and runs them in the background
+type RenderJobService struct {
+	renderJobRepo   *repository.RenderJobRepository
+	hookRepo        *repository.HookRepository
+	userRepo        *repository.UserRepository
+	aiAvatarService *AIAvatarService
+	brandKitService *BrandKitService
+	concurrency     int
+
+	// running holds the cancel functions of renders in progress on this instance, keyed by video ID
+	mu      sync.Mutex
+	running map[uuid.UUID]context.CancelFunc
+}
+
+// NewRenderJobService creates a new render job service that runs up to concurrency renders at once
+func NewRenderJobService(renderJobRepo *repository.RenderJobRepository, hookRepo *repository.HookRepository, userRepo *repository.UserRepository, aiAvatarService *AIAvatarService, brandKitService *BrandKitService, concurrency int) *RenderJobService {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &RenderJobService{
+		renderJobRepo:   renderJobRepo,
+		hookRepo:        hookRepo,
+		userRepo:        userRepo,
+		aiAvatarService: aiAvatarService,
+		brandKitService: brandKitService,
+		concurrency:     concurrency,
+		running:         make(map[uuid.UUID]context.CancelFunc),
+	}
+}
+
+// renderCreditRequestID is the credit transaction key for a video's render hold
+func renderCreditRequestID(videoID uuid.UUID) string {
+	return fmt.Sprintf("render:%s", videoID)
+}
+
+// EnqueueRender applies the brand kit and validates the render options, creates a user-generated
+// video in the processing state and queues it for rendering
+func (s *RenderJobService) EnqueueRender(ctx context.Context, userID, aiAvatarVideoID uuid.UUID, overlayText string, options *RenderOptions) (*db.UserGeneratedVideo, error) {
+	if err := s.brandKitService.ApplyToRenderOptions(ctx, userID, options); err != nil {
+		return nil, err
+	}
+	if err := s.aiAvatarService.ValidateRenderOptions(options, overlayText); err != nil {
+		return nil, err
+	}
+
+	source, err := s.aiAvatarService.GetVideoByID(ctx, aiAvatarVideoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI avatar video: %w", err)
+	}
+	if source.UnsupportedReason != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSource, *source.UnsupportedReason)
+	}
+
+	renderOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode render options: %w", err)
+	}
+
+	watermarked, err := s.watermarksUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := s.newRenderParams(userID, source, overlayText, options, renderOptions, watermarked)
+	if err != nil {
+		return nil, err
+	}
+
+	video, _, err := s.renderJobRepo.CreateUserGeneratedVideoWithJob(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue render: %w", err)
+	}
+
+	return video, nil
+}
+
+// RerenderVideo queues a fresh copy of one of a user's videos with the same source, text and render
+// options. The watermark follows the user's current plan, so a user who has upgraded gets a clean copy,
+// and a brand kit is applied as it is now.
+func (s *RenderJobService) RerenderVideo(ctx context.Context, userID, videoID uuid.UUID) (*db.UserGeneratedVideo, error) {
+	video, err := s.aiAvatarService.GetUserGeneratedVideoByID(ctx, videoID)
+	if err != nil || uuid.UUID(video.UserID.Bytes) != userID {
+		return nil, ErrRenderNotFound
+	}
+
+	options, err := renderOptionsFromVideo(video)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.EnqueueRender(ctx, userID, uuid.UUID(video.AiAvatarVideoID.Bytes), video.OverlayText, options)
+}
+
+// watermarksUser looks up a user's plan to decide whether the renders they queue are watermarked
+func (s *RenderJobService) watermarksUser(ctx context.Context, userID uuid.UUID) (bool, error) {
+	userAccount, err := s.userRepo.GetUserAccount(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return s.aiAvatarService.WatermarksPlan(userAccount.Plan), nil
+}
+
+// newRenderParams builds the record for a render of overlayText over source in the processing state,
+// fingerprinted so a worker can reuse an identical earlier render instead of re-encoding
+func (s *RenderJobService) newRenderParams(userID uuid.UUID, source *db.AiAvatarVideo, overlayText string, options *RenderOptions, renderOptions []byte, watermarked bool) (*db.CreateUserGeneratedVideoParams, error) {
+	if err := s.aiAvatarService.ValidateClip(options, source); err != nil {
+		return nil, err
+	}
+
+	renderSpecHash, err := s.aiAvatarService.RenderSpecHash(source, overlayText, options, watermarked)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filenames are derived from the record ID and only point at real objects once the render completes
+	videoID := uuid.New()
+	status := "processing"
+
+	return &db.CreateUserGeneratedVideoParams{
+		ID:                     videoID,
+		UserID:                 pgtype.UUID{Bytes: userID, Valid: true},
+		AiAvatarVideoID:        pgtype.UUID{Bytes: source.ID, Valid: true},
+		OverlayText:            overlayText,
+		GeneratedVideoFilename: fmt.Sprintf("%s.mp4", videoID.String()),
+		ThumbnailFilename:      fmt.Sprintf("%s.jpg", videoID.String()),
+		Status:                 &status,
+		RenderOptions:          renderOptions,
+		RenderSpecHash:         &renderSpecHash,
+		Watermarked:            watermarked,
+	}, nil
+}
+
+// Start launches the background workers; they stop when ctx is cancelled
+func (s *RenderJobService) Start(ctx context.Context) {
+	for i := 0; i < s.concurrency; i++ {
+		go s.runWorker(ctx, i)
+	}
+	go s.requeueStaleJobs(ctx)
+
+	log.Printf("🎞️ Started %d render worker(s)", s.concurrency)
+}
+
+// runWorker repeatedly claims and runs jobs until ctx is cancelled
+func (s *RenderJobService) runWorker(ctx context.Context, worker int) {
+	ticker := time.NewTicker(renderJobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain the queue before waiting for the next tick
+		for {
+			job, err := s.renderJobRepo.ClaimNextRenderJob(ctx)
+			if err != nil {
+				log.Printf("❌ Render worker %d failed to claim job: %v", worker, err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			s.runJob(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runJob renders the job's video and records the outcome on both the job and the video
+func (s *RenderJobService) runJob(ctx context.Context, job *db.RenderJob) {
+	videoID := uuid.UUID(job.UserGeneratedVideoID.Bytes)
+	log.Printf("🎬 Render job %s started for video %s (attempt %d)", job.ID, videoID, job.Attempts)
+
+	// Give the render its own context so CancelRender can stop it
+	jobCtx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.running[videoID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, videoID)
+		s.mu.Unlock()
+		cancel()
+	}()
+	go s.heartbeat(jobCtx, job.ID, cancel)
+
+	output, reused, err := s.render(jobCtx, job.ID, videoID, cancel)
+	if err != nil {
+		// The job and video were already updated by whoever cancelled them
+		if jobCtx.Err() != nil && ctx.Err() == nil {
+			log.Printf("🛑 Render job %s cancelled", job.ID)
+			return
+		}
+
+		s.handleRenderFailure(ctx, job, videoID, err)
+		return
+	}
+
+	// A reused render was not re-encoded, so it is not charged for
+	completed, err := s.renderJobRepo.CompleteRenderJob(ctx, job.ID, output, renderCreditRequestID(videoID), reused)
+	if err != nil {
+		log.Printf("❌ Failed to mark render job %s as completed: %v", job.ID, err)
+		return
+	}
+	if !completed {
+		log.Printf("🛑 Render job %s was cancelled before it could complete", job.ID)
+		return
+	}
+
+	log.Printf("✅ Render job %s completed", job.ID)
+}
+
+// handleRenderFailure puts a job that hit a transient error back on the queue with exponential backoff,
+// and fails the job and its video, refunding the credits, if the error is permanent or the job is out of attempts
+func (s *RenderJobService) handleRenderFailure(ctx context.Context, job *db.RenderJob, videoID uuid.UUID, err error) {
+	failure := classifyRenderError(err)
+
+	if failure.Transient && job.Attempts < maxRenderAttempts {
+		delay := renderRetryDelay(job.Attempts)
+		log.Printf("⚠️ Render job %s hit a transient error on attempt %d, retrying in %s: %v", job.ID, job.Attempts, delay, err)
+
+		if _, retryErr := s.renderJobRepo.RetryRenderJob(ctx, job.ID, err.Error(), time.Now().Add(delay)); retryErr != nil {
+			log.Printf("❌ Failed to requeue render job %s: %v", job.ID, retryErr)
+		}
+		return
+	}
+
+	message := failure.Message
+	if failure.Transient {
+		message = renderRetriesExhaustedMessage
+	}
+	log.Printf("❌ Render job %s failed on attempt %d: %v", job.ID, job.Attempts, err)
+
+	if failErr := s.renderJobRepo.FailRenderJob(ctx, job.ID, videoID, err.Error(), message, renderCreditRequestID(videoID)); failErr != nil {
+		log.Printf("❌ Failed to mark render job %s as failed: %v", job.ID, failErr)
+	}
+}
+
+// GetLatestRenderJob gets the most recent render job for a user-generated video
+func (s *RenderJobService) GetLatestRenderJob(ctx context.Context, videoID uuid.UUID) (*db.RenderJob, error) {
+	return s.renderJobRepo.GetLatestRenderJobByVideoID(ctx, videoID)
+}
+
+// CancelRender stops a user's queued or running render, marks the video cancelled and refunds its credits.
+// A render running on another instance stops the next time it reports progress.
+func (s *RenderJobService) CancelRender(ctx context.Context, userID, videoID uuid.UUID) (*db.UserGeneratedVideo, error) {
+	video, err := s.aiAvatarService.GetUserGeneratedVideoByID(ctx, videoID)
+	if err != nil || uuid.UUID(video.UserID.Bytes) != userID {
+		return nil, ErrRenderNotFound
+	}
+
+	jobs, cancelledVideo, err := s.renderJobRepo.CancelRenderJobsForVideo(ctx, videoID, renderCreditRequestID(videoID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel render: %w", err)
+	}
+	if len(jobs) == 0 {
+		return nil, ErrRenderNotCancellable
+	}
+
+	// Kill ffmpeg straight away if the render is running here
+	s.mu.Lock()
+	if cancel, ok := s.running[videoID]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	log.Printf("🛑 Cancelled render for video %s", videoID)
+	return cancelledVideo, nil
+}
+
+// render loads the video and its source and runs the overlay pipeline, recording progress on the job.
+// It returns the filenames to complete the video with and whether an identical completed render was
+// reused instead of running the pipeline.
+func (s *RenderJobService) render(ctx context.Context, jobID, videoID uuid.UUID, cancel context.CancelFunc) (*db.UpdateUserGeneratedVideoFilenamesParams, bool, error) {
+	video, err := s.aiAvatarService.GetUserGeneratedVideoByID(ctx, videoID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get user-generated video: %w", err)
+	}
+
+	// An identical render the user already has can be reused without running ffmpeg
+	reused, err := s.aiAvatarService.ReuseCompletedRender(ctx, video)
+	if err != nil {
+		return nil, false, err
+	}
+	if reused != nil {
+		return reused, true, nil
+	}
+
+	aiAvatarVideo, err := s.aiAvatarService.GetVideoByID(ctx, uuid.UUID(video.AiAvatarVideoID.Bytes))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get AI avatar video: %w", err)
+	}
+
+	output, err := s.aiAvatarService.ProcessVideoWithTextOverlay(ctx, video, aiAvatarVideo, s.progressRecorder(ctx, jobID, cancel))
+	return output, false, err
+}
+
+// progressRecorder returns a ProgressFunc that writes progress to the job, at most once per update interval.
+// If the job is found to be no longer running it calls cancel to stop the render.
+func (s *RenderJobService) progressRecorder(ctx context.Context, jobID uuid.UUID, cancel context.CancelFunc) ProgressFunc {
+	var lastUpdate time.Time
+
+	return func(progress RenderProgress) {
+		if time.Since(lastUpdate) < renderJobProgressUpdateInterval {
+			return
+		}
+		lastUpdate = time.Now()
+
+		etaSeconds := int32(progress.ETA.Round(time.Second).Seconds())
+		running, err := s.renderJobRepo.UpdateRenderJobProgress(ctx, jobID, progress.Percent, &etaSeconds)
+		if err != nil {
+			log.Printf("❌ Failed to record progress for render job %s: %v", jobID, err)
+			return
+		}
+		if !running {
+			cancel()
+		}
+	}
+}
+
+// heartbeat keeps a running job's lock fresh until ctx is done, so long renders are not requeued as stale.
+// If the job is found to be no longer running it calls cancel to stop the render.
+func (s *RenderJobService) heartbeat(ctx context.Context, jobID uuid.UUID, cancel context.CancelFunc) {
+	ticker := time.NewTicker(renderJobHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			running, err := s.renderJobRepo.HeartbeatRenderJob(ctx, jobID)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("❌ Failed to heartbeat render job %s: %v", jobID, err)
+				}
+				continue
+			}
+			if !running {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// requeueStaleJobs periodically returns jobs abandoned by crashed workers, which have stopped
+// heartbeating, to the queue, failing those that have been abandoned on every attempt
+func (s *RenderJobService) requeueStaleJobs(ctx context.Context) {
+	ticker := time.NewTicker(renderJobRequeueInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			staleBefore := time.Now().Add(-renderJobStaleAfter)
+			count, err := s.renderJobRepo.RequeueStaleRenderJobs(ctx, staleBefore, maxRenderAttempts)
+			if err != nil {
+				log.Printf("❌ Failed to requeue stale render jobs: %v", err)
+				continue
+			}
+			if count > 0 {
+				log.Printf("♻️ Requeued %d stale render job(s)", count)
+			}
+
+			// A job whose worker died on every attempt is probably what is killing them
+			failed, err := s.renderJobRepo.FailStaleRenderJobs(ctx, staleBefore, maxRenderAttempts, "worker went away on every attempt", renderRetriesExhaustedMessage, renderCreditRequestID)
+			if err != nil {
+				log.Printf("❌ Failed to fail stale render jobs: %v", err)
+				continue
+			}
+			for _, job := range failed {
+				log.Printf("❌ Render job %s failed after its worker went away %d times", job.ID, job.Attempts)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/google/uuid"
+)
+
+// RenderOptions are the per-request render settings, stored as JSON on the user-generated video
+type RenderOptions struct {
+	Style          *api.OverlayStyle   `json:"style,omitempty"`
+	Captions       *api.CaptionOptions `json:"captions,omitempty"`
+	OutputProfiles []api.OutputProfile `json:"output_profiles,omitempty"`
+	SubtitleMode   api.SubtitleMode    `json:"subtitle_mode,omitempty"`
+	Clip           *api.ClipOptions    `json:"clip,omitempty"`
+	EndCard        *api.EndCard        `json:"end_card,omitempty"`
+	BrandKitID     *uuid.UUID          `json:"brand_kit_id,omitempty"`
+	// BrandKit is the brand kit named by BrandKitID as it was when the render was queued
+	BrandKit *brandKitStyle `json:"brand_kit,omitempty"`
+}
+
+// renderOptionsFromVideo decodes the render options stored on a user-generated video
+func renderOptionsFromVideo(video *db.UserGeneratedVideo) (*RenderOptions, error) {
+	options := &RenderOptions{}
+	if len(video.RenderOptions) == 0 {
+		return options, nil
+	}
+
+	if err := json.Unmarshal(video.RenderOptions, options); err != nil {
+		return nil, fmt.Errorf("failed to decode render options: %w", err)
+	}
+	return options, nil
+}
+
+// style returns the requested overlay style with the brand kit's colours filling in what it leaves unset
+func (o *RenderOptions) style() *api.OverlayStyle {
+	if o.BrandKit == nil {
+		return o.Style
+	}
+
+	style := api.OverlayStyle{}
+	if o.Style != nil {
+		style = *o.Style
+	}
+	if style.FontColor == nil {
+		style.FontColor = o.BrandKit.FontColor
+	}
+	if style.StrokeColor == nil {
+		style.StrokeColor = o.BrandKit.StrokeColor
+	}
+	if style.Background == nil && o.BrandKit.BackgroundColor != nil {
+		style.Background = &api.OverlayBackground{Color: *o.BrandKit.BackgroundColor}
+	}
+	return &style
+}
+
+// captions returns the requested caption options with the brand kit's highlight colour if they leave it unset
+func (o *RenderOptions) captions() *api.CaptionOptions {
+	if o.BrandKit == nil || o.BrandKit.HighlightColor == nil {
+		return o.Captions
+	}
+
+	captions := api.CaptionOptions{}
+	if o.Captions != nil {
+		captions = *o.Captions
+	}
+	if captions.HighlightColor == nil {
+		captions.HighlightColor = o.BrandKit.HighlightColor
+	}
+	return &captions
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/ethanhosier/reel-farm/internal/api"
+)
+
+// rendererVersion is part of every render spec hash. Bump it whenever a change to the render
+// pipeline alters the output for the same inputs, so earlier renders are no longer reused.
+const rendererVersion = 1
+
+// renderSpec is everything that determines a render's output. Its JSON encoding is hashed to find
+// earlier renders that can be reused, so fields hold resolved values rather than raw request options:
+// a request that spells out a default hashes the same as one that leaves it out.
+type renderSpec struct {
+	RendererVersion int                 `json:"renderer_version"`
+	SourceVideoID   string              `json:"source_video_id"`
+	SourceFilename  string              `json:"source_filename"`
+	SourceUpdatedAt time.Time           `json:"source_updated_at"`
+	OverlayText     string              `json:"overlay_text"`
+	Style           overlayStyle        `json:"style"`
+	Captions        captionOptions      `json:"captions"`
+	OutputProfiles  []api.OutputProfile `json:"output_profiles"`
+	Subtitles       subtitleDelivery    `json:"subtitles"`
+	Watermark       *watermark          `json:"watermark,omitempty"`
+	Clip            *clipOptions        `json:"clip,omitempty"`
+	EndCard         *endCard            `json:"end_card,omitempty"`
+}
+
+// RenderSpecHash returns the hex SHA-256 of the canonical render spec for rendering overlayText
+// over source with the given options, and with the watermark if watermarked
+func (s *AIAvatarService) RenderSpecHash(source *db.AiAvatarVideo, overlayText string, options *RenderOptions, watermarked bool) (string, error) {
+	style, err := s.resolveRenderStyle(options)
+	if err != nil {
+		return "", err
+	}
+	captions, err := resolveCaptionOptions(options.captions(), overlayText)
+	if err != nil {
+		return "", err
+	}
+	delivery, err := resolveSubtitleDelivery(options.SubtitleMode)
+	if err != nil {
+		return "", err
+	}
+	clip, err := resolveClipOptions(options.Clip)
+	if err != nil {
+		return "", err
+	}
+	card, err := resolveEndCard(options.EndCard, options.BrandKit)
+	if err != nil {
+		return "", err
+	}
+
+	// Font files live at different paths on different hosts; the family identifies the font
+	style.FontPath = ""
+
+	// Variants are rendered independently, so the order they were asked for doesn't matter
+	profiles := slices.Clone(options.OutputProfiles)
+	slices.Sort(profiles)
+
+	spec := renderSpec{
+		RendererVersion: rendererVersion,
+		SourceVideoID:   source.ID.String(),
+		SourceFilename:  source.Filename,
+		SourceUpdatedAt: source.UpdatedAt.UTC(),
+		OverlayText:     overlayText,
+		Style:           *style,
+		Captions:        *captions,
+		OutputProfiles:  profiles,
+		Subtitles:       delivery,
+		Clip:            clip,
+		EndCard:         card,
+	}
+	if watermarked {
+		spec.Watermark = s.watermark
+	}
+
+	encoded, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode render spec: %w", err)
+	}
+
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
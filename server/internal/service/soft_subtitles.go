@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethanhosier/reel-farm/internal/api"
+	"github.com/ethanhosier/reel-farm/internal/subtitles"
+)
+
+// ErrInvalidSubtitleMode is returned when a render request asks for an unknown subtitle mode
+var ErrInvalidSubtitleMode = errors.New("invalid subtitle mode")
+
+// subtitleDelivery is a validated api.SubtitleMode: where the overlay text ends up
+type subtitleDelivery struct {
+	BurnIn bool // Drawn onto the picture
+	Soft   bool // Muxed as a mov_text track and published as SRT/VTT sidecars
+}
+
+// resolveSubtitleDelivery validates a requested subtitle mode, defaulting to burned in
+func resolveSubtitleDelivery(mode api.SubtitleMode) (subtitleDelivery, error) {
+	switch mode {
+	case "", api.BurnedIn:
+		return subtitleDelivery{BurnIn: true}, nil
+	case api.Soft:
+		return subtitleDelivery{Soft: true}, nil
+	case api.BurnedInAndSoft:
+		return subtitleDelivery{BurnIn: true, Soft: true}, nil
+	default:
+		return subtitleDelivery{}, fmt.Errorf("%w: subtitle_mode must be burned_in, soft or burned_in_and_soft", ErrInvalidSubtitleMode)
+	}
+}
+
+// subtitleCues turns the overlay text into timed cues. Static captions are one cue for the whole
+// video; animated captions are one cue per word or phrase, timed like the burned-in animation.
+func subtitleCues(text string, captions *captionOptions, duration time.Duration) []subtitles.Cue {
+	if !captions.Animated {
+		return []subtitles.Cue{{Start: 0, End: duration, Text: text}}
+	}
+
+	words, _ := captionWords(text)
+	wordSteps, stepWords := captionSteps(text, captions.Unit)
+
+	stepText := make([][]string, len(stepWords))
+	for i, word := range words {
+		stepText[wordSteps[i]] = append(stepText[wordSteps[i]], word)
+	}
+
+	var cues []subtitles.Cue
+	for i, step := range captions.stepTimes(stepWords, duration) {
+		if step.End <= step.Start {
+			continue
+		}
+		cues = append(cues, subtitles.Cue{Start: step.Start, End: step.End, Text: strings.Join(stepText[i], " ")})
+	}
+	return cues
+}
+
+// writeSubtitleSidecars writes the cues as an SRT and a WebVTT file in dir, named after the video,
+// and returns their paths. The SRT file is also the input for the mov_text track.
+func writeSubtitleSidecars(dir, videoID string, cues []subtitles.Cue) (srtPath, vttPath string, err error) {
+	srtPath = filepath.Join(dir, videoID+".srt")
+	if err := os.WriteFile(srtPath, []byte(subtitles.SRT(cues)), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write SRT subtitles: %w", err)
+	}
+
+	vttPath = filepath.Join(dir, videoID+".vtt")
+	if err := os.WriteFile(vttPath, []byte(subtitles.WebVTT(cues)), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write WebVTT subtitles: %w", err)
+	}
+
+	return srtPath, vttPath, nil
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/google/uuid"
+)
+
+// defaultSourceCacheMaxBytes bounds the source cache when SOURCE_CACHE_MAX_MB is not set
+const defaultSourceCacheMaxBytes = 2 << 30
+
+// SourceCache keeps recently used AI avatar source videos on local disk so renders don't download
+// them again. Entries are keyed by AI avatar video ID, checked against the video's recorded file size
+// (or its ETag when the size is unknown) before use and evicted least recently used first once the
+// cache grows past its size limit.
+type SourceCache struct {
+	dir      string
+	maxBytes int64
+	client   *http.Client
+
+	mu       sync.Mutex
+	entries  map[uuid.UUID]*list.Element // Values are *sourceCacheEntry
+	lru      *list.List                  // Most recently used at the front
+	size     int64
+	inflight map[uuid.UUID]*sourceDownload
+}
+
+// sourceCacheEntry is a source video held in the cache
+type sourceCacheEntry struct {
+	id   uuid.UUID
+	path string
+	size int64
+	etag string
+}
+
+// sourceDownload is a download in progress that concurrent renders of the same source wait on
+type sourceDownload struct {
+	done chan struct{}
+	err  error
+}
+
+// NewSourceCache creates a source cache in dir holding up to maxBytes of video. Anything already in
+// dir is removed, since the cache doesn't know what it was validated against.
+func NewSourceCache(dir string, maxBytes int64) (*SourceCache, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to clear source cache directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create source cache directory: %w", err)
+	}
+
+	return &SourceCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		client: &http.Client{
+			Timeout: 5 * time.Minute,
+		},
+		entries:  make(map[uuid.UUID]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[uuid.UUID]*sourceDownload),
+	}, nil
+}
+
+// Fetch makes the source video available at outputPath, downloading it from url first unless a valid
+// copy is cached. outputPath is a hard link to the cached file, so it stays usable if the entry is evicted.
+func (c *SourceCache) Fetch(ctx context.Context, source *db.AiAvatarVideo, url, outputPath string) error {
+	for {
+		if c.lookup(ctx, source, url) {
+			linked, err := c.link(source.ID, outputPath)
+			if err != nil || linked {
+				return err
+			}
+		}
+
+		// Join the download already in flight for this source, or start one
+		c.mu.Lock()
+		download, ok := c.inflight[source.ID]
+		if !ok {
+			download = &sourceDownload{done: make(chan struct{})}
+			c.inflight[source.ID] = download
+			go c.download(ctx, source, url, download)
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-download.done:
+		}
+		if download.err != nil {
+			return download.err
+		}
+
+		// A fresh download is used as is; it is only missing if something evicted it straight away
+		linked, err := c.link(source.ID, outputPath)
+		if err != nil || linked {
+			return err
+		}
+	}
+}
+
+// lookup reports whether source has a valid cached copy, dropping a stale one
+func (c *SourceCache) lookup(ctx context.Context, source *db.AiAvatarVideo, url string) bool {
+	c.mu.Lock()
+	element, ok := c.entries[source.ID]
+	if !ok {
+		c.mu.Unlock()
+		return false
+	}
+	entry := element.Value.(*sourceCacheEntry)
+	c.mu.Unlock()
+
+	valid := true
+	switch {
+	case source.FileSize != nil:
+		valid = entry.size == *source.FileSize
+	case entry.etag != "":
+		etag, err := c.currentETag(ctx, url)
+		if err != nil {
+			log.Printf("⚠️ Failed to revalidate cached source %s: %v", source.ID, err)
+			valid = false
+		} else {
+			valid = etag == entry.etag
+		}
+	}
+
+	if !valid {
+		log.Printf("🗑️ Cached source %s is stale", source.ID)
+		c.mu.Lock()
+		if current, ok := c.entries[source.ID]; ok && current == element {
+			c.remove(element)
+		}
+		c.mu.Unlock()
+		return false
+	}
+	return true
+}
+
+// link hard links the cached copy of a source to outputPath, copying it if linking is not possible.
+// It reports false if the source is no longer cached.
+func (c *SourceCache) link(id uuid.UUID, outputPath string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[id]
+	if !ok {
+		return false, nil
+	}
+	c.lru.MoveToFront(element)
+	entry := element.Value.(*sourceCacheEntry)
+
+	if err := os.Link(entry.path, outputPath); err == nil {
+		return true, nil
+	}
+	if err := copyFile(entry.path, outputPath); err != nil {
+		// The file was removed from under the cache
+		if os.IsNotExist(err) {
+			c.remove(element)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to copy cached source: %w", err)
+	}
+	return true, nil
+}
+
+// download fetches a source into the cache and wakes everyone waiting on it. It carries on if the
+// render that started it is cancelled, since other renders may be waiting for the same source.
+func (c *SourceCache) download(ctx context.Context, source *db.AiAvatarVideo, url string, download *sourceDownload) {
+	defer close(download.done)
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, source.ID)
+		c.mu.Unlock()
+	}()
+
+	path := filepath.Join(c.dir, fmt.Sprintf("%s_%d.mp4", source.ID, time.Now().UnixNano()))
+	etag, err := downloadVideo(context.WithoutCancel(ctx), c.client, url, path)
+	if err != nil {
+		os.Remove(path)
+		download.err = err
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		os.Remove(path)
+		download.err = fmt.Errorf("failed to stat downloaded source: %w", err)
+		return
+	}
+	// A copy of the wrong size would be treated as stale by every lookup, so it is never cached
+	if source.FileSize != nil && info.Size() != *source.FileSize {
+		os.Remove(path)
+		download.err = fmt.Errorf("source downloaded as %d bytes but is recorded as %d bytes", info.Size(), *source.FileSize)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[source.ID]; ok {
+		c.remove(element)
+	}
+	entry := &sourceCacheEntry{id: source.ID, path: path, size: info.Size(), etag: etag}
+	c.entries[source.ID] = c.lru.PushFront(entry)
+	c.size += entry.size
+
+	// Evict least recently used sources, keeping the new one so its waiters can link it
+	for c.size > c.maxBytes && c.lru.Back() != c.entries[source.ID] {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove drops an entry from the cache and deletes its file; callers must hold c.mu
+func (c *SourceCache) remove(element *list.Element) {
+	entry := element.Value.(*sourceCacheEntry)
+	c.lru.Remove(element)
+	delete(c.entries, entry.id)
+	c.size -= entry.size
+	if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+		log.Printf("⚠️ Failed to remove cached source %s: %v", entry.id, err)
+	}
+}
+
+// currentETag asks the CDN for the source's current ETag
+func (c *SourceCache) currentETag(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to check source: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to check source: HTTP %d", resp.StatusCode)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// copyFile copies src to a new file at dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
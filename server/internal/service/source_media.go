@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethanhosier/reel-farm/db"
+	"github.com/ethanhosier/reel-farm/internal/ffmpeg"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxSourceDimension is the largest frame width or height the render workers can handle
+	maxSourceDimension = 4096
+	// maxSourceDuration is the longest source video the render workers will encode
+	maxSourceDuration = 10 * time.Minute
+)
+
+// ErrUnsupportedSource is returned when a source video cannot be used by the render pipeline
+var ErrUnsupportedSource = errors.New("unsupported source video")
+
+// supportedSourceVideoCodecs are the video codecs the render workers can decode
+var supportedSourceVideoCodecs = map[string]bool{
+	"h264":   true,
+	"hevc":   true,
+	"vp8":    true,
+	"vp9":    true,
+	"av1":    true,
+	"mpeg4":  true,
+	"prores": true,
+}
+
+// SourceMetadata is what the render pipeline needs to know about a source video
+type SourceMetadata struct {
+	Width      int // Coded size, before rotation
+	Height     int
+	Rotation   int // Degrees: 0, 90, 180 or 270
+	FrameRate  float64
+	VideoCodec string // Empty if there is no video stream
+	AudioCodec string // Empty if there is no audio stream
+	HasAudio   bool
+	Duration   time.Duration
+}
+
+// ProbeSourceMetadata reads a source video's metadata using ffprobe
+func ProbeSourceMetadata(ctx context.Context, path string) (*SourceMetadata, error) {
+	probe, err := ffmpeg.Probe(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &SourceMetadata{Duration: probe.Duration()}
+	if video := probe.VideoStream(); video != nil {
+		metadata.Width = video.Width
+		metadata.Height = video.Height
+		metadata.Rotation = video.Rotation
+		metadata.FrameRate = video.FrameRate
+		metadata.VideoCodec = video.CodecName
+	}
+	if audio := probe.AudioStream(); audio != nil {
+		metadata.AudioCodec = audio.CodecName
+		metadata.HasAudio = true
+	}
+	return metadata, nil
+}
+
+// Validate returns an error wrapping ErrUnsupportedSource that says why the render pipeline cannot
+// use the source, or nil if it can
+func (m *SourceMetadata) Validate() error {
+	if reason := m.unsupportedReason(); reason != "" {
+		return fmt.Errorf("%w: %s", ErrUnsupportedSource, reason)
+	}
+	return nil
+}
+
+// unsupportedReason says why the render pipeline cannot use the source, or is empty if it can
+func (m *SourceMetadata) unsupportedReason() string {
+	switch {
+	case m.VideoCodec == "":
+		return "no video stream"
+	case !supportedSourceVideoCodecs[m.VideoCodec]:
+		return fmt.Sprintf("unsupported video codec %q", m.VideoCodec)
+	case m.Width <= 0 || m.Height <= 0:
+		return "unknown frame size"
+	case m.Width > maxSourceDimension || m.Height > maxSourceDimension:
+		return fmt.Sprintf("frame size %dx%d is larger than %dx%d", m.Width, m.Height, maxSourceDimension, maxSourceDimension)
+	case m.Duration <= 0:
+		return "unknown duration"
+	case m.Duration > maxSourceDuration:
+		return fmt.Sprintf("duration %s is longer than %s", m.Duration.Round(time.Second), maxSourceDuration)
+	}
+	return ""
+}
+
+// DisplaySize is the frame size once rotation is applied, which is the size ffmpeg's filters see
+func (m *SourceMetadata) DisplaySize() (width, height int) {
+	if m.Rotation == 90 || m.Rotation == 270 {
+		return m.Height, m.Width
+	}
+	return m.Width, m.Height
+}
+
+// videoInfo converts the metadata to the form the render pipeline works with
+func (m *SourceMetadata) videoInfo() *videoInfo {
+	width, height := m.DisplaySize()
+	return &videoInfo{
+		Duration:  m.Duration,
+		Width:     width,
+		Height:    height,
+		HasAudio:  m.HasAudio,
+		FrameRate: m.FrameRate,
+	}
+}
+
+// sourceMetadataFromVideo returns the metadata stored on an AI avatar video, or nil if it has not been probed
+func sourceMetadataFromVideo(video *db.AiAvatarVideo) *SourceMetadata {
+	if !video.ProbedAt.Valid || video.Width == nil || video.Height == nil || video.DurationMs == nil {
+		return nil
+	}
+
+	metadata := &SourceMetadata{
+		Width:    int(*video.Width),
+		Height:   int(*video.Height),
+		Duration: time.Duration(*video.DurationMs) * time.Millisecond,
+	}
+	if video.Rotation != nil {
+		metadata.Rotation = int(*video.Rotation)
+	}
+	if video.FrameRate != nil {
+		metadata.FrameRate = *video.FrameRate
+	}
+	if video.VideoCodec != nil {
+		metadata.VideoCodec = *video.VideoCodec
+	}
+	if video.AudioCodec != nil {
+		metadata.AudioCodec = *video.AudioCodec
+	}
+	if video.HasAudio != nil {
+		metadata.HasAudio = *video.HasAudio
+	}
+	return metadata
+}
+
+// CreateVideoParams returns the parameters for recording a newly uploaded source video with this metadata
+func (m *SourceMetadata) CreateVideoParams(id uuid.UUID, title, filename, thumbnailFilename string, fileSize int64) *db.CreateVideoParams {
+	columns := m.updateParams(id, nil)
+	return &db.CreateVideoParams{
+		ID:                id,
+		Title:             title,
+		Filename:          filename,
+		ThumbnailFilename: thumbnailFilename,
+		FileSize:          &fileSize,
+		Duration:          columns.Duration,
+		Width:             columns.Width,
+		Height:            columns.Height,
+		Rotation:          columns.Rotation,
+		FrameRate:         columns.FrameRate,
+		VideoCodec:        columns.VideoCodec,
+		AudioCodec:        columns.AudioCodec,
+		HasAudio:          columns.HasAudio,
+		DurationMs:        columns.DurationMs,
+	}
+}
+
+// updateParams returns the parameters for recording this metadata on an existing source video
+func (m *SourceMetadata) updateParams(id uuid.UUID, unsupportedReason *string) *db.UpdateVideoMediaMetadataParams {
+	duration := int32(m.Duration / time.Second)
+	width := int32(m.Width)
+	height := int32(m.Height)
+	rotation := int32(m.Rotation)
+	frameRate := m.FrameRate
+	hasAudio := m.HasAudio
+	durationMs := m.Duration.Milliseconds()
+
+	params := &db.UpdateVideoMediaMetadataParams{
+		ID:                id,
+		Duration:          &duration,
+		Width:             &width,
+		Height:            &height,
+		Rotation:          &rotation,
+		FrameRate:         &frameRate,
+		HasAudio:          &hasAudio,
+		DurationMs:        &durationMs,
+		UnsupportedReason: unsupportedReason,
+	}
+	if m.VideoCodec != "" {
+		params.VideoCodec = &m.VideoCodec
+	}
+	if m.AudioCodec != "" {
+		params.AudioCodec = &m.AudioCodec
+	}
+	return params
+}
+
+// ProbeAndStoreSourceMetadata fetches a source video, probes it and records its metadata, marking it
+// unsupported if the render pipeline cannot use it
+func (s *AIAvatarService) ProbeAndStoreSourceMetadata(ctx context.Context, video *db.AiAvatarVideo) (*db.AiAvatarVideo, error) {
+	workDir := filepath.Join(s.tempDir, "probe-"+video.ID.String())
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	sourcePath := filepath.Join(workDir, "source.mp4")
+	if err := s.sources.Fetch(ctx, video, s.SourceVideoURL(video), sourcePath); err != nil {
+		return nil, fmt.Errorf("failed to fetch source video: %w", err)
+	}
+
+	metadata, err := ProbeSourceMetadata(ctx, sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe source video: %w", err)
+	}
+
+	var unsupportedReason *string
+	if reason := metadata.unsupportedReason(); reason != "" {
+		unsupportedReason = &reason
+	}
+
+	return s.repo.UpdateVideoMediaMetadata(ctx, metadata.updateParams(video.ID, unsupportedReason))
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	defaultWatermarkPosition = watermarkBottomRight
+	defaultWatermarkOpacity  = 0.6
+	defaultWatermarkScale    = 0.25
+
+	// watermarkMarginRatio is the share of the frame width kept between the watermark and the edges
+	watermarkMarginRatio = 0.04
+
+	// watermarkedPlan is the plan whose renders carry the watermark
+	watermarkedPlan = "free"
+)
+
+// watermarkPosition is where in the frame the watermark is drawn
+type watermarkPosition string
+
+const (
+	watermarkTopLeft     watermarkPosition = "top_left"
+	watermarkTopRight    watermarkPosition = "top_right"
+	watermarkBottomLeft  watermarkPosition = "bottom_left"
+	watermarkBottomRight watermarkPosition = "bottom_right"
+	watermarkCenter      watermarkPosition = "center"
+)
+
+// watermark is the logo burned into renders for users on the free plan. It is part of the render
+// spec, so changing the logo or its placement stops earlier watermarked renders being reused.
+type watermark struct {
+	Logo       image.Image       `json:"-"`
+	LogoSHA256 string            `json:"logo_sha256"`
+	Position   watermarkPosition `json:"position"`
+	Opacity    float64           `json:"opacity"`
+	Scale      float64           `json:"scale"` // Logo width as a share of the frame width
+}
+
+// watermarkFromEnv loads the watermark configured by WATERMARK_LOGO_PATH, WATERMARK_POSITION,
+// WATERMARK_OPACITY and WATERMARK_SCALE. It returns nil if no logo is configured.
+func watermarkFromEnv() (*watermark, error) {
+	logoPath := os.Getenv("WATERMARK_LOGO_PATH")
+	if logoPath == "" {
+		log.Printf("⚠️ WATERMARK_LOGO_PATH is not set; free plan renders will not be watermarked")
+		return nil, nil
+	}
+
+	position := defaultWatermarkPosition
+	if positionStr := os.Getenv("WATERMARK_POSITION"); positionStr != "" {
+		position = watermarkPosition(positionStr)
+		switch position {
+		case watermarkTopLeft, watermarkTopRight, watermarkBottomLeft, watermarkBottomRight, watermarkCenter:
+		default:
+			return nil, fmt.Errorf("invalid WATERMARK_POSITION: must be top_left, top_right, bottom_left, bottom_right or center")
+		}
+	}
+
+	opacity := defaultWatermarkOpacity
+	if opacityStr := os.Getenv("WATERMARK_OPACITY"); opacityStr != "" {
+		var err error
+		opacity, err = strconv.ParseFloat(opacityStr, 64)
+		if err != nil || opacity <= 0 || opacity > 1 {
+			return nil, fmt.Errorf("invalid WATERMARK_OPACITY: must be a number greater than 0 and at most 1")
+		}
+	}
+
+	scale := defaultWatermarkScale
+	if scaleStr := os.Getenv("WATERMARK_SCALE"); scaleStr != "" {
+		var err error
+		scale, err = strconv.ParseFloat(scaleStr, 64)
+		if err != nil || scale <= 0 || scale > 1 {
+			return nil, fmt.Errorf("invalid WATERMARK_SCALE: must be a number greater than 0 and at most 1")
+		}
+	}
+
+	data, err := os.ReadFile(logoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watermark logo: %w", err)
+	}
+	logo, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode watermark logo: %w", err)
+	}
+	sum := sha256.Sum256(data)
+
+	return &watermark{
+		Logo:       logo,
+		LogoSHA256: hex.EncodeToString(sum[:]),
+		Position:   position,
+		Opacity:    opacity,
+		Scale:      scale,
+	}, nil
+}
+
+// WatermarksPlan reports whether renders queued by a user on plan are watermarked
+func (s *AIAvatarService) WatermarksPlan(plan string) bool {
+	return s.watermark != nil && plan == watermarkedPlan
+}
+
+// render scales the logo to the frame and fades it to the watermark's opacity
+func (w *watermark) render(frameWidth int) *image.RGBA {
+	bounds := w.Logo.Bounds()
+	width := max(int(math.Round(float64(frameWidth)*w.Scale)), 1)
+	height := max(int(math.Round(float64(bounds.Dy())*float64(width)/float64(bounds.Dx()))), 1)
+
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), w.Logo, bounds, xdraw.Src, nil)
+
+	img := image.NewRGBA(scaled.Bounds())
+	opacity := image.NewUniform(color.Alpha{A: uint8(math.Round(w.Opacity * 255))})
+	draw.DrawMask(img, img.Bounds(), scaled, image.Point{}, opacity, image.Point{}, draw.Src)
+	return img
+}
+
+// position returns where to draw a width x height watermark in the frame
+func (w *watermark) position(frameWidth, frameHeight, width, height int) (x, y int) {
+	margin := int(math.Round(float64(frameWidth) * watermarkMarginRatio))
+
+	left, right := margin, frameWidth-width-margin
+	top, bottom := margin, frameHeight-height-margin
+
+	switch w.Position {
+	case watermarkTopLeft:
+		return left, top
+	case watermarkTopRight:
+		return right, top
+	case watermarkBottomLeft:
+		return left, bottom
+	case watermarkCenter:
+		return (frameWidth - width) / 2, (frameHeight - height) / 2
+	default:
+		return right, bottom
+	}
+}
+
+// write draws the watermark for the frame as a PNG next to the output, returning its path and
+// where to place it in the frame
+func (w *watermark) write(info *videoInfo, outputPath string) (path string, x, y int, err error) {
+	img := w.render(info.Width)
+
+	base := strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+	path, err = filepath.Abs(base + "_watermark.png")
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("failed to resolve watermark image path: %w", err)
+	}
+	if err := writePNG(path, img); err != nil {
+		return "", 0, 0, err
+	}
+
+	x, y = w.position(info.Width, info.Height, img.Bounds().Dx(), img.Bounds().Dy())
+	return path, x, y, nil
+}
@@ -0,0 +1,64 @@
+// Package subtitles writes subtitle files for ffmpeg to mux and for players to load alongside a video.
+package subtitles
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cue is one timed piece of plain text. Text may span several lines.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// SRT renders cues as a SubRip file, which is also what ffmpeg reads to mux a mov_text track
+func SRT(cues []Cue) string {
+	var b strings.Builder
+	for i, cue := range cues {
+		fmt.Fprintf(&b, "%d\n", i+1)
+		fmt.Fprintf(&b, "%s --> %s\n", cueTimestamp(cue.Start, ","), cueTimestamp(cue.End, ","))
+		b.WriteString(cueText(cue.Text))
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+// WebVTT renders cues as a WebVTT file
+func WebVTT(cues []Cue) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, cue := range cues {
+		fmt.Fprintf(&b, "%s --> %s\n", cueTimestamp(cue.Start, "."), cueTimestamp(cue.End, "."))
+		b.WriteString(vttEscaper.Replace(cueText(cue.Text)))
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+// vttEscaper escapes the characters WebVTT reads as markup
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// cueText drops blank lines, which would end the cue early in both formats, and puts a word joiner
+// inside any "-->" so a line of text is never read as cue timings
+func cueText(text string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, strings.ReplaceAll(line, "-->", "--\u2060>"))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cueTimestamp formats a duration as HH:MM:SS followed by sep and milliseconds; SRT uses a comma
+// and WebVTT a full stop
+func cueTimestamp(d time.Duration, sep string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Round(time.Millisecond).Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
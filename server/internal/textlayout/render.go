@@ -0,0 +1,261 @@
+package textlayout
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/go-text/typesetting/font"
+	ot "github.com/go-text/typesetting/font/opentype"
+	"github.com/go-text/typesetting/shaping"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// Align is how lines of different widths line up with each other
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Span is a piece of a line drawn in one colour. A nil Color uses RenderOptions.Color; a hidden
+// span takes up its space but is not drawn.
+type Span struct {
+	Text   string
+	Color  color.Color
+	Hidden bool
+}
+
+// Line is a laid-out line of text made of spans, drawn one after another
+type Line []Span
+
+// RenderOptions control how text is drawn
+type RenderOptions struct {
+	FontSize    int
+	LineSpacing int // Extra pixels between lines
+	Align       Align
+	Color       color.Color
+	StrokeColor color.Color
+	StrokeWidth int
+	// BackgroundColor fills a box around the text, padded by BackgroundPadding; nil for no box
+	BackgroundColor   color.Color
+	BackgroundPadding int
+}
+
+// Margin is how far the rendered image extends past the text on each side, to make room for the
+// stroke and background box
+func (o *RenderOptions) Margin() int {
+	if o.BackgroundColor != nil {
+		return max(o.StrokeWidth, o.BackgroundPadding)
+	}
+	return o.StrokeWidth
+}
+
+// Render draws lines on a transparent image just big enough for the text and its Margin. Glyphs
+// from colour bitmap fonts, such as emoji, are drawn in their own colours rather than opts.Color.
+func (f *Font) Render(lines []Line, opts *RenderOptions) (*image.RGBA, error) {
+	if opts.FontSize <= 0 {
+		return nil, fmt.Errorf("font size must be positive")
+	}
+
+	s := newShaper(f)
+	shaped := make([]shapedLine, len(lines))
+	var blockWidth, blockHeight fixed.Int26_6
+	for i, line := range lines {
+		shaped[i] = s.shapeLine(line, opts.FontSize)
+		blockWidth = max(blockWidth, shaped[i].width)
+		blockHeight += shaped[i].ascent - shaped[i].descent
+	}
+	blockHeight += fixed.I(opts.LineSpacing * max(len(lines)-1, 0))
+
+	margin := opts.Margin()
+	bounds := image.Rect(0, 0, blockWidth.Ceil()+2*margin, blockHeight.Ceil()+2*margin)
+	img := image.NewRGBA(bounds)
+
+	if opts.BackgroundColor != nil {
+		box := image.Rect(0, 0, blockWidth.Ceil(), blockHeight.Ceil()).
+			Add(image.Pt(margin, margin)).
+			Inset(-opts.BackgroundPadding)
+		draw.Draw(img, box, image.NewUniform(opts.BackgroundColor), image.Point{}, draw.Over)
+	}
+
+	// Draw the glyphs on their own layer so the stroke can be grown from their shapes
+	text := image.NewRGBA(bounds)
+	rasterizer := &vector.Rasterizer{}
+	y := fixed.I(margin)
+	for i, line := range shaped {
+		x := fixed.I(margin)
+		switch opts.Align {
+		case AlignCenter:
+			x += (blockWidth - line.width) / 2
+		case AlignRight:
+			x += blockWidth - line.width
+		}
+		baseline := y + line.ascent
+
+		for _, output := range line.outputs {
+			for _, glyph := range output.Glyphs {
+				span := lines[i][line.spans[glyph.ClusterIndex]]
+				if !span.Hidden {
+					spanColor := span.Color
+					if spanColor == nil {
+						spanColor = opts.Color
+					}
+					drawGlyph(text, rasterizer, &output, &glyph, x+glyph.XOffset, baseline-glyph.YOffset, spanColor)
+				}
+				x += glyph.Advance
+			}
+		}
+
+		y += line.ascent - line.descent + fixed.I(opts.LineSpacing)
+	}
+
+	if opts.StrokeWidth > 0 && opts.StrokeColor != nil {
+		outline := dilate(text, opts.StrokeWidth)
+		draw.DrawMask(img, bounds, image.NewUniform(opts.StrokeColor), image.Point{}, outline, image.Point{}, draw.Over)
+	}
+	draw.Draw(img, bounds, text, image.Point{}, draw.Over)
+
+	return img, nil
+}
+
+// shapedLine is a line shaped at a font size, with the span each rune belongs to
+type shapedLine struct {
+	outputs []shaping.Output
+	spans   []int
+	width   fixed.Int26_6
+	ascent  fixed.Int26_6
+	descent fixed.Int26_6 // Negative, below the baseline
+}
+
+// shapeLine shapes the spans of a line together, so kerning and ligatures work across them. The
+// line is as tall as the tallest font used in it.
+func (s *shaper) shapeLine(line Line, size int) shapedLine {
+	var text []rune
+	var spans []int
+	for i, span := range line {
+		for _, r := range span.Text {
+			text = append(text, r)
+			spans = append(spans, i)
+		}
+	}
+
+	shaped := shapedLine{spans: spans}
+	if len(text) == 0 {
+		// Keep blank lines as tall as a line of text
+		text = []rune{' '}
+	}
+	shaped.outputs = s.shape(text, size)
+	for _, output := range shaped.outputs {
+		shaped.width += output.Advance
+		shaped.ascent = max(shaped.ascent, output.LineBounds.Ascent)
+		shaped.descent = min(shaped.descent, output.LineBounds.Descent)
+	}
+	if len(spans) == 0 {
+		shaped.outputs = nil
+		shaped.width = 0
+	}
+	return shaped
+}
+
+// drawGlyph draws one shaped glyph with its origin at (x, y): a colour bitmap if the font has one,
+// otherwise the glyph outline filled with c. Any part of the glyph outside dst is clipped.
+func drawGlyph(dst *image.RGBA, rasterizer *vector.Rasterizer, output *shaping.Output, glyph *shaping.Glyph, x, y fixed.Int26_6, c color.Color) {
+	// Glyph extents are measured from the origin, with YBearing up to the top and Height down from it
+	left := x + glyph.XBearing
+	top := y - glyph.YBearing
+	rect := image.Rect(left.Floor(), top.Floor(), (left + glyph.Width).Ceil(), (top - glyph.Height).Ceil())
+	if rect.Empty() {
+		return
+	}
+
+	if bitmap, ok := output.Face.GlyphDataBitmap(glyph.GlyphID); ok && bitmap.Format == font.PNG {
+		if src, err := png.Decode(bytes.NewReader(bitmap.Data)); err == nil {
+			xdraw.CatmullRom.Scale(dst, rect, src, src.Bounds(), xdraw.Over, nil)
+			return
+		}
+	}
+
+	outline, ok := output.Face.GlyphDataOutline(glyph.GlyphID)
+	if !ok || len(outline.Segments) == 0 {
+		return
+	}
+
+	// Outlines are in font units with y pointing up; the rasterizer works in pixels relative to rect
+	scale := float32(output.Size) / 64 / float32(output.Face.Upem())
+	originX := float32(x)/64 - float32(rect.Min.X)
+	originY := float32(y)/64 - float32(rect.Min.Y)
+	point := func(p ot.SegmentPoint) (float32, float32) {
+		return originX + p.X*scale, originY - p.Y*scale
+	}
+
+	rasterizer.Reset(rect.Dx(), rect.Dy())
+	for _, segment := range outline.Segments {
+		switch segment.Op {
+		case ot.SegmentOpMoveTo:
+			rasterizer.ClosePath()
+			rasterizer.MoveTo(point(segment.Args[0]))
+		case ot.SegmentOpLineTo:
+			rasterizer.LineTo(point(segment.Args[0]))
+		case ot.SegmentOpQuadTo:
+			bx, by := point(segment.Args[0])
+			cx, cy := point(segment.Args[1])
+			rasterizer.QuadTo(bx, by, cx, cy)
+		case ot.SegmentOpCubeTo:
+			bx, by := point(segment.Args[0])
+			cx, cy := point(segment.Args[1])
+			dx, dy := point(segment.Args[2])
+			rasterizer.CubeTo(bx, by, cx, cy, dx, dy)
+		}
+	}
+	rasterizer.ClosePath()
+
+	// The image is sized from the line boxes, not the ink, so glyphs such as accented capitals can
+	// reach past it; rasterize into a mask and let DrawMask clip it to dst
+	mask := image.NewAlpha(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	rasterizer.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+	draw.DrawMask(dst, rect, image.NewUniform(c), image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// dilate grows the alpha of src outwards by radius pixels to make the mask of the stroke drawn
+// behind the text. Alternating square and diamond steps keep the corners of the stroke round.
+func dilate(src *image.RGBA, radius int) *image.Alpha {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	alpha := make([]uint8, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			alpha[y*width+x] = src.Pix[y*src.Stride+x*4+3]
+		}
+	}
+
+	grown := make([]uint8, len(alpha))
+	for step := 0; step < radius; step++ {
+		diagonals := step%2 == 0
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				v := alpha[y*width+x]
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						if (dx != 0 && dy != 0 && !diagonals) || x+dx < 0 || x+dx >= width || y+dy < 0 || y+dy >= height {
+							continue
+						}
+						v = max(v, alpha[(y+dy)*width+x+dx])
+					}
+				}
+				grown[y*width+x] = v
+			}
+		}
+		alpha, grown = grown, alpha
+	}
+
+	return &image.Alpha{Pix: alpha, Stride: width, Rect: bounds}
+}
@@ -0,0 +1,29 @@
+package textlayout
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestRenderClipsInkOutsideLineBox renders accented capitals, whose ink rises above the font's
+// ascent, with no stroke to give the image a margin. Glyphs must be clipped to the image rather
+// than drawn outside it.
+func TestRenderClipsInkOutsideLineBox(t *testing.T) {
+	font, err := LoadFont("../../TikTokDisplay-Medium.ttf")
+	if err != nil {
+		t.Fatalf("failed to load font: %v", err)
+	}
+
+	for _, text := range []string{"jÅ", "ÉÅÑ", "Ǻ", "ẤỸ"} {
+		img, err := font.Render([]Line{{{Text: text}}}, &RenderOptions{
+			FontSize: 36,
+			Color:    color.White,
+		})
+		if err != nil {
+			t.Fatalf("Render(%q) returned error: %v", text, err)
+		}
+		if img.Bounds().Empty() {
+			t.Errorf("Render(%q) returned an empty image", text)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+// Package textlayout wraps overlay text to a pixel width using real font metrics, and draws it.
+// Text is shaped, so joining scripts such as Arabic and Devanagari come out right, and mixed
+// left-to-right and right-to-left text is drawn in bidi order.
+package textlayout
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/go-text/typesetting/bidi"
+	"github.com/go-text/typesetting/di"
+	"github.com/go-text/typesetting/font"
+	ot "github.com/go-text/typesetting/font/opentype"
+	"github.com/go-text/typesetting/language"
+	"github.com/go-text/typesetting/segmenter"
+	"github.com/go-text/typesetting/shaping"
+	"golang.org/x/image/math/fixed"
+)
+
+// Font is a parsed TrueType/OpenType font used to measure and draw text, together with the fonts
+// used for characters it has no glyph for
+type Font struct {
+	font      *font.Font
+	fallbacks []*Font
+	// scriptFallbacks are tried before fallbacks for characters in a run of their script
+	scriptFallbacks map[language.Script]*Font
+}
+
+// LoadFont reads and parses a TTF/OTF file
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font file: %w", err)
+	}
+	return ParseFont(data)
+}
+
+// ParseFont parses TTF/OTF font data. For a TTC collection, such as Noto Sans CJK, the first font
+// in it is used.
+func ParseFont(data []byte) (*Font, error) {
+	faces, err := font.ParseTTC(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %w", err)
+	}
+	return &Font{font: faces[0].Font}, nil
+}
+
+// FontInfo describes a font file
+type FontInfo struct {
+	Family string
+	// RestrictedLicense is set when the font's embedding permissions (the OS/2 fsType field) say it
+	// may not be embedded or redistributed without the foundry's permission
+	RestrictedLicense bool
+}
+
+// InspectFont checks that data is a single TrueType or OpenType font that text can be drawn with,
+// and describes it
+func InspectFont(data []byte) (*FontInfo, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("not a TrueType or OpenType font")
+	}
+	switch string(data[:4]) {
+	case "\x00\x01\x00\x00", "OTTO", "true":
+	default:
+		return nil, fmt.Errorf("not a TrueType or OpenType font")
+	}
+
+	loader, err := ot.NewLoader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %w", err)
+	}
+	parsed, err := font.NewFont(loader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %w", err)
+	}
+	if _, ok := parsed.NominalGlyph(' '); !ok {
+		return nil, fmt.Errorf("font has no character map for Unicode text")
+	}
+
+	info := &FontInfo{Family: parsed.Describe().Family}
+	if os2, err := loader.RawTable(ot.MustNewTag("OS/2")); err == nil && len(os2) >= 10 {
+		fsType := binary.BigEndian.Uint16(os2[8:10])
+		info.RestrictedLicense = fsType&0x000F == 0x0002
+	}
+	return info, nil
+}
+
+// WithFallback returns a copy of the font that measures and draws characters it has no glyph for,
+// such as emoji, with the first of fallbacks that has one
+func (f *Font) WithFallback(fallbacks ...*Font) *Font {
+	return &Font{
+		font:            f.font,
+		fallbacks:       append(f.fallbacks[:len(f.fallbacks):len(f.fallbacks)], fallbacks...),
+		scriptFallbacks: f.scriptFallbacks,
+	}
+}
+
+// WithScriptFallback returns a copy of the font that draws characters of the given scripts it has no
+// glyph for with fallback, ahead of any fallbacks added by WithFallback
+func (f *Font) WithScriptFallback(fallback *Font, scripts ...language.Script) *Font {
+	scriptFallbacks := maps.Clone(f.scriptFallbacks)
+	if scriptFallbacks == nil {
+		scriptFallbacks = make(map[language.Script]*Font, len(scripts))
+	}
+	for _, script := range scripts {
+		scriptFallbacks[script] = fallback
+	}
+	return &Font{font: f.font, fallbacks: f.fallbacks, scriptFallbacks: scriptFallbacks}
+}
+
+// Options control how text is laid out
+type Options struct {
+	// FontSize is the preferred font size in pixels
+	FontSize int
+	// MinFontSize is the smallest size the font may shrink to so the text fits in MaxLines
+	MinFontSize int
+	// MaxWidth is the width available to a line in pixels
+	MaxWidth float64
+	// MaxLines is the most lines the text may wrap to before the font is shrunk (0 for no limit)
+	MaxLines int
+}
+
+// Layout is text wrapped into lines at a chosen font size
+type Layout struct {
+	Lines    []string
+	FontSize int
+	// Overflow is set when the text needs more than MaxLines lines even at MinFontSize
+	Overflow bool
+}
+
+// Layout wraps text to fit opts.MaxWidth, shrinking the font a pixel at a time from
+// opts.FontSize towards opts.MinFontSize until it fits in opts.MaxLines
+func (f *Font) Layout(text string, opts Options) (*Layout, error) {
+	if opts.FontSize <= 0 {
+		return nil, fmt.Errorf("font size must be positive")
+	}
+	minFontSize := min(max(opts.MinFontSize, 1), opts.FontSize)
+
+	s := newShaper(f)
+	var lines []string
+	for size := opts.FontSize; size >= minFontSize; size-- {
+		lines = s.wrap(text, size, opts.MaxWidth)
+
+		if opts.MaxLines <= 0 || len(lines) <= opts.MaxLines {
+			return &Layout{Lines: lines, FontSize: size}, nil
+		}
+	}
+
+	return &Layout{Lines: lines, FontSize: minFontSize, Overflow: true}, nil
+}
+
+// MeasureString returns the advance width of text in pixels at the given font size
+func (f *Font) MeasureString(text string, size int) (float64, error) {
+	if size <= 0 {
+		return 0, fmt.Errorf("font size must be positive")
+	}
+	return newShaper(f).measure(text, size), nil
+}
+
+// shaper shapes text in a font and its fallbacks. Font faces cache glyph lookups and are not safe
+// for concurrent use, so a shaper with its own faces is created for each call into the package.
+type shaper struct {
+	faces       []*font.Face // The font's face, then one for each fallback
+	scriptFaces map[language.Script]*font.Face
+	script      language.Script // The script of the run being split by face
+	harfbuzz    shaping.HarfbuzzShaper
+	segmenter   shaping.Segmenter
+	breaker     segmenter.Segmenter
+	bidi        bidi.Paragraph
+}
+
+// newShaper creates a shaper for text in f
+func newShaper(f *Font) *shaper {
+	faces := []*font.Face{font.NewFace(f.font)}
+	for _, fallback := range f.fallbacks {
+		faces = append(faces, font.NewFace(fallback.font))
+	}
+
+	// A font covering several scripts, like a CJK one, shares a face between them
+	scriptFaces := make(map[language.Script]*font.Face, len(f.scriptFallbacks))
+	byFont := make(map[*font.Font]*font.Face)
+	for script, fallback := range f.scriptFallbacks {
+		face, ok := byFont[fallback.font]
+		if !ok {
+			face = font.NewFace(fallback.font)
+			byFont[fallback.font] = face
+		}
+		scriptFaces[script] = face
+	}
+
+	return &shaper{faces: faces, scriptFaces: scriptFaces}
+}
+
+// SetScript records the script of the run the segmenter is about to resolve faces for. It implements
+// shaping.FontmapScript.
+func (s *shaper) SetScript(script language.Script) {
+	s.script = script
+}
+
+// ResolveFace picks the face to shape r with: the font's own if it has a glyph for r, then the
+// fallback for the script of the run, then the first other fallback that has one. It implements
+// shaping.Fontmap.
+func (s *shaper) ResolveFace(r rune) *font.Face {
+	if _, ok := s.faces[0].NominalGlyph(r); ok {
+		return s.faces[0]
+	}
+	if face, ok := s.scriptFaces[s.script]; ok {
+		if _, ok := face.NominalGlyph(r); ok {
+			return face
+		}
+	}
+	for _, face := range s.faces[1:] {
+		if _, ok := face.NominalGlyph(r); ok {
+			return face
+		}
+	}
+	return s.faces[0]
+}
+
+// shape shapes a line of text at size, split into runs by direction, by script and by the font that
+// has glyphs for them. The runs are returned in visual order, left to right, ready to be drawn.
+func (s *shaper) shape(text []rune, size int) []shaping.Output {
+	input := shaping.Input{
+		Text:      text,
+		RunStart:  0,
+		RunEnd:    len(text),
+		Direction: s.direction(text),
+		Face:      s.faces[0],
+		Size:      fixed.I(size),
+		Language:  language.NewLanguage("en"),
+	}
+
+	runs := s.segmenter.Split(input, s)
+	outputs := make([]shaping.Output, len(runs))
+	for i, run := range runs {
+		outputs[i] = s.harfbuzz.Shape(run)
+	}
+	visualOrder(outputs, input.Direction)
+	return outputs
+}
+
+// direction is the base direction of a line: right to left if its first strongly directional
+// character is, as in Arabic and Hebrew, otherwise left to right
+func (s *shaper) direction(text []rune) di.Direction {
+	runs := s.bidi.Segment(text, bidi.Neutral)
+	if runs.NumRuns() == 0 {
+		return di.DirectionLTR
+	}
+
+	// Only a right-to-left line has nothing at an even level below its first odd one
+	lowest := runs.Run(0).Level
+	for i := 1; i < runs.NumRuns(); i++ {
+		lowest = min(lowest, runs.Run(i).Level)
+	}
+	if lowest%2 == 1 {
+		return di.DirectionRTL
+	}
+	return di.DirectionLTR
+}
+
+// visualOrder puts runs shaped in logical order into the order they are drawn in, left to right. As
+// in the go-text line wrapper, a right-to-left line is reversed, and each sequence of runs against the
+// line's direction is then reversed back. Glyphs within a right-to-left run already come out of the
+// shaper in visual order.
+func visualOrder(outputs []shaping.Output, direction di.Direction) {
+	if direction == di.DirectionRTL {
+		slices.Reverse(outputs)
+	}
+	for start := 0; start < len(outputs); {
+		if outputs[start].Direction == direction {
+			start++
+			continue
+		}
+		end := start
+		for end < len(outputs) && outputs[end].Direction != direction {
+			end++
+		}
+		slices.Reverse(outputs[start:end])
+		start = end
+	}
+}
+
+// measure returns the advance width of text in pixels at size
+func (s *shaper) measure(text string, size int) float64 {
+	var advance fixed.Int26_6
+	for _, output := range s.shape([]rune(text), size) {
+		advance += output.Advance
+	}
+	return toPixels(advance)
+}
+
+// wrap greedily fills lines, keeping explicit line breaks and breaking only where Unicode line
+// breaking rules allow: at spaces, and between characters in scripts such as Chinese and Japanese
+// that are written without them. Segments wider than a whole line are split. A blank paragraph is
+// kept as an empty line, but text with nothing to draw has no lines.
+func (s *shaper) wrap(text string, size int, maxWidth float64) []string {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	fits := func(line string) bool {
+		return s.measure(line, size) <= maxWidth
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		// Collapse runs of whitespace, so the only spaces left end break segments
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		s.breaker.Init([]rune(strings.Join(words, " ")))
+		segments := s.breaker.LineIterator()
+
+		var currentLine string
+		for segments.Next() {
+			segment := string(segments.Line().Text)
+			if fits(strings.TrimSuffix(currentLine+segment, " ")) {
+				currentLine += segment
+				continue
+			}
+
+			if currentLine != "" {
+				lines = append(lines, strings.TrimSuffix(currentLine, " "))
+			}
+
+			// Break segments that cannot fit on a line of their own
+			word, space := strings.CutSuffix(segment, " ")
+			pieces := splitToFit(word, fits)
+			lines = append(lines, pieces[:len(pieces)-1]...)
+			currentLine = pieces[len(pieces)-1]
+			if space {
+				currentLine += " "
+			}
+		}
+
+		if currentLine != "" {
+			lines = append(lines, strings.TrimSuffix(currentLine, " "))
+		}
+	}
+
+	return lines
+}
+
+// splitToFit splits a word between grapheme clusters into pieces that fit, always putting at least
+// one cluster per piece, so a character and the accents or joiners that go with it stay together
+func splitToFit(word string, fits func(string) bool) []string {
+	if fits(word) {
+		return []string{word}
+	}
+
+	var graphemes segmenter.Segmenter
+	graphemes.Init([]rune(word))
+	iter := graphemes.GraphemeIterator()
+
+	var pieces []string
+	var current []rune
+	for iter.Next() {
+		grapheme := iter.Grapheme().Text
+		if len(current) > 0 && !fits(string(current)+string(grapheme)) {
+			pieces = append(pieces, string(current))
+			current = current[:0]
+		}
+		current = append(current, grapheme...)
+	}
+	return append(pieces, string(current))
+}
+
+// toPixels converts a 26.6 fixed-point length to pixels
+func toPixels(v fixed.Int26_6) float64 {
+	return float64(v) / 64
+}
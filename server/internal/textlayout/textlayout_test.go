@@ -0,0 +1,59 @@
+package textlayout
+
+import (
+	"slices"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLayoutKeepsBlankLines(t *testing.T) {
+	font, err := LoadFont("../../TikTokDisplay-Medium.ttf")
+	if err != nil {
+		t.Fatalf("failed to load font: %v", err)
+	}
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "one\n\ntwo", want: []string{"one", "", "two"}},
+		{text: "one\n \n\ntwo", want: []string{"one", "", "", "two"}},
+		{text: "  one   two  ", want: []string{"one two"}},
+		{text: "", want: nil},
+		{text: "\n \n", want: nil},
+	}
+
+	for _, tt := range tests {
+		layout, err := font.Layout(tt.text, Options{FontSize: 36, MaxWidth: 1000})
+		if err != nil {
+			t.Fatalf("Layout(%q) returned error: %v", tt.text, err)
+		}
+		if !slices.Equal(layout.Lines, tt.want) {
+			t.Errorf("Layout(%q) lines = %q, want %q", tt.text, layout.Lines, tt.want)
+		}
+	}
+}
+
+func TestSplitToFitKeepsGraphemeClustersTogether(t *testing.T) {
+	// Pretend a line holds three runes, so splitting between runes would cut clusters apart
+	fits := func(s string) bool {
+		return utf8.RuneCountInString(s) <= 3
+	}
+
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{word: "abcdefg", want: []string{"abc", "def", "g"}},
+		// e followed by a combining acute accent
+		{word: "e\u0301e\u0301e\u0301", want: []string{"e\u0301", "e\u0301", "e\u0301"}},
+		// A family emoji joined with zero width joiners is wider than a line, so it gets one to itself
+		{word: "a👩\u200d👩\u200d👧b", want: []string{"a", "👩\u200d👩\u200d👧", "b"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitToFit(tt.word, fits); !slices.Equal(got, tt.want) {
+			t.Errorf("splitToFit(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
@@ -1,5 +1,6 @@
 -- name: GetAllVideos :many
 SELECT * FROM ai_avatar_videos
+WHERE unsupported_reason IS NULL
 ORDER BY created_at DESC;
 
 -- name: GetVideoByID :one
@@ -14,9 +15,18 @@ INSERT INTO ai_avatar_videos (
     filename,
     thumbnail_filename,
     duration,
-    file_size
+    file_size,
+    width,
+    height,
+    rotation,
+    frame_rate,
+    video_codec,
+    audio_codec,
+    has_audio,
+    duration_ms,
+    probed_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW()
 ) RETURNING *;
 
 -- name: UpdateVideo :one
@@ -29,6 +39,28 @@ SET
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateVideoMediaMetadata :one
+UPDATE ai_avatar_videos
+SET
+    duration = $2,
+    width = $3,
+    height = $4,
+    rotation = $5,
+    frame_rate = $6,
+    video_codec = $7,
+    audio_codec = $8,
+    has_audio = $9,
+    duration_ms = $10,
+    unsupported_reason = $11,
+    probed_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: GetVideosMissingMediaMetadata :many
+SELECT * FROM ai_avatar_videos
+WHERE probed_at IS NULL
+ORDER BY created_at ASC;
+
 -- name: DeleteVideo :exec
 DELETE FROM ai_avatar_videos
 WHERE id = $1;
@@ -0,0 +1,43 @@
+-- name: CreateBrandKit :one
+INSERT INTO public.brand_kits (user_id, name, font_color, stroke_color, background_color, highlight_color)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetBrandKitsByUserID :many
+SELECT * FROM public.brand_kits
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetBrandKitByID :one
+SELECT * FROM public.brand_kits
+WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateBrandKit :one
+UPDATE public.brand_kits
+SET name = $3, font_color = $4, stroke_color = $5, background_color = $6, highlight_color = $7
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteBrandKit :execrows
+DELETE FROM public.brand_kits
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetBrandKitAssetsByBrandKitIDs :many
+-- sqlc:arg brand_kit_ids uuid[]
+SELECT * FROM public.brand_kit_assets
+WHERE brand_kit_id = ANY(@brand_kit_ids::uuid[]);
+
+-- name: UpsertBrandKitAsset :one
+INSERT INTO public.brand_kit_assets (brand_kit_id, kind, filename, sha256, size_bytes, font_family, licensed)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (brand_kit_id, kind) DO UPDATE
+SET filename = EXCLUDED.filename,
+    sha256 = EXCLUDED.sha256,
+    size_bytes = EXCLUDED.size_bytes,
+    font_family = EXCLUDED.font_family,
+    licensed = EXCLUDED.licensed
+RETURNING *;
+
+-- name: DeleteBrandKitAsset :execrows
+DELETE FROM public.brand_kit_assets
+WHERE brand_kit_id = $1 AND kind = $2;
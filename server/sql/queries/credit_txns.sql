@@ -26,6 +26,17 @@ UPDATE public.credit_txns
 SET status = 'refunded', updated_at = NOW()
 WHERE id = $1;
 
+-- name: CaptureCreditsByRequestID :exec
+UPDATE public.credit_txns
+SET status = 'captured', updated_at = NOW()
+WHERE request_id = $1 AND status = 'reserved';
+
+-- name: MarkReservedTxnRefundedByRequestID :one
+UPDATE public.credit_txns
+SET status = 'refunded', updated_at = NOW()
+WHERE request_id = $1 AND status = 'reserved'
+RETURNING id, user_id, amount;
+
 -- name: GetTxnStatus :one
 SELECT status FROM public.credit_txns WHERE id = $1;
 
@@ -0,0 +1,49 @@
+-- name: CreateHookPromptTemplate :one
+INSERT INTO public.hook_prompt_templates (name, style, active)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetHookPromptTemplates :many
+SELECT * FROM public.hook_prompt_templates
+ORDER BY name ASC;
+
+-- name: GetActiveHookPromptTemplates :many
+SELECT * FROM public.hook_prompt_templates
+WHERE active = true
+ORDER BY name ASC;
+
+-- name: GetHookPromptTemplateByID :one
+SELECT * FROM public.hook_prompt_templates
+WHERE id = $1;
+
+-- name: UpdateHookPromptTemplate :one
+UPDATE public.hook_prompt_templates
+SET name = $2, style = $3, active = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateHookPromptTemplateVersion :one
+INSERT INTO public.hook_prompt_template_versions (template_id, version, instructions, created_by)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetHookPromptTemplateVersionByID :one
+SELECT * FROM public.hook_prompt_template_versions
+WHERE id = $1;
+
+-- name: GetLatestHookPromptTemplateVersion :one
+SELECT * FROM public.hook_prompt_template_versions
+WHERE template_id = $1
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: GetLatestHookPromptTemplateVersions :many
+-- sqlc:arg template_ids uuid[]
+SELECT DISTINCT ON (template_id) * FROM public.hook_prompt_template_versions
+WHERE template_id = ANY(@template_ids::uuid[])
+ORDER BY template_id, version DESC;
+
+-- name: GetHookPromptTemplateVersions :many
+SELECT * FROM public.hook_prompt_template_versions
+WHERE template_id = $1
+ORDER BY version DESC;
@@ -1,11 +1,16 @@
 -- name: CreateHook :one
-INSERT INTO public.hooks (user_id, generation_id, prompt, hook_text, hook_index, credits_used)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO public.hooks (user_id, generation_id, prompt, hook_text, hook_index, credits_used, prompt_template_version_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 
 -- name: CreateHooksBatch :many
-INSERT INTO public.hooks (user_id, generation_id, prompt, hook_text, hook_index, credits_used)
-SELECT $1, $2, $3, unnest($4::text[]), unnest($5::int[]), $6
+INSERT INTO public.hooks (user_id, generation_id, prompt, hook_text, hook_index, credits_used, prompt_template_version_id)
+SELECT $1, $2, $3, unnest($4::text[]), unnest($5::int[]), $6, $7
+RETURNING *;
+
+-- name: CreateHookRefinementsBatch :many
+INSERT INTO public.hooks (user_id, generation_id, prompt, hook_text, hook_index, credits_used, parent_hook_id, refinement_instruction, prompt_template_version_id)
+SELECT @user_id, @generation_id, @prompt, unnest(@hook_texts::text[]), unnest(@hook_indices::int[]), @credits_used, @parent_hook_id, @refinement_instruction, @prompt_template_version_id
 RETURNING *;
 
 -- name: GetHooksByUser :many
@@ -23,6 +28,10 @@ ORDER BY hook_index ASC;
 SELECT * FROM public.hooks
 WHERE id = $1;
 
+-- name: GetUserHookByID :one
+SELECT * FROM public.hooks
+WHERE id = $1 AND user_id = $2;
+
 -- name: DeleteHook :exec
 DELETE FROM public.hooks
 WHERE id = $1 AND user_id = $2;
@@ -37,3 +46,9 @@ RETURNING *;
 -- name: GetUserHookCount :one
 SELECT COUNT(*) FROM public.hooks
 WHERE user_id = $1;
+
+-- name: GetHooksByIDs :many
+-- sqlc:arg hook_ids uuid[]
+-- sqlc:arg user_id uuid
+SELECT * FROM public.hooks
+WHERE id = ANY(@hook_ids::uuid[]) AND user_id = @user_id;
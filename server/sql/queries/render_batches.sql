@@ -0,0 +1,22 @@
+-- name: CreateRenderBatch :one
+INSERT INTO public.render_batches (id, user_id, render_count, credits_charged, render_options)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetRenderBatchByID :one
+SELECT * FROM public.render_batches
+WHERE id = $1;
+
+-- name: GetRenderBatchSummariesByUserID :many
+SELECT
+    sqlc.embed(render_batches),
+    COUNT(v.id) FILTER (WHERE v.status = 'processing') AS processing_count,
+    COUNT(v.id) FILTER (WHERE v.status = 'completed') AS completed_count,
+    COUNT(v.id) FILTER (WHERE v.status = 'failed') AS failed_count,
+    COUNT(v.id) FILTER (WHERE v.status = 'cancelled') AS cancelled_count
+FROM public.render_batches
+LEFT JOIN public.user_generated_videos v ON v.render_batch_id = render_batches.id
+WHERE render_batches.user_id = $1
+GROUP BY render_batches.id
+ORDER BY render_batches.created_at DESC
+LIMIT $2 OFFSET $3;
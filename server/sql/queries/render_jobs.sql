@@ -0,0 +1,68 @@
+-- name: CreateRenderJob :one
+INSERT INTO public.render_jobs (user_generated_video_id)
+VALUES ($1)
+RETURNING *;
+
+-- name: ClaimNextRenderJob :one
+UPDATE public.render_jobs
+SET status = 'running', attempts = attempts + 1, locked_at = NOW(), progress_percent = 0, eta_seconds = NULL, updated_at = NOW()
+WHERE id = (
+    SELECT id FROM public.render_jobs
+    WHERE status = 'queued' AND run_after <= NOW()
+    ORDER BY run_after ASC
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateRenderJobProgress :execrows
+UPDATE public.render_jobs
+SET progress_percent = $2, eta_seconds = $3, locked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND status = 'running';
+
+-- name: HeartbeatRenderJob :execrows
+UPDATE public.render_jobs
+SET locked_at = NOW()
+WHERE id = $1 AND status = 'running';
+
+-- name: GetLatestRenderJobByVideoID :one
+SELECT * FROM public.render_jobs
+WHERE user_generated_video_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: CompleteRenderJob :execrows
+UPDATE public.render_jobs
+SET status = 'completed', locked_at = NULL, last_error = NULL, progress_percent = 100, eta_seconds = 0, updated_at = NOW()
+WHERE id = $1 AND status = 'running';
+
+-- name: FailRenderJob :execrows
+UPDATE public.render_jobs
+SET status = 'failed', locked_at = NULL, last_error = $2, updated_at = NOW()
+WHERE id = $1 AND status = 'running';
+
+-- name: RetryRenderJob :execrows
+UPDATE public.render_jobs
+SET status = 'queued', locked_at = NULL, last_error = $2, run_after = $3, eta_seconds = NULL, updated_at = NOW()
+WHERE id = $1 AND status = 'running';
+
+-- name: CancelRenderJobsForVideo :many
+UPDATE public.render_jobs
+SET status = 'cancelled', locked_at = NULL, eta_seconds = NULL, updated_at = NOW()
+WHERE user_generated_video_id = $1 AND status IN ('queued', 'running')
+RETURNING *;
+
+-- name: RequeueStaleRenderJobs :execrows
+UPDATE public.render_jobs
+SET status = 'queued', locked_at = NULL, updated_at = NOW()
+WHERE status = 'running'
+  AND locked_at < @stale_before::timestamptz
+  AND attempts < @max_attempts::int;
+
+-- name: FailStaleRenderJobs :many
+UPDATE public.render_jobs
+SET status = 'failed', locked_at = NULL, last_error = @last_error::text, updated_at = NOW()
+WHERE status = 'running'
+  AND locked_at < @stale_before::timestamptz
+  AND attempts >= @max_attempts::int
+RETURNING *;
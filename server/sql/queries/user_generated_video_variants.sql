@@ -0,0 +1,23 @@
+-- name: UpsertUserGeneratedVideoVariant :one
+INSERT INTO user_generated_video_variants (
+    user_generated_video_id,
+    profile,
+    video_filename,
+    width,
+    height
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (user_generated_video_id, profile) DO UPDATE
+SET video_filename = EXCLUDED.video_filename, width = EXCLUDED.width, height = EXCLUDED.height, updated_at = NOW()
+RETURNING *;
+
+-- name: GetVariantsByUserGeneratedVideoID :many
+SELECT * FROM user_generated_video_variants
+WHERE user_generated_video_id = $1
+ORDER BY profile ASC;
+
+-- name: GetVariantsByUserGeneratedVideoIDs :many
+SELECT * FROM user_generated_video_variants
+WHERE user_generated_video_id = ANY(@video_ids::uuid[])
+ORDER BY user_generated_video_id, profile ASC;
@@ -6,25 +6,44 @@ INSERT INTO user_generated_videos (
     overlay_text,
     generated_video_filename,
     thumbnail_filename,
-    status
+    status,
+    render_options,
+    render_spec_hash,
+    render_batch_id,
+    watermarked
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 ) RETURNING *;
 
 -- name: GetUserGeneratedVideoByID :one
 SELECT * FROM user_generated_videos WHERE id = $1;
 
+-- name: GetCompletedUserGeneratedVideoByRenderSpecHash :one
+SELECT * FROM user_generated_videos
+WHERE user_id = $1 AND render_spec_hash = $2 AND status = 'completed'
+ORDER BY created_at DESC
+LIMIT 1;
+
 -- name: GetUserGeneratedVideosByUserID :many
 SELECT * FROM user_generated_videos WHERE user_id = $1 ORDER BY created_at DESC;
 
+-- name: GetUserGeneratedVideosByRenderBatchID :many
+SELECT * FROM user_generated_videos WHERE render_batch_id = $1 ORDER BY created_at ASC, id ASC;
+
 -- name: UpdateUserGeneratedVideoStatus :one
 UPDATE user_generated_videos 
 SET status = $2, error_message = $3, updated_at = NOW()
-WHERE id = $1
+WHERE id = $1 AND status = 'processing'
 RETURNING *;
 
 -- name: UpdateUserGeneratedVideoFilenames :one
 UPDATE user_generated_videos 
-SET generated_video_filename = $2, thumbnail_filename = $3, status = $4, updated_at = NOW()
-WHERE id = $1
+SET
+    generated_video_filename = $2,
+    thumbnail_filename = $3,
+    status = $4,
+    subtitle_srt_filename = $5,
+    subtitle_vtt_filename = $6,
+    updated_at = NOW()
+WHERE id = $1 AND status = 'processing'
 RETURNING *;
@@ -149,10 +149,274 @@ CREATE TABLE public.ai_avatar_videos (
     duration integer,
     file_size bigint,
     created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    width integer,
+    height integer,
+    rotation integer,
+    frame_rate double precision,
+    video_codec text,
+    audio_codec text,
+    has_audio boolean,
+    duration_ms bigint,
+    probed_at timestamp with time zone,
+    unsupported_reason text
+);
+
+
+--
+-- Name: COLUMN ai_avatar_videos.width; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.ai_avatar_videos.width IS 'Coded frame width in pixels, before rotation';
+
+
+--
+-- Name: COLUMN ai_avatar_videos.height; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.ai_avatar_videos.height IS 'Coded frame height in pixels, before rotation';
+
+
+--
+-- Name: COLUMN ai_avatar_videos.rotation; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.ai_avatar_videos.rotation IS 'Display rotation in degrees (0, 90, 180 or 270)';
+
+
+--
+-- Name: COLUMN ai_avatar_videos.frame_rate; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.ai_avatar_videos.frame_rate IS 'Average frames per second of the video stream';
+
+
+--
+-- Name: COLUMN ai_avatar_videos.video_codec; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.ai_avatar_videos.video_codec IS 'Codec of the first video stream, e.g. h264';
+
+
+--
+-- Name: COLUMN ai_avatar_videos.audio_codec; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.ai_avatar_videos.audio_codec IS 'Codec of the first audio stream (null if there is none)';
+
+
+--
+-- Name: COLUMN ai_avatar_videos.has_audio; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.ai_avatar_videos.has_audio IS 'Whether the video has an audio stream';
+
+
+--
+-- Name: COLUMN ai_avatar_videos.duration_ms; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.ai_avatar_videos.duration_ms IS 'Exact duration in milliseconds';
+
+
+--
+-- Name: COLUMN ai_avatar_videos.probed_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.ai_avatar_videos.probed_at IS 'When the metadata was last read with ffprobe (null until probed)';
+
+
+--
+-- Name: COLUMN ai_avatar_videos.unsupported_reason; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.ai_avatar_videos.unsupported_reason IS 'Why the render pipeline cannot use this video (null if it can)';
+
+
+--
+-- Name: brand_kit_assets; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.brand_kit_assets (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    brand_kit_id uuid NOT NULL,
+    kind text NOT NULL,
+    filename text NOT NULL,
+    sha256 text NOT NULL,
+    size_bytes integer NOT NULL,
+    font_family text,
+    licensed boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT brand_kit_assets_kind_check CHECK ((kind = ANY (ARRAY['font'::text, 'logo'::text, 'background'::text]))),
+    CONSTRAINT brand_kit_assets_size_bytes_check CHECK ((size_bytes > 0))
+);
+
+
+--
+-- Name: TABLE brand_kit_assets; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON TABLE public.brand_kit_assets IS 'Font, logo and background image files uploaded to brand kits, at most one of each per kit';
+
+
+--
+-- Name: COLUMN brand_kit_assets.id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kit_assets.id IS 'Unique asset identifier';
+
+
+--
+-- Name: COLUMN brand_kit_assets.brand_kit_id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kit_assets.brand_kit_id IS 'The brand kit the asset belongs to';
+
+
+--
+-- Name: COLUMN brand_kit_assets.kind; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kit_assets.kind IS 'What the asset is: font (TTF/OTF), logo (PNG) or background (PNG/JPEG)';
+
+
+--
+-- Name: COLUMN brand_kit_assets.filename; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kit_assets.filename IS 'Filename of the asset in S3 under brand-kits/fonts/, brand-kits/logos/ or brand-kits/backgrounds/, named by its content hash';
+
+
+--
+-- Name: COLUMN brand_kit_assets.sha256; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kit_assets.sha256 IS 'Hex SHA-256 of the file contents';
+
+
+--
+-- Name: COLUMN brand_kit_assets.size_bytes; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kit_assets.size_bytes IS 'Size of the file in bytes';
+
+
+--
+-- Name: COLUMN brand_kit_assets.font_family; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kit_assets.font_family IS 'Family name read from the font file (null for logos and backgrounds)';
+
+
+--
+-- Name: COLUMN brand_kit_assets.licensed; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kit_assets.licensed IS 'Whether the uploader confirmed they hold a licence to use the font in videos (false for logos and backgrounds)';
+
+
+--
+-- Name: COLUMN brand_kit_assets.created_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kit_assets.created_at IS 'When the asset was first uploaded';
+
+
+--
+-- Name: COLUMN brand_kit_assets.updated_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kit_assets.updated_at IS 'When the asset was last replaced';
+
+
+--
+-- Name: brand_kits; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.brand_kits (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    font_color text,
+    stroke_color text,
+    background_color text,
+    highlight_color text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
     updated_at timestamp with time zone DEFAULT now() NOT NULL
 );
 
 
+--
+-- Name: TABLE brand_kits; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON TABLE public.brand_kits IS 'Saved fonts, colours and logos a user can apply to renders';
+
+
+--
+-- Name: COLUMN brand_kits.id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kits.id IS 'Unique brand kit identifier';
+
+
+--
+-- Name: COLUMN brand_kits.user_id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kits.user_id IS 'The user who owns the brand kit';
+
+
+--
+-- Name: COLUMN brand_kits.name; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kits.name IS 'Name of the brand kit, usually the brand or client it is for';
+
+
+--
+-- Name: COLUMN brand_kits.font_color; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kits.font_color IS 'Overlay text colour as #RRGGBB or #RRGGBBAA (null for the default)';
+
+
+--
+-- Name: COLUMN brand_kits.stroke_color; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kits.stroke_color IS 'Overlay text outline colour as #RRGGBB or #RRGGBBAA (null for the default)';
+
+
+--
+-- Name: COLUMN brand_kits.background_color; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kits.background_color IS 'Colour of the box behind the overlay text as #RRGGBB or #RRGGBBAA (null for no box)';
+
+
+--
+-- Name: COLUMN brand_kits.highlight_color; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kits.highlight_color IS 'Colour of the current word in animated captions as #RRGGBB or #RRGGBBAA (null for the default)';
+
+
+--
+-- Name: COLUMN brand_kits.created_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kits.created_at IS 'When the brand kit was created';
+
+
+--
+-- Name: COLUMN brand_kits.updated_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.brand_kits.updated_at IS 'When the brand kit was last updated';
+
+
 --
 -- Name: credit_txns; Type: TABLE; Schema: public; Owner: -
 --
@@ -165,223 +429,739 @@ CREATE TABLE public.credit_txns (
     status text NOT NULL,
     created_at timestamp with time zone DEFAULT now() NOT NULL,
     updated_at timestamp with time zone DEFAULT now() NOT NULL,
-    CONSTRAINT credit_txns_amount_check CHECK ((amount > 0)),
-    CONSTRAINT credit_txns_status_check CHECK ((status = ANY (ARRAY['reserved'::text, 'captured'::text, 'refunded'::text])))
+    CONSTRAINT credit_txns_amount_check CHECK ((amount > 0)),
+    CONSTRAINT credit_txns_status_check CHECK ((status = ANY (ARRAY['reserved'::text, 'captured'::text, 'refunded'::text])))
+);
+
+
+--
+-- Name: TABLE credit_txns; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON TABLE public.credit_txns IS 'Tracks credit transactions for idempotency and audit purposes';
+
+
+--
+-- Name: COLUMN credit_txns.id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.credit_txns.id IS 'Unique transaction identifier';
+
+
+--
+-- Name: COLUMN credit_txns.user_id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.credit_txns.user_id IS 'User who owns this transaction';
+
+
+--
+-- Name: COLUMN credit_txns.request_id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.credit_txns.request_id IS 'Client-supplied idempotency key to prevent double-charging';
+
+
+--
+-- Name: COLUMN credit_txns.amount; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.credit_txns.amount IS 'Number of credits involved in this transaction (must be > 0)';
+
+
+--
+-- Name: COLUMN credit_txns.status; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.credit_txns.status IS 'Transaction status: reserved (pending), captured (completed), refunded (failed)';
+
+
+--
+-- Name: COLUMN credit_txns.created_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.credit_txns.created_at IS 'When the transaction was first created';
+
+
+--
+-- Name: COLUMN credit_txns.updated_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.credit_txns.updated_at IS 'When the transaction was last updated';
+
+
+--
+-- Name: hook_prompt_template_versions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.hook_prompt_template_versions (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    template_id uuid NOT NULL,
+    version integer NOT NULL,
+    instructions text NOT NULL,
+    created_by uuid,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT hook_prompt_template_versions_version_check CHECK ((version > 0))
+);
+
+
+--
+-- Name: TABLE hook_prompt_template_versions; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON TABLE public.hook_prompt_template_versions IS 'Every version of each prompt template''s instructions; the highest version is the one in use';
+
+
+--
+-- Name: COLUMN hook_prompt_template_versions.id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_template_versions.id IS 'Unique template version identifier';
+
+
+--
+-- Name: COLUMN hook_prompt_template_versions.template_id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_template_versions.template_id IS 'The template this is a version of';
+
+
+--
+-- Name: COLUMN hook_prompt_template_versions.version; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_template_versions.version IS 'Version number, starting at 1 and counting up with each edit';
+
+
+--
+-- Name: COLUMN hook_prompt_template_versions.instructions; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_template_versions.instructions IS 'Instructions and examples given to the model before the fixed output format, topic and hook count';
+
+
+--
+-- Name: COLUMN hook_prompt_template_versions.created_by; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_template_versions.created_by IS 'The admin who wrote this version (null for the starting templates)';
+
+
+--
+-- Name: COLUMN hook_prompt_template_versions.created_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_template_versions.created_at IS 'When this version was written';
+
+
+--
+-- Name: hook_prompt_templates; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.hook_prompt_templates (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    name text NOT NULL,
+    style text NOT NULL,
+    active boolean DEFAULT true NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT hook_prompt_templates_style_check CHECK ((style = ANY (ARRAY['listicle'::text, 'pov'::text, 'question'::text, 'controversial'::text, 'storytime'::text])))
+);
+
+
+--
+-- Name: TABLE hook_prompt_templates; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON TABLE public.hook_prompt_templates IS 'Prompt templates admins write for hook generation, one writing style each';
+
+
+--
+-- Name: COLUMN hook_prompt_templates.id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_templates.id IS 'Unique prompt template identifier';
+
+
+--
+-- Name: COLUMN hook_prompt_templates.name; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_templates.name IS 'Unique name of the template shown to users';
+
+
+--
+-- Name: COLUMN hook_prompt_templates.style; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_templates.style IS 'Style of hook the template writes: listicle, pov, question, controversial or storytime';
+
+
+--
+-- Name: COLUMN hook_prompt_templates.active; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_templates.active IS 'Whether users can generate hooks with the template; templates are deactivated rather than deleted';
+
+
+--
+-- Name: COLUMN hook_prompt_templates.created_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_templates.created_at IS 'When the template was created';
+
+
+--
+-- Name: COLUMN hook_prompt_templates.updated_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hook_prompt_templates.updated_at IS 'When the template was last updated';
+
+
+--
+-- Name: hooks; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.hooks (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    user_id uuid NOT NULL,
+    generation_id uuid NOT NULL,
+    prompt text NOT NULL,
+    hook_text text NOT NULL,
+    hook_index integer NOT NULL,
+    credits_used integer NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    prompt_template_version_id uuid,
+    parent_hook_id uuid,
+    refinement_instruction text,
+    CONSTRAINT hooks_credits_used_check CHECK ((credits_used > 0)),
+    CONSTRAINT hooks_hook_index_check CHECK ((hook_index >= 0))
+);
+
+
+--
+-- Name: TABLE hooks; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON TABLE public.hooks IS 'Stores individual generated hooks for users';
+
+
+--
+-- Name: COLUMN hooks.id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.id IS 'Unique hook identifier';
+
+
+--
+-- Name: COLUMN hooks.user_id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.user_id IS 'User who generated this hook';
+
+
+--
+-- Name: COLUMN hooks.generation_id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.generation_id IS 'Groups hooks from the same generation request';
+
+
+--
+-- Name: COLUMN hooks.prompt; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.prompt IS 'The prompt used to generate this hook';
+
+
+--
+-- Name: COLUMN hooks.hook_text; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.hook_text IS 'The actual hook text';
+
+
+--
+-- Name: COLUMN hooks.hook_index; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.hook_index IS 'Order of this hook within the generation (0-based)';
+
+
+--
+-- Name: COLUMN hooks.credits_used; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.credits_used IS 'Number of credits consumed for this generation';
+
+
+--
+-- Name: COLUMN hooks.created_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.created_at IS 'When the hook was generated';
+
+
+--
+-- Name: COLUMN hooks.updated_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.updated_at IS 'When the record was last updated';
+
+
+--
+-- Name: COLUMN hooks.prompt_template_version_id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.prompt_template_version_id IS 'The prompt template version the hook was generated with (null for the built-in prompt)';
+
+
+--
+-- Name: COLUMN hooks.parent_hook_id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.parent_hook_id IS 'The hook this hook was refined from (null for generated hooks, or once the parent is deleted)';
+
+
+--
+-- Name: COLUMN hooks.refinement_instruction; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.hooks.refinement_instruction IS 'The instruction the parent hook was refined with, such as "shorter" (null for generated hooks)';
+
+
+--
+-- Name: render_batches; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.render_batches (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    user_id uuid NOT NULL,
+    render_count integer NOT NULL,
+    credits_charged integer NOT NULL,
+    render_options jsonb DEFAULT '{}'::jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT render_batches_credits_charged_check CHECK ((credits_charged >= 0)),
+    CONSTRAINT render_batches_render_count_check CHECK ((render_count > 0))
+);
+
+
+--
+-- Name: TABLE render_batches; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON TABLE public.render_batches IS 'Batches of renders requested together, one per hook text and avatar video pair';
+
+
+--
+-- Name: COLUMN render_batches.id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_batches.id IS 'Unique batch identifier';
+
+
+--
+-- Name: COLUMN render_batches.user_id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_batches.user_id IS 'The user who requested the batch';
+
+
+--
+-- Name: COLUMN render_batches.render_count; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_batches.render_count IS 'Number of renders the batch fanned out into';
+
+
+--
+-- Name: COLUMN render_batches.credits_charged; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_batches.credits_charged IS 'Credits held up front for the whole batch';
+
+
+--
+-- Name: COLUMN render_batches.render_options; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_batches.render_options IS 'Render settings shared by every render in the batch, as JSON';
+
+
+--
+-- Name: COLUMN render_batches.created_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_batches.created_at IS 'When the batch was requested';
+
+
+--
+-- Name: COLUMN render_batches.updated_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_batches.updated_at IS 'When the batch was last updated';
+
+
+--
+-- Name: render_jobs; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.render_jobs (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    user_generated_video_id uuid NOT NULL,
+    status text DEFAULT 'queued'::text NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    run_after timestamp with time zone DEFAULT now() NOT NULL,
+    locked_at timestamp with time zone,
+    last_error text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    progress_percent double precision DEFAULT 0 NOT NULL,
+    eta_seconds integer,
+    CONSTRAINT render_jobs_attempts_check CHECK ((attempts >= 0)),
+    CONSTRAINT render_jobs_progress_percent_check CHECK (((progress_percent >= (0)::double precision) AND (progress_percent <= (100)::double precision))),
+    CONSTRAINT render_jobs_status_check CHECK ((status = ANY (ARRAY['queued'::text, 'running'::text, 'completed'::text, 'failed'::text, 'cancelled'::text])))
+);
+
+
+--
+-- Name: TABLE render_jobs; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON TABLE public.render_jobs IS 'Queue of background render jobs for user-generated videos';
+
+
+--
+-- Name: COLUMN render_jobs.id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_jobs.id IS 'Unique job identifier';
+
+
+--
+-- Name: COLUMN render_jobs.user_generated_video_id; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_jobs.user_generated_video_id IS 'The user-generated video this job renders';
+
+
+--
+-- Name: COLUMN render_jobs.status; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_jobs.status IS 'Job status: queued, running, completed, failed or cancelled';
+
+
+--
+-- Name: COLUMN render_jobs.attempts; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_jobs.attempts IS 'Number of times a worker has picked up this job';
+
+
+--
+-- Name: COLUMN render_jobs.run_after; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_jobs.run_after IS 'Earliest time a worker may pick up this job';
+
+
+--
+-- Name: COLUMN render_jobs.locked_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_jobs.locked_at IS 'When the worker running this job last checked in (null while queued); jobs that stop checking in are requeued';
+
+
+--
+-- Name: COLUMN render_jobs.last_error; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_jobs.last_error IS 'Error from the most recent failed attempt';
+
+
+--
+-- Name: COLUMN render_jobs.created_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_jobs.created_at IS 'When the job was enqueued';
+
+
+--
+-- Name: COLUMN render_jobs.updated_at; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_jobs.updated_at IS 'When the job was last updated';
+
+
+--
+-- Name: COLUMN render_jobs.progress_percent; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_jobs.progress_percent IS 'Percent of the source video encoded so far (0-100), parsed from ffmpeg -progress output';
+
+
+--
+-- Name: COLUMN render_jobs.eta_seconds; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.render_jobs.eta_seconds IS 'Estimated seconds until the encode finishes (null until known)';
+
+
+--
+-- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.schema_migrations (
+    version character varying(255) NOT NULL,
+    applied_at timestamp with time zone DEFAULT now()
+);
+
+
+--
+-- Name: user_accounts; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.user_accounts (
+    id uuid NOT NULL,
+    plan text DEFAULT 'free'::text NOT NULL,
+    plan_started_at timestamp with time zone DEFAULT now() NOT NULL,
+    plan_ends_at timestamp with time zone,
+    billing_customer_id text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    credits integer DEFAULT 100 NOT NULL,
+    CONSTRAINT user_accounts_credits_check CHECK ((credits >= 0)),
+    CONSTRAINT user_accounts_plan_check CHECK ((plan = ANY (ARRAY['free'::text, 'pro'::text, 'enterprise'::text])))
+);
+
+
+--
+-- Name: COLUMN user_accounts.credits; Type: COMMENT; Schema: public; Owner: -
+--
+
+COMMENT ON COLUMN public.user_accounts.credits IS 'Number of credits available to the user (must be >= 0, default 100)';
+
+
+--
+-- Name: user_generated_video_variants; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.user_generated_video_variants (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    user_generated_video_id uuid NOT NULL,
+    profile text NOT NULL,
+    video_filename character varying(255) NOT NULL,
+    width integer NOT NULL,
+    height integer NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT user_generated_video_variants_height_check CHECK ((height > 0)),
+    CONSTRAINT user_generated_video_variants_width_check CHECK ((width > 0))
 );
 
 
 --
--- Name: TABLE credit_txns; Type: COMMENT; Schema: public; Owner: -
+-- Name: TABLE user_generated_video_variants; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON TABLE public.credit_txns IS 'Tracks credit transactions for idempotency and audit purposes';
+COMMENT ON TABLE public.user_generated_video_variants IS 'Per-platform renders of a user-generated video';
 
 
 --
--- Name: COLUMN credit_txns.id; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_video_variants.id; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.credit_txns.id IS 'Unique transaction identifier';
+COMMENT ON COLUMN public.user_generated_video_variants.id IS 'Unique variant identifier';
 
 
 --
--- Name: COLUMN credit_txns.user_id; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_video_variants.user_generated_video_id; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.credit_txns.user_id IS 'User who owns this transaction';
+COMMENT ON COLUMN public.user_generated_video_variants.user_generated_video_id IS 'The user-generated video this is a variant of';
 
 
 --
--- Name: COLUMN credit_txns.request_id; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_video_variants.profile; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.credit_txns.request_id IS 'Client-supplied idempotency key to prevent double-charging';
+COMMENT ON COLUMN public.user_generated_video_variants.profile IS 'Name of the output profile the variant was rendered with';
 
 
 --
--- Name: COLUMN credit_txns.amount; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_video_variants.video_filename; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.credit_txns.amount IS 'Number of credits involved in this transaction (must be > 0)';
+COMMENT ON COLUMN public.user_generated_video_variants.video_filename IS 'S3 filename of the rendered variant';
 
 
 --
--- Name: COLUMN credit_txns.status; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_video_variants.width; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.credit_txns.status IS 'Transaction status: reserved (pending), captured (completed), refunded (failed)';
+COMMENT ON COLUMN public.user_generated_video_variants.width IS 'Output width in pixels';
 
 
 --
--- Name: COLUMN credit_txns.created_at; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_video_variants.height; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.credit_txns.created_at IS 'When the transaction was first created';
+COMMENT ON COLUMN public.user_generated_video_variants.height IS 'Output height in pixels';
 
 
 --
--- Name: COLUMN credit_txns.updated_at; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_video_variants.created_at; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.credit_txns.updated_at IS 'When the transaction was last updated';
+COMMENT ON COLUMN public.user_generated_video_variants.created_at IS 'When the variant was first rendered';
 
 
 --
--- Name: hooks; Type: TABLE; Schema: public; Owner: -
+-- Name: COLUMN user_generated_video_variants.updated_at; Type: COMMENT; Schema: public; Owner: -
 --
 
-CREATE TABLE public.hooks (
-    id uuid DEFAULT gen_random_uuid() NOT NULL,
-    user_id uuid NOT NULL,
-    generation_id uuid NOT NULL,
-    prompt text NOT NULL,
-    hook_text text NOT NULL,
-    hook_index integer NOT NULL,
-    credits_used integer NOT NULL,
-    created_at timestamp with time zone DEFAULT now() NOT NULL,
-    updated_at timestamp with time zone DEFAULT now() NOT NULL,
-    CONSTRAINT hooks_credits_used_check CHECK ((credits_used > 0)),
-    CONSTRAINT hooks_hook_index_check CHECK ((hook_index >= 0))
-);
+COMMENT ON COLUMN public.user_generated_video_variants.updated_at IS 'When the variant was last rendered';
 
 
 --
--- Name: TABLE hooks; Type: COMMENT; Schema: public; Owner: -
+-- Name: user_generated_videos; Type: TABLE; Schema: public; Owner: -
 --
 
-COMMENT ON TABLE public.hooks IS 'Stores individual generated hooks for users';
+CREATE TABLE public.user_generated_videos (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    user_id uuid NOT NULL,
+    ai_avatar_video_id uuid NOT NULL,
+    overlay_text text NOT NULL,
+    generated_video_filename character varying(255) NOT NULL,
+    thumbnail_filename character varying(255) NOT NULL,
+    status character varying(20) DEFAULT 'processing'::character varying,
+    error_message text,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now(),
+    render_options jsonb DEFAULT '{}'::jsonb NOT NULL,
+    render_spec_hash text,
+    subtitle_srt_filename text,
+    subtitle_vtt_filename text,
+    render_batch_id uuid,
+    watermarked boolean DEFAULT false NOT NULL
+);
 
 
 --
--- Name: COLUMN hooks.id; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_videos.render_options; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.hooks.id IS 'Unique hook identifier';
+COMMENT ON COLUMN public.user_generated_videos.render_options IS 'Per-request render settings such as overlay style, as JSON';
 
 
 --
--- Name: COLUMN hooks.user_id; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_videos.render_spec_hash; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.hooks.user_id IS 'User who generated this hook';
+COMMENT ON COLUMN public.user_generated_videos.render_spec_hash IS 'SHA-256 of the canonical render spec (source video, text, style, profiles and renderer version)';
 
 
 --
--- Name: COLUMN hooks.generation_id; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_videos.subtitle_srt_filename; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.hooks.generation_id IS 'Groups hooks from the same generation request';
+COMMENT ON COLUMN public.user_generated_videos.subtitle_srt_filename IS 'SRT sidecar of the overlay text in S3 (null unless soft subtitles were requested)';
 
 
 --
--- Name: COLUMN hooks.prompt; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_videos.subtitle_vtt_filename; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.hooks.prompt IS 'The prompt used to generate this hook';
+COMMENT ON COLUMN public.user_generated_videos.subtitle_vtt_filename IS 'WebVTT sidecar of the overlay text in S3 (null unless soft subtitles were requested)';
 
 
 --
--- Name: COLUMN hooks.hook_text; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_videos.render_batch_id; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.hooks.hook_text IS 'The actual hook text';
+COMMENT ON COLUMN public.user_generated_videos.render_batch_id IS 'The render batch this video was rendered in (null for single renders)';
 
 
 --
--- Name: COLUMN hooks.hook_index; Type: COMMENT; Schema: public; Owner: -
+-- Name: COLUMN user_generated_videos.watermarked; Type: COMMENT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.hooks.hook_index IS 'Order of this hook within the generation (0-based)';
+COMMENT ON COLUMN public.user_generated_videos.watermarked IS 'Whether the render carries the free plan watermark, decided from the user''s plan when it was queued';
 
 
 --
--- Name: COLUMN hooks.credits_used; Type: COMMENT; Schema: public; Owner: -
+-- Name: brand_kit_assets brand_kit_assets_brand_kit_id_kind_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.hooks.credits_used IS 'Number of credits consumed for this generation';
+ALTER TABLE ONLY public.brand_kit_assets
+    ADD CONSTRAINT brand_kit_assets_brand_kit_id_kind_key UNIQUE (brand_kit_id, kind);
 
 
 --
--- Name: COLUMN hooks.created_at; Type: COMMENT; Schema: public; Owner: -
+-- Name: brand_kit_assets brand_kit_assets_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.hooks.created_at IS 'When the hook was generated';
+ALTER TABLE ONLY public.brand_kit_assets
+    ADD CONSTRAINT brand_kit_assets_pkey PRIMARY KEY (id);
 
 
 --
--- Name: COLUMN hooks.updated_at; Type: COMMENT; Schema: public; Owner: -
+-- Name: brand_kits brand_kits_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.hooks.updated_at IS 'When the record was last updated';
+ALTER TABLE ONLY public.brand_kits
+    ADD CONSTRAINT brand_kits_pkey PRIMARY KEY (id);
 
 
 --
--- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
+-- Name: credit_txns credit_txns_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE TABLE public.schema_migrations (
-    version character varying(255) NOT NULL,
-    applied_at timestamp with time zone DEFAULT now()
-);
+ALTER TABLE ONLY public.credit_txns
+    ADD CONSTRAINT credit_txns_pkey PRIMARY KEY (id);
 
 
 --
--- Name: user_accounts; Type: TABLE; Schema: public; Owner: -
+-- Name: credit_txns credit_txns_request_id_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE TABLE public.user_accounts (
-    id uuid NOT NULL,
-    plan text DEFAULT 'free'::text NOT NULL,
-    plan_started_at timestamp with time zone DEFAULT now() NOT NULL,
-    plan_ends_at timestamp with time zone,
-    billing_customer_id text,
-    created_at timestamp with time zone DEFAULT now() NOT NULL,
-    updated_at timestamp with time zone DEFAULT now() NOT NULL,
-    credits integer DEFAULT 100 NOT NULL,
-    CONSTRAINT user_accounts_credits_check CHECK ((credits >= 0)),
-    CONSTRAINT user_accounts_plan_check CHECK ((plan = ANY (ARRAY['free'::text, 'pro'::text, 'enterprise'::text])))
-);
+ALTER TABLE ONLY public.credit_txns
+    ADD CONSTRAINT credit_txns_request_id_key UNIQUE (request_id);
 
 
 --
--- Name: COLUMN user_accounts.credits; Type: COMMENT; Schema: public; Owner: -
+-- Name: hook_prompt_template_versions hook_prompt_template_versions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-COMMENT ON COLUMN public.user_accounts.credits IS 'Number of credits available to the user (must be >= 0, default 100)';
+ALTER TABLE ONLY public.hook_prompt_template_versions
+    ADD CONSTRAINT hook_prompt_template_versions_pkey PRIMARY KEY (id);
 
 
 --
--- Name: user_generated_videos; Type: TABLE; Schema: public; Owner: -
+-- Name: hook_prompt_template_versions hook_prompt_template_versions_template_id_version_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE TABLE public.user_generated_videos (
-    id uuid DEFAULT gen_random_uuid() NOT NULL,
-    user_id uuid NOT NULL,
-    ai_avatar_video_id uuid NOT NULL,
-    overlay_text text NOT NULL,
-    generated_video_filename character varying(255) NOT NULL,
-    thumbnail_filename character varying(255) NOT NULL,
-    status character varying(20) DEFAULT 'processing'::character varying,
-    error_message text,
-    created_at timestamp without time zone DEFAULT now(),
-    updated_at timestamp without time zone DEFAULT now()
-);
+ALTER TABLE ONLY public.hook_prompt_template_versions
+    ADD CONSTRAINT hook_prompt_template_versions_template_id_version_key UNIQUE (template_id, version);
 
 
 --
--- Name: credit_txns credit_txns_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: hook_prompt_templates hook_prompt_templates_name_key; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.credit_txns
-    ADD CONSTRAINT credit_txns_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.hook_prompt_templates
+    ADD CONSTRAINT hook_prompt_templates_name_key UNIQUE (name);
 
 
 --
--- Name: credit_txns credit_txns_request_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: hook_prompt_templates hook_prompt_templates_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.credit_txns
-    ADD CONSTRAINT credit_txns_request_id_key UNIQUE (request_id);
+ALTER TABLE ONLY public.hook_prompt_templates
+    ADD CONSTRAINT hook_prompt_templates_pkey PRIMARY KEY (id);
 
 
 --
@@ -392,6 +1172,22 @@ ALTER TABLE ONLY public.hooks
     ADD CONSTRAINT hooks_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: render_batches render_batches_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.render_batches
+    ADD CONSTRAINT render_batches_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: render_jobs render_jobs_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.render_jobs
+    ADD CONSTRAINT render_jobs_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -408,6 +1204,22 @@ ALTER TABLE ONLY public.user_accounts
     ADD CONSTRAINT user_accounts_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: user_generated_video_variants user_generated_video_variants_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_generated_video_variants
+    ADD CONSTRAINT user_generated_video_variants_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: user_generated_video_variants user_generated_video_variants_video_profile_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_generated_video_variants
+    ADD CONSTRAINT user_generated_video_variants_video_profile_key UNIQUE (user_generated_video_id, profile);
+
+
 --
 -- Name: user_generated_videos user_generated_videos_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -438,6 +1250,13 @@ CREATE INDEX idx_ai_avatar_videos_created_at ON public.ai_avatar_videos USING bt
 CREATE INDEX idx_ai_avatar_videos_title ON public.ai_avatar_videos USING btree (title);
 
 
+--
+-- Name: idx_brand_kits_user_id_created_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_brand_kits_user_id_created_at ON public.brand_kits USING btree (user_id, created_at);
+
+
 --
 -- Name: idx_credit_txns_created_at; Type: INDEX; Schema: public; Owner: -
 --
@@ -473,6 +1292,20 @@ CREATE INDEX idx_hooks_created_at ON public.hooks USING btree (created_at);
 CREATE INDEX idx_hooks_generation_id ON public.hooks USING btree (generation_id);
 
 
+--
+-- Name: idx_hooks_parent_hook_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_hooks_parent_hook_id ON public.hooks USING btree (parent_hook_id);
+
+
+--
+-- Name: idx_hooks_prompt_template_version_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_hooks_prompt_template_version_id ON public.hooks USING btree (prompt_template_version_id);
+
+
 --
 -- Name: idx_hooks_user_id; Type: INDEX; Schema: public; Owner: -
 --
@@ -480,6 +1313,27 @@ CREATE INDEX idx_hooks_generation_id ON public.hooks USING btree (generation_id)
 CREATE INDEX idx_hooks_user_id ON public.hooks USING btree (user_id);
 
 
+--
+-- Name: idx_render_batches_user_id_created_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_render_batches_user_id_created_at ON public.render_batches USING btree (user_id, created_at);
+
+
+--
+-- Name: idx_render_jobs_status_run_after; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_render_jobs_status_run_after ON public.render_jobs USING btree (status, run_after);
+
+
+--
+-- Name: idx_render_jobs_user_generated_video_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_render_jobs_user_generated_video_id ON public.render_jobs USING btree (user_generated_video_id);
+
+
 --
 -- Name: idx_user_generated_videos_ai_avatar_video_id; Type: INDEX; Schema: public; Owner: -
 --
@@ -494,6 +1348,13 @@ CREATE INDEX idx_user_generated_videos_ai_avatar_video_id ON public.user_generat
 CREATE INDEX idx_user_generated_videos_created_at ON public.user_generated_videos USING btree (created_at);
 
 
+--
+-- Name: idx_user_generated_videos_render_batch_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_user_generated_videos_render_batch_id ON public.user_generated_videos USING btree (render_batch_id);
+
+
 --
 -- Name: idx_user_generated_videos_status; Type: INDEX; Schema: public; Owner: -
 --
@@ -508,6 +1369,13 @@ CREATE INDEX idx_user_generated_videos_status ON public.user_generated_videos US
 CREATE INDEX idx_user_generated_videos_user_id ON public.user_generated_videos USING btree (user_id);
 
 
+--
+-- Name: idx_user_generated_videos_user_render_spec_hash; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_user_generated_videos_user_render_spec_hash ON public.user_generated_videos USING btree (user_id, render_spec_hash) WHERE ((status)::text = 'completed'::text);
+
+
 --
 -- Name: ai_avatar_videos set_updated_at_ai_avatar_videos; Type: TRIGGER; Schema: public; Owner: -
 --
@@ -515,6 +1383,41 @@ CREATE INDEX idx_user_generated_videos_user_id ON public.user_generated_videos U
 CREATE TRIGGER set_updated_at_ai_avatar_videos BEFORE UPDATE ON public.ai_avatar_videos FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
 
 
+--
+-- Name: brand_kit_assets set_updated_at_brand_kit_assets; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER set_updated_at_brand_kit_assets BEFORE UPDATE ON public.brand_kit_assets FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+
+--
+-- Name: brand_kits set_updated_at_brand_kits; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER set_updated_at_brand_kits BEFORE UPDATE ON public.brand_kits FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+
+--
+-- Name: hook_prompt_templates set_updated_at_hook_prompt_templates; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER set_updated_at_hook_prompt_templates BEFORE UPDATE ON public.hook_prompt_templates FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+
+--
+-- Name: render_batches set_updated_at_render_batches; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER set_updated_at_render_batches BEFORE UPDATE ON public.render_batches FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+
+--
+-- Name: render_jobs set_updated_at_render_jobs; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER set_updated_at_render_jobs BEFORE UPDATE ON public.render_jobs FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+
 --
 -- Name: user_accounts set_updated_at_user_accounts; Type: TRIGGER; Schema: public; Owner: -
 --
@@ -522,6 +1425,13 @@ CREATE TRIGGER set_updated_at_ai_avatar_videos BEFORE UPDATE ON public.ai_avatar
 CREATE TRIGGER set_updated_at_user_accounts BEFORE UPDATE ON public.user_accounts FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
 
 
+--
+-- Name: user_generated_video_variants set_updated_at_user_generated_video_variants; Type: TRIGGER; Schema: public; Owner: -
+--
+
+CREATE TRIGGER set_updated_at_user_generated_video_variants BEFORE UPDATE ON public.user_generated_video_variants FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
+
+
 --
 -- Name: user_generated_videos set_updated_at_user_generated_videos; Type: TRIGGER; Schema: public; Owner: -
 --
@@ -529,6 +1439,22 @@ CREATE TRIGGER set_updated_at_user_accounts BEFORE UPDATE ON public.user_account
 CREATE TRIGGER set_updated_at_user_generated_videos BEFORE UPDATE ON public.user_generated_videos FOR EACH ROW EXECUTE FUNCTION public.tg_set_updated_at();
 
 
+--
+-- Name: brand_kit_assets brand_kit_assets_brand_kit_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.brand_kit_assets
+    ADD CONSTRAINT brand_kit_assets_brand_kit_id_fkey FOREIGN KEY (brand_kit_id) REFERENCES public.brand_kits(id) ON DELETE CASCADE;
+
+
+--
+-- Name: brand_kits brand_kits_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.brand_kits
+    ADD CONSTRAINT brand_kits_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.user_accounts(id) ON DELETE CASCADE;
+
+
 --
 -- Name: credit_txns credit_txns_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -537,6 +1463,38 @@ ALTER TABLE ONLY public.credit_txns
     ADD CONSTRAINT credit_txns_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.user_accounts(id) ON DELETE CASCADE;
 
 
+--
+-- Name: hook_prompt_template_versions hook_prompt_template_versions_created_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.hook_prompt_template_versions
+    ADD CONSTRAINT hook_prompt_template_versions_created_by_fkey FOREIGN KEY (created_by) REFERENCES public.user_accounts(id) ON DELETE SET NULL;
+
+
+--
+-- Name: hook_prompt_template_versions hook_prompt_template_versions_template_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.hook_prompt_template_versions
+    ADD CONSTRAINT hook_prompt_template_versions_template_id_fkey FOREIGN KEY (template_id) REFERENCES public.hook_prompt_templates(id) ON DELETE CASCADE;
+
+
+--
+-- Name: hooks hooks_parent_hook_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.hooks
+    ADD CONSTRAINT hooks_parent_hook_id_fkey FOREIGN KEY (parent_hook_id) REFERENCES public.hooks(id) ON DELETE SET NULL;
+
+
+--
+-- Name: hooks hooks_prompt_template_version_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.hooks
+    ADD CONSTRAINT hooks_prompt_template_version_id_fkey FOREIGN KEY (prompt_template_version_id) REFERENCES public.hook_prompt_template_versions(id);
+
+
 --
 -- Name: hooks hooks_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -545,6 +1503,23 @@ ALTER TABLE ONLY public.hooks
     ADD CONSTRAINT hooks_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.user_accounts(id) ON DELETE CASCADE;
 
 
+--
+-- Name: render_batches render_batches_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.render_batches
+    ADD CONSTRAINT render_batches_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.user_accounts(id) ON DELETE CASCADE;
+
+
+--
+-- Name: render_jobs render_jobs_user_generated_video_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.render_jobs
+    ADD CONSTRAINT render_jobs_user_generated_video_id_fkey FOREIGN KEY (user_generated_video_id) REFERENCES public.user_generated_videos(id) ON DELETE CASCADE;
+
+
+
 --
 -- Name: user_accounts user_accounts_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -553,6 +1528,15 @@ ALTER TABLE ONLY public.user_accounts
     ADD CONSTRAINT user_accounts_id_fkey FOREIGN KEY (id) REFERENCES auth.users(id) ON DELETE CASCADE;
 
 
+--
+-- Name: user_generated_video_variants user_generated_video_variants_user_generated_video_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_generated_video_variants
+    ADD CONSTRAINT user_generated_video_variants_user_generated_video_id_fkey FOREIGN KEY (user_generated_video_id) REFERENCES public.user_generated_videos(id) ON DELETE CASCADE;
+
+
+
 --
 -- Name: user_generated_videos user_generated_videos_ai_avatar_video_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -561,6 +1545,14 @@ ALTER TABLE ONLY public.user_generated_videos
     ADD CONSTRAINT user_generated_videos_ai_avatar_video_id_fkey FOREIGN KEY (ai_avatar_video_id) REFERENCES public.ai_avatar_videos(id);
 
 
+--
+-- Name: user_generated_videos user_generated_videos_render_batch_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_generated_videos
+    ADD CONSTRAINT user_generated_videos_render_batch_id_fkey FOREIGN KEY (render_batch_id) REFERENCES public.render_batches(id) ON DELETE SET NULL;
+
+
 --
 -- Name: user_generated_videos user_generated_videos_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --